@@ -4,7 +4,9 @@ import (
 	_ "app/api/docs"
 	"app/api/handler"
 	"app/config"
+	"app/pkg/blobstore"
 	"app/pkg/logger"
+	"app/pkg/mailer"
 	"app/storage"
 
 	"github.com/gin-gonic/gin"
@@ -12,118 +14,137 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewApi(r *gin.Engine, cfg *config.Config, storage storage.StorageI, logger logger.LoggerI) {
+func NewApi(r *gin.Engine, cfg *config.Config, storage storage.StorageI, logger logger.LoggerI, mailer mailer.Mailer, blobs blobstore.Store) {
 
 	// @securityDefinitions.apikey ApiKeyAuth
 	// @in header
 	// @name Authorization
 
-	handler := handler.NewHandler(cfg, storage, logger)
+	handler := handler.NewHandler(cfg, storage, logger, mailer, blobs)
 
 	r.Use(customCORSMiddleware())
-	// v1 := r.Group("/v1")
-	// v1.Use(handler.AuthMiddleware())
 
-	r.POST("/login", handler.Login)
-	r.POST("/register", handler.Register)
-
-	//uploading
-	// r.Static("/uploads", "./uploads")
-	// r.GET("/download/:filename", handler.DownloadFileHandler)
-	// r.POST("/uploadd", handler.UploadHandler)
-	// r.GET("/images", handler.ListImagesHandler)
-	// r.GET("/image/:filename", handler.GetImageHandler)
+	// Route groups: public endpoints, public endpoints that know the caller
+	// when a token is sent, endpoints for any authenticated caller and
+	// admin-only endpoints
+	public := r.Group("/")
+	viewer := r.Group("/", handler.OptionalAuthMiddleware())
+	authorized := r.Group("/", handler.AuthMiddleware())
+	admin := r.Group("/", handler.AuthMiddleware(), handler.RoleMiddleware(config.RoleAdmin))
+
+	public.POST("/login", handler.Login)
+	public.POST("/register", handler.Register)
+	public.POST("/register/verify", handler.VerifyEmail)
+	public.POST("/register/resend", handler.ResendVerification)
+	public.POST("/password/forgot", handler.ForgotPassword)
+	public.POST("/password/reset", handler.ResetPassword)
+	public.POST("/refresh", handler.Refresh)
+	authorized.POST("/logout", handler.Logout)
+	authorized.POST("/logout/all", handler.LogoutAll)
 
 	//SisAmin
-	r.POST("/admin", handler.CreateAdmin)
-	r.GET("/admin/:id", handler.GetByIdAdmin)
-	r.GET("/admin", handler.GetListAdmin)
-	r.PUT("/admin/:id", handler.UpdateAdmin)
-	r.DELETE("/admin/:id", handler.DeleteAdmin)
+	admin.POST("/admin", handler.CreateAdmin)
+	admin.GET("/admin/:id", handler.GetByIdAdmin)
+	admin.GET("/admin", handler.GetListAdmin)
+	admin.PUT("/admin/:id", handler.UpdateAdmin)
+	admin.DELETE("/admin/:id", handler.DeleteAdmin)
+	admin.POST("/admin/gc", handler.CollectGarbage)
 
 	// User
-	// r.POST("/user", handler.CreateUser)
-	r.GET("/user/:id", handler.GetByIdUser)
-	r.GET("/user", handler.GetListUser)
-	r.PUT("/user/:id", handler.UpdateUser)
-	r.DELETE("/user/:id", handler.DeleteUser)
-	r.GET("/get_user_activity_counts", handler.GetUserActivityCounts)
-	r.GET("/top_contributors", handler.GetTopContributors)
-	r.GET("/users/scores", handler.GetUserScores)
-	r.GET("/users/{user_id}/rank", handler.GetUserRank)
-	r.GET("/users/statistics", handler.GetUserStatistics)
+	authorized.GET("/user/:id", handler.GetByIdUser)
+	authorized.GET("/user", handler.GetListUser)
+	authorized.PUT("/user/:id", handler.UpdateUser)
+	admin.DELETE("/user/:id", handler.DeleteUser)
+	public.GET("/get_user_activity_counts", handler.GetUserActivityCounts)
+	public.GET("/top_contributors", handler.GetTopContributors)
+	public.GET("/users/scores", handler.GetUserScores)
+	public.GET("/users/{user_id}/rank", handler.GetUserRank)
+	public.GET("/users/statistics", handler.GetUserStatistics)
 
 	// Course
-	r.POST("/course", handler.CreateCourse)
-	r.GET("/course/:id", handler.GetByIdCourse)
-	r.GET("/course", handler.GetListCourse)
-	r.PUT("/course/:id", handler.UpdateCourse)
-	r.DELETE("/course/:id", handler.DeleteCourse)
-	r.GET("/courses_by_semester_id", handler.GetListCoursesBySemesterId)
+	admin.POST("/course", handler.CreateCourse)
+	public.GET("/course/:id", handler.GetByIdCourse)
+	public.GET("/course", handler.GetListCourse)
+	admin.PUT("/course/:id", handler.UpdateCourse)
+	admin.DELETE("/course/:id", handler.DeleteCourse)
+	public.GET("/courses_by_semester_id", handler.GetListCoursesBySemesterId)
 
 	// Semester
-	r.POST("/semester", handler.CreateSemester)
-	r.GET("/semester/:id", handler.GetByIdSemester)
-	r.GET("/semester", handler.GetListSemester)
-	r.PUT("/semester/:id", handler.UpdateSemester)
-	r.DELETE("/semester/:id", handler.DeleteSemester)
+	admin.POST("/semester", handler.CreateSemester)
+	public.GET("/semester/:id", handler.GetByIdSemester)
+	public.GET("/semester", handler.GetListSemester)
+	admin.PUT("/semester/:id", handler.UpdateSemester)
+	admin.DELETE("/semester/:id", handler.DeleteSemester)
 	// Like
-	r.POST("/like", handler.CreateLike)
-	r.GET("/like/:id", handler.GetByIdLike)
-	r.GET("/like", handler.GetListLike)
-	r.PUT("/like/:id", handler.UpdateLike)
-	r.DELETE("/like/:id", handler.DeleteLike)
+	authorized.POST("/publication/:id/like", handler.LikePublication)
+	authorized.DELETE("/publication/:id/like", handler.UnlikePublication)
+	authorized.GET("/like/:id", handler.GetByIdLike)
+	authorized.GET("/like", handler.GetListLike)
+	authorized.DELETE("/like/:id", handler.DeleteLike)
 	// Download
-	r.POST("/download", handler.CreateDownload)
-	r.GET("/download/:id", handler.GetByIdDownload)
-	r.GET("/download", handler.GetListDownload)
-	r.PUT("/download/:id", handler.UpdateDownload)
-	r.DELETE("/download/:id", handler.DeleteDownload)
+	authorized.GET("/download/:id", handler.GetByIdDownload)
+	authorized.GET("/download", handler.GetListDownload)
+	authorized.DELETE("/download/:id", handler.DeleteDownload)
 	// Publication
-	r.POST("/publication", handler.CreatePublication)
-	r.GET("/publication/:id", handler.GetByIdPublication)
-	r.GET("/publication", handler.GetListPublication)
-	r.PUT("/publication/:id", handler.UpdatePublication)
-	r.DELETE("/publication/:id", handler.DeletePublication)
-	r.GET("/get_publication_stats", handler.GetPublicationStats)
-	r.GET("/publications/tags", handler.GetPublicationsByTag)
+	authorized.POST("/publication", handler.CreatePublication)
+	viewer.GET("/publication/:id", handler.GetByIdPublication)
+	viewer.GET("/publication", handler.GetListPublication)
+	viewer.GET("/publication/:id/download-link", handler.GetPublicationDownloadLink)
+	authorized.PUT("/publication/:id", handler.UpdatePublication)
+	authorized.DELETE("/publication/:id", handler.DeletePublication)
+	authorized.POST("/publication/:id/submit", handler.SubmitPublication)
+	authorized.POST("/publication/:id/withdraw", handler.WithdrawPublication)
+	authorized.POST("/publication/:id/archive", handler.ArchivePublication)
+	authorized.GET("/publication/:id/transitions", handler.GetPublicationTransitions)
+	authorized.GET("/publication/:id/revisions", handler.GetPublicationRevisions)
+	authorized.GET("/publication/:id/revisions/diff", handler.GetPublicationRevisionDiff)
+	admin.POST("/admin/publication/:id/approve", handler.ApprovePublication)
+	admin.POST("/admin/publication/:id/reject", handler.RejectPublication)
+	admin.POST("/admin/publication/:id/rollback", handler.RollbackPublication)
+	public.GET("/get_publication_stats", handler.GetPublicationStats)
+	public.GET("/publications/tags", handler.GetPublicationsByTag)
+
+	// Tag
+	public.GET("/tags", handler.GetListTag)
+	admin.PUT("/admin/tags/:id", handler.RenameTag)
+	admin.POST("/admin/tags/merge", handler.MergeTags)
+
+	// Moderation queue
+	admin.GET("/admin/moderation/queue", handler.GetModerationQueue)
+	admin.POST("/admin/moderation/queue/:id/claim", handler.ClaimPublication)
+	admin.DELETE("/admin/moderation/queue/:id/claim", handler.ReleasePublication)
+	admin.POST("/admin/moderation/approve", handler.BulkApprovePublications)
+	admin.POST("/admin/moderation/reject", handler.BulkRejectPublications)
+	admin.GET("/admin/moderation/stats", handler.GetModerationStats)
+
+	// Trash
+	admin.GET("/trash", handler.GetListTrash)
+	admin.POST("/trash/:kind/:id/restore", handler.RestoreTrash)
 
 	// Notification
-	r.POST("/notification", handler.CreateNotification)
-	r.GET("/notification/:id", handler.GetByIdNotification)
-	r.GET("/notification", handler.GetListNotification)
-	r.PUT("/notification/:id", handler.UpdateNotification)
-	r.DELETE("/notification/:id", handler.DeleteNotification)
-	///////////////////////////////////////////////////
-
-	// Worker
-	// r.POST("/worker", handler.AuthMiddleware(), handler.CreateWorker)
-	// r.GET("/worker/:id", handler.AuthMiddleware(), handler.GetByIdWorker)
-	// r.GET("/worker", handler.AuthMiddleware(), handler.GetListWorker)
-	// r.PUT("/worker/:id", handler.AuthMiddleware(), handler.UpdateWorker)
-	// r.DELETE("/worker/:id", handler.AuthMiddleware(), handler.DeleteWorker)
-	// r.PATCH("/worker/:id", handler.AuthMiddleware(), handler.PatchWorker)
-	//Login
-	// r.POST("/login", handler.Login)
-
-	// r.GET("/petrol_history", handler.AuthMiddleware(), handler.GetListPetrolHistory)
-	// r.GET("/petrol_history_by_car_id", handler.AuthMiddleware(), handler.GetListPetrolHistoryByCarId)
-	// r.GET("/users_by_company_id", handler.AuthMiddleware(), handler.GetListUserByCompanyId)
-
-	// // r.POST("/upload", handler.UploadPhoto)   TO FIrebase
-	// r.Static("/uploads", "./uploads")
-	r.POST("/uploadd", handler.UploadHandler)
-	r.POST("/upload_profile", handler.UploadHandlerProfile)
-	r.GET("/images", handler.ListImagesHandler)
-	r.GET("/image/:filename", handler.GetImageHandler)
-	r.GET("/profile_image/:filename", handler.GetProfileImageHandler)
-	// r.GET("/petrol_history_sum_remaining", handler.AuthMiddleware(), handler.GetSumRemainingPetrolByMonth)
-	// r.GET("/petrol_history_sum_remaining_by_year", handler.AuthMiddleware(), handler.GetSumRemainingPetrolByYear)
-	r.GET("/file_download/:filename", handler.FileDownloadFileHandler)
-	// r.GET("/petrol_history/by_car", handler.GetListPetrolHistoryByCarID)
+	admin.POST("/notification", handler.CreateNotification)
+	authorized.GET("/notification/:id", handler.GetByIdNotification)
+	authorized.GET("/notification", handler.GetListNotification)
+	admin.PUT("/notification/:id", handler.UpdateNotification)
+	admin.DELETE("/notification/:id", handler.DeleteNotification)
+
+	// File
+	authorized.POST("/uploadd", handler.UploadHandler)
+	authorized.POST("/upload_profile", handler.UploadHandlerProfile)
+	public.GET("/images", handler.ListImagesHandler)
+	public.GET("/image/:filename", handler.GetImageHandler)
+	public.GET("/profile_image/:filename", handler.GetProfileImageHandler)
+	viewer.GET("/file_download/:filename", handler.FileDownloadFileHandler)
+
+	// Resumable uploads (tus)
+	authorized.POST("/uploads", handler.CreateResumableUpload)
+	authorized.HEAD("/uploads/:id", handler.HeadResumableUpload)
+	authorized.GET("/uploads/:id", handler.GetResumableUpload)
+	authorized.PATCH("/uploads/:id", handler.PatchResumableUpload)
+	authorized.DELETE("/uploads/:id", handler.DeleteResumableUpload)
 
 	url := ginSwagger.URL("swagger/doc.json") // The url pointing to API definition
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
+	public.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
 }
 
 func customCORSMiddleware() gin.HandlerFunc {
@@ -132,6 +153,7 @@ func customCORSMiddleware() gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Methods", "POST, GET, PUT, PATCH, DELETE, OPTIONS, HEAD")
 		c.Header("Access-Control-Allow-Headers", "Platform-Id, Content-Type, Accesp-Encoding, Authorization, Cache-Control")
 		c.Header("Access-Control-Allow-Headers", "*")
+		c.Header("Access-Control-Expose-Headers", "Location, Tus-Resumable, Upload-Offset, Upload-Length, Upload-Expires, Upload-File-Id")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -49,6 +49,30 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from, 2006-01-02 or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created until, 2006-01-02 or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at, updated_at, email",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default desc",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -192,28 +216,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/{id}": {
-            "get": {
+        "/admin/gc": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID Admin",
+                "description": "Delete files no publication or user refers to, unreferenced blobs and stray objects in the blob store that are older than GC_GRACE_PERIOD. Runs as a dry run unless dry_run=false.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "summary": "Get By ID Admin",
-                "operationId": "get_by_id_admin",
+                "summary": "Collect Garbage",
+                "operationId": "collect_garbage",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path"
+                        "type": "boolean",
+                        "description": "only report what would be deleted, default true",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -228,7 +252,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.GarbageReport"
                                         }
                                     }
                                 }
@@ -272,37 +296,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/moderation/approve": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Admin",
+                "description": "Publish several publications waiting for review. Each item succeeds or fails on its own.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "summary": "Update Admin",
-                "operationId": "update_admin",
+                "summary": "Bulk Approve Publications",
+                "operationId": "bulk_approve_publications",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "UpdateAdminRequest",
-                        "name": "Admin",
+                        "description": "ids and an optional note",
+                        "name": "Review",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateAdmin"
+                            "$ref": "#/definitions/models.ModerationBulkRequest"
                         }
                     }
                 ],
@@ -318,7 +337,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.ModerationBulkResult"
                                         }
                                     }
                                 }
@@ -362,29 +381,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/moderation/queue": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Admin",
+                "description": "Publications waiting for review, oldest submission first, with contributor, file, claim and duplicate-file hints",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "summary": "Delete Admin",
-                "operationId": "delete_admin",
+                "summary": "Get Moderation Queue",
+                "operationId": "get_moderation_queue",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "course_id",
+                        "name": "course_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "hide items claimed by other moderators",
+                        "name": "available",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -399,7 +437,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.ModerationQueueResponse"
                                         }
                                     }
                                 }
@@ -445,40 +483,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/course": {
-            "get": {
+        "/admin/moderation/queue/{id}/claim": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get List Course",
+                "description": "Reserve a publication waiting for review for MODERATION_CLAIM_TTL so no other moderator reviews it. Claiming again extends the claim.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Course"
+                    "Admin"
                 ],
-                "summary": "Get List Course",
-                "operationId": "get_list_course",
+                "summary": "Claim Publication",
+                "operationId": "claim_publication",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -493,7 +520,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.ModerationClaim"
                                         }
                                     }
                                 }
@@ -518,6 +545,42 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Claimed by another moderator or not waiting for review",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ModerationClaim"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -538,34 +601,38 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create Course",
+                "description": "Give up the claim on a publication. With force=true the claim of another moderator is dropped too.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Course"
+                    "Admin"
                 ],
-                "summary": "Create Course",
-                "operationId": "create_course",
+                "summary": "Release Publication",
+                "operationId": "release_publication",
                 "parameters": [
                     {
-                        "description": "CreateCourseRequest",
-                        "name": "Course",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateCourse"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "drop another moderator's claim",
+                        "name": "force",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "204": {
                         "description": "Success Request",
                         "schema": {
                             "allOf": [
@@ -601,6 +668,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "404": {
+                        "description": "No claim to release",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -622,28 +707,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/course/{id}": {
-            "get": {
+        "/admin/moderation/reject": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID Course",
+                "description": "Reject several publications waiting for review with the same reason. Each item succeeds or fails on its own.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Course"
+                    "Admin"
                 ],
-                "summary": "Get By ID Course",
-                "operationId": "get_by_id_course",
+                "summary": "Bulk Reject Publications",
+                "operationId": "bulk_reject_publications",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path"
+                        "description": "ids and reason",
+                        "name": "Review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ModerationBulkRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -658,7 +746,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.ModerationBulkResult"
                                         }
                                     }
                                 }
@@ -702,38 +790,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/moderation/stats": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Course",
+                "description": "Queue length and, per moderator, approvals, rejections, mean review time and live claims",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Course"
+                    "Admin"
                 ],
-                "summary": "Update Course",
-                "operationId": "update_course",
+                "summary": "Get Moderation Stats",
+                "operationId": "get_moderation_stats",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "decisions from, 2006-01-02 or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
                     },
                     {
-                        "description": "UpdateCourseRequest",
-                        "name": "Course",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateCourse"
-                        }
+                        "type": "string",
+                        "description": "decisions until, 2006-01-02 or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -748,7 +834,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.ModerationStats"
                                         }
                                     }
                                 }
@@ -792,22 +878,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/publication/{id}/approve": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Course",
+                "description": "Publish a publication waiting for review",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Course"
+                    "Admin"
                 ],
-                "summary": "Delete Course",
-                "operationId": "delete_course",
+                "summary": "Approve Publication",
+                "operationId": "approve_publication",
                 "parameters": [
                     {
                         "type": "string",
@@ -815,6 +903,14 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "optional note",
+                        "name": "Review",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.PublicationReview"
+                        }
                     }
                 ],
                 "responses": {
@@ -829,7 +925,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.Publication"
                                         }
                                     }
                                 }
@@ -854,68 +950,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Server error",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            }
-        },
-        "/courses_by_semester_id": {
-            "get": {
-                "description": "Get List GetListCoursesBySemesterId",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Course"
-                ],
-                "summary": "Get List GetListCoursesBySemesterId",
-                "operationId": "get_list_courses_by_semester_id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Semester ID",
-                        "name": "semester_id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "allOf": [
                                 {
@@ -932,8 +968,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Not waiting for review or claimed by another moderator",
                         "schema": {
                             "allOf": [
                                 {
@@ -971,40 +1007,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/download": {
-            "get": {
+        "/admin/publication/{id}/reject": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get List Download",
+                "description": "Reject a publication waiting for review. The reason is shown to the contributor.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Download"
+                    "Admin"
                 ],
-                "summary": "Get List Download",
-                "operationId": "get_list_download",
+                "summary": "Reject Publication",
+                "operationId": "reject_publication",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "reason",
+                        "name": "Review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PublicationReview"
+                        }
                     }
                 ],
                 "responses": {
@@ -1019,7 +1053,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.Publication"
                                         }
                                     }
                                 }
@@ -1044,6 +1078,42 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Not waiting for review or claimed by another moderator",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1063,30 +1133,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/publication/{id}/rollback": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create Download",
+                "description": "Restore the content of an earlier revision. The restored content is saved as a new revision; the status doesn't change.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Download"
+                    "Admin"
                 ],
-                "summary": "Create Download",
-                "operationId": "create_download",
+                "summary": "Rollback Publication",
+                "operationId": "rollback_publication",
                 "parameters": [
                     {
-                        "description": "CreateDownloadRequest",
-                        "name": "Download",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "revision to restore",
+                        "name": "Rollback",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateDownload"
+                            "$ref": "#/definitions/models.PublicationRollback"
                         }
                     }
                 ],
@@ -1102,7 +1181,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.Publication"
                                         }
                                     }
                                 }
@@ -1127,6 +1206,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "404": {
+                        "description": "Publication or revision not found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1148,28 +1245,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/download/{id}": {
-            "get": {
+        "/admin/tags/merge": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID Download",
+                "description": "Replace the source tags by the target tag, created when missing, on every publication carrying them; each gets a new revision. The source tags are removed.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Download"
+                    "Admin"
                 ],
-                "summary": "Get By ID Download",
-                "operationId": "get_by_id_download",
+                "summary": "Merge Tags",
+                "operationId": "merge_tags",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path"
+                        "description": "tags to merge",
+                        "name": "Merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeTags"
+                        }
                     }
                 ],
                 "responses": {
@@ -1184,7 +1284,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.TagChange"
                                         }
                                     }
                                 }
@@ -1209,6 +1309,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "404": {
+                        "description": "No source tag exists",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1228,22 +1346,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/tags/{id}": {
             "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Download",
+                "description": "Rename a tag on every publication carrying it, each getting a new revision. Renaming to an existing tag is refused, merge them instead.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Download"
+                    "Admin"
                 ],
-                "summary": "Update Download",
-                "operationId": "update_download",
+                "summary": "Rename Tag",
+                "operationId": "rename_tag",
                 "parameters": [
                     {
                         "type": "string",
@@ -1253,12 +1373,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdateDownloadRequest",
-                        "name": "Download",
+                        "description": "new name",
+                        "name": "Tag",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateDownload"
+                            "$ref": "#/definitions/models.RenameTag"
                         }
                     }
                 ],
@@ -1274,7 +1394,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.TagChange"
                                         }
                                     }
                                 }
@@ -1299,6 +1419,42 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Name taken by another tag",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1318,29 +1474,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Download",
+                "description": "Get By ID Admin",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Download"
+                    "Admin"
                 ],
-                "summary": "Delete Download",
-                "operationId": "delete_download",
+                "summary": "Get By ID Admin",
+                "operationId": "get_by_id_admin",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "id",
                         "name": "id",
-                        "in": "path",
-                        "required": true
+                        "in": "path"
                     }
                 ],
                 "responses": {
@@ -1399,77 +1556,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/file_download/{filename}": {
-            "get": {
-                "description": "File Download a file by its filename",
-                "produces": [
-                    "application/octet-stream"
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Admin",
+                "consumes": [
+                    "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Admin"
                 ],
-                "summary": "File Download a specific file",
+                "summary": "Update Admin",
+                "operationId": "update_admin",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filename of the file to download",
-                        "name": "filename",
+                        "description": "id",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "The file for download",
-                        "schema": {
-                            "type": "file"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "File Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    {
+                        "description": "UpdateAdminRequest",
+                        "name": "Admin",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.UpdateAdmin"
                         }
                     }
-                }
-            }
-        },
-        "/get_publication_stats": {
-            "get": {
-                "description": "Get the count of likes and downloads for a publication",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Publication"
-                ],
-                "summary": "Get  Publication Stats",
-                "operationId": "get_publication_stats",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Publication ID",
-                        "name": "publication_id",
-                        "in": "query",
-                        "required": true
-                    }
                 ],
                 "responses": {
                     "200": {
@@ -1483,7 +1602,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.PublicationStats"
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -1527,25 +1646,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/get_user_activity_counts": {
-            "get": {
-                "description": "Get the count of publications, downloads, and likes for a specific user",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Admin",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Admin"
                 ],
-                "summary": "Get User Activity Counts",
-                "operationId": "get_user_activity_counts",
+                "summary": "Delete Admin",
+                "operationId": "delete_admin",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1561,7 +1683,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserActivityCounts"
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -1607,105 +1729,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/image/{filename}": {
-            "get": {
-                "description": "Get an image by its filename",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "image/png",
-                    " image/jpeg",
-                    " image/gif"
-                ],
-                "tags": [
-                    "File"
-                ],
-                "summary": "Get a specific image",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filename of the image",
-                        "name": "filename",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "The image file",
-                        "schema": {
-                            "type": "file"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Image Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/images": {
-            "get": {
-                "description": "Get a list of all images that have been uploaded",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "File"
-                ],
-                "summary": "List all uploaded images except Profile Images",
-                "responses": {
-                    "200": {
-                        "description": "List of images",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/like": {
+        "/course": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get List Like",
+                "description": "Get List Course",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Course"
                 ],
-                "summary": "Get List Like",
-                "operationId": "get_list_like",
+                "summary": "Get List Course",
+                "operationId": "get_list_course",
                 "parameters": [
                     {
                         "type": "string",
@@ -1724,6 +1763,36 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "semester_id",
+                        "name": "semester_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from, 2006-01-02 or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created until, 2006-01-02 or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at, updated_at, course_title",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default desc",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1789,23 +1858,23 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create Like",
+                "description": "Create Course",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Course"
                 ],
-                "summary": "Create Like",
-                "operationId": "create_like",
+                "summary": "Create Course",
+                "operationId": "create_course",
                 "parameters": [
                     {
-                        "description": "CreateLikeRequest",
-                        "name": "Like",
+                        "description": "CreateCourseRequest",
+                        "name": "Course",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateLike"
+                            "$ref": "#/definitions/models.CreateCourse"
                         }
                     }
                 ],
@@ -1867,22 +1936,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/like/{id}": {
+        "/course/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID Like",
+                "description": "Get By ID Course",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Course"
                 ],
-                "summary": "Get By ID Like",
-                "operationId": "get_by_id_like",
+                "summary": "Get By ID Course",
+                "operationId": "get_by_id_course",
                 "parameters": [
                     {
                         "type": "string",
@@ -1954,15 +2023,15 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Like",
+                "description": "Update Course",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Course"
                 ],
-                "summary": "Update Like",
-                "operationId": "update_like",
+                "summary": "Update Course",
+                "operationId": "update_course",
                 "parameters": [
                     {
                         "type": "string",
@@ -1972,12 +2041,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdateLikeRequest",
-                        "name": "Like",
+                        "description": "UpdateCourseRequest",
+                        "name": "Course",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateLike"
+                            "$ref": "#/definitions/models.UpdateCourse"
                         }
                     }
                 ],
@@ -2044,15 +2113,15 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Like",
+                "description": "Delete Course",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Course"
                 ],
-                "summary": "Delete Like",
-                "operationId": "delete_like",
+                "summary": "Delete Course",
+                "operationId": "delete_course",
                 "parameters": [
                     {
                         "type": "string",
@@ -2120,102 +2189,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Login",
+        "/courses_by_semester_id": {
+            "get": {
+                "description": "Get List GetListCoursesBySemesterId",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Login"
+                    "Course"
                 ],
-                "summary": "Login",
-                "operationId": "login",
-                "parameters": [
-                    {
-                        "description": "LoginRequest",
-                        "name": "login",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.LoginInfo"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success Request",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Server error",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            }
-        },
-        "/notification": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get List Notification",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Notification"
-                ],
-                "summary": "Get List Notification",
-                "operationId": "get_list_notification",
+                "summary": "Get List GetListCoursesBySemesterId",
+                "operationId": "get_list_courses_by_semester_id",
                 "parameters": [
                     {
                         "type": "string",
@@ -2234,6 +2218,13 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Semester ID",
+                        "name": "semester_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -2292,31 +2283,78 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/download": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create Notification",
+                "description": "Get List Download",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notification"
+                    "Download"
                 ],
-                "summary": "Create Notification",
-                "operationId": "create_notification",
+                "summary": "Get List Download",
+                "operationId": "get_list_download",
                 "parameters": [
                     {
-                        "description": "CreateNotificationRequest",
-                        "name": "Notification",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateNotification"
-                        }
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "publication_id",
+                        "name": "publication_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "contributor_id",
+                        "name": "contributor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from, 2006-01-02 or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created until, 2006-01-02 or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at, updated_at",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default desc",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2377,22 +2415,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/{id}": {
+        "/download/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID Notification",
+                "description": "Get By ID Download",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notification"
+                    "Download"
                 ],
-                "summary": "Get By ID Notification",
-                "operationId": "get_by_id_notification",
+                "summary": "Get By ID Download",
+                "operationId": "get_by_id_download",
                 "parameters": [
                     {
                         "type": "string",
@@ -2458,21 +2496,21 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Notification",
+                "description": "Delete Download",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notification"
+                    "Download"
                 ],
-                "summary": "Update Notification",
-                "operationId": "update_notification",
+                "summary": "Delete Download",
+                "operationId": "delete_download",
                 "parameters": [
                     {
                         "type": "string",
@@ -2480,15 +2518,6 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "UpdateNotificationRequest",
-                        "name": "Notification",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateNotification"
-                        }
                     }
                 ],
                 "responses": {
@@ -2528,53 +2557,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Server error",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Delete Notification",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Notification"
-                ],
-                "summary": "Delete Notification",
-                "operationId": "delete_notification",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success Request",
+                    "403": {
+                        "description": "Not the contributor",
                         "schema": {
                             "allOf": [
                                 {
@@ -2591,8 +2575,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "allOf": [
                                 {
@@ -2630,33 +2614,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile_image/{filename}": {
+        "/file_download/{filename}": {
             "get": {
-                "description": "Get an image by its filename",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "File Download a file by its filename. A client's downloads of a publication count once until DOWNLOAD_DEDUPE_WINDOW has passed since its last counted download.",
                 "produces": [
-                    "image/png",
-                    " image/jpeg",
-                    " image/gif"
+                    "application/octet-stream"
                 ],
                 "tags": [
                     "File"
                 ],
-                "summary": "Get a specific Profile image",
+                "summary": "File Download a specific file",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filename of the image",
+                        "description": "File ID of the file to download, as returned on upload",
                         "name": "filename",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "expiry of a signed link",
+                        "name": "expires",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "user a signed link is bound to",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "signature of a signed link",
+                        "name": "signature",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The image file",
+                        "description": "The file for download",
                         "schema": {
                             "type": "file"
                         }
@@ -2668,8 +2665,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Missing, expired or invalid signed link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
-                        "description": "Image Not Found",
+                        "description": "File Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -2685,40 +2689,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/publication": {
+        "/get_publication_stats": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get List Publication",
+                "description": "Get the count of likes and downloads for a publication",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Publication"
                 ],
-                "summary": "Get List Publication",
-                "operationId": "get_list_publication",
+                "summary": "Get  Publication Stats",
+                "operationId": "get_publication_stats",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Publication ID",
+                        "name": "publication_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -2733,7 +2721,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.PublicationStats"
                                         }
                                     }
                                 }
@@ -2777,31 +2765,26 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Create Publication",
+            }
+        },
+        "/get_user_activity_counts": {
+            "get": {
+                "description": "Get the count of publications, downloads, and likes for a specific user",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Publication"
+                    "User"
                 ],
-                "summary": "Create Publication",
-                "operationId": "create_publication",
+                "summary": "Get User Activity Counts",
+                "operationId": "get_user_activity_counts",
                 "parameters": [
                     {
-                        "description": "CreatePublicationRequest",
-                        "name": "Publication",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreatePublication"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -2816,7 +2799,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/models.UserActivityCounts"
                                         }
                                     }
                                 }
@@ -2862,28 +2845,179 @@ const docTemplate = `{
                 }
             }
         },
-        "/publication/{id}": {
+        "/image/{filename}": {
+            "get": {
+                "description": "Get an image by its filename",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "image/png",
+                    " image/jpeg",
+                    " image/gif"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Get a specific image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID of the image, as returned on upload",
+                        "name": "filename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "original (default), medium (1024px) or thumb (256px)",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The image file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Image Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/images": {
+            "get": {
+                "description": "Get the file ids of the profile images that have been uploaded, with the URLs of their thumbnails",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "List all uploaded profile images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of images",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/like": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID Publication",
+                "description": "Get List Like",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Publication"
+                    "Like"
                 ],
-                "summary": "Get By ID Publication",
-                "operationId": "get_by_id_publication",
+                "summary": "Get List Like",
+                "operationId": "get_list_like",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path"
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "publication_id",
+                        "name": "publication_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "contributor_id",
+                        "name": "contributor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from, 2006-01-02 or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created until, 2006-01-02 or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at, updated_at",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default desc",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2942,38 +3076,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/like/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Publication",
+                "description": "Get By ID Like",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Publication"
+                    "Like"
                 ],
-                "summary": "Update Publication",
-                "operationId": "update_publication",
+                "summary": "Get By ID Like",
+                "operationId": "get_by_id_like",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "id",
                         "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "UpdatePublicationRequest",
-                        "name": "Publication",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdatePublication"
-                        }
+                        "in": "path"
                     }
                 ],
                 "responses": {
@@ -3039,15 +3165,15 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Publication",
+                "description": "Delete Like",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Publication"
+                    "Like"
                 ],
-                "summary": "Delete Publication",
-                "operationId": "delete_publication",
+                "summary": "Delete Like",
+                "operationId": "delete_like",
                 "parameters": [
                     {
                         "type": "string",
@@ -3094,8 +3220,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "403": {
+                        "description": "Not the contributor",
                         "schema": {
                             "allOf": [
                                 {
@@ -3111,30 +3237,66 @@ const docTemplate = `{
                                 }
                             ]
                         }
-                    }
-                }
-            }
-        },
-        "/publications/tags": {
-            "get": {
-                "description": "Get a list of publications that have the specified tag",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Publication"
-                ],
-                "summary": "Get Publications by Tag",
-                "parameters": [
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Login",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Login"
+                ],
+                "summary": "Login",
+                "operationId": "login",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "Tag to search for",
-                        "name": "tag",
-                        "in": "query",
-                        "required": true
+                        "description": "LoginRequest",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginInfo"
+                        }
                     }
                 ],
                 "responses": {
@@ -3149,10 +3311,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.Publication"
-                                            }
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -3198,28 +3357,96 @@ const docTemplate = `{
                 }
             }
         },
-        "/register": {
+        "/logout": {
             "post": {
-                "description": "Register",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke the session of the current access token",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Register"
+                    "Login"
                 ],
-                "summary": "Register",
-                "operationId": "register",
-                "parameters": [
-                    {
-                        "description": "CreateUserRequest",
-                        "name": "register",
-                        "in": "body",
-                        "required": true,
+                "summary": "Logout",
+                "operationId": "logout",
+                "responses": {
+                    "204": {
+                        "description": "Success Request",
                         "schema": {
-                            "$ref": "#/definitions/models.CreateUser"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
+                }
+            }
+        },
+        "/logout/all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke every session of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Login"
                 ],
+                "summary": "Logout All Devices",
+                "operationId": "logout_all",
                 "responses": {
                     "200": {
                         "description": "Success Request",
@@ -3239,8 +3466,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "allOf": [
                                 {
@@ -3278,22 +3505,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/semester": {
+        "/notification": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get List Semester",
+                "description": "Get List Notification",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Semester"
+                    "Notification"
                 ],
-                "summary": "Get List Semester",
-                "operationId": "get_list_semester",
+                "summary": "Get List Notification",
+                "operationId": "get_list_notification",
                 "parameters": [
                     {
                         "type": "string",
@@ -3312,6 +3539,36 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "message_type",
+                        "name": "message_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from, 2006-01-02 or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created until, 2006-01-02 or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at, updated_at, message_type",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default desc",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -3377,23 +3634,23 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create Semester",
+                "description": "Create Notification",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Semester"
+                    "Notification"
                 ],
-                "summary": "Create Semester",
-                "operationId": "create_semester",
+                "summary": "Create Notification",
+                "operationId": "create_notification",
                 "parameters": [
                     {
-                        "description": "CreateSemesterRequest",
-                        "name": "Semester",
+                        "description": "CreateNotificationRequest",
+                        "name": "Notification",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateSemester"
+                            "$ref": "#/definitions/models.CreateNotification"
                         }
                     }
                 ],
@@ -3455,22 +3712,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/semester/{id}": {
+        "/notification/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID Semester",
+                "description": "Get By ID Notification",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Semester"
+                    "Notification"
                 ],
-                "summary": "Get By ID Semester",
-                "operationId": "get_by_id_semester",
+                "summary": "Get By ID Notification",
+                "operationId": "get_by_id_notification",
                 "parameters": [
                     {
                         "type": "string",
@@ -3542,15 +3799,15 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Semester",
+                "description": "Update Notification",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Semester"
+                    "Notification"
                 ],
-                "summary": "Update Semester",
-                "operationId": "update_semester",
+                "summary": "Update Notification",
+                "operationId": "update_notification",
                 "parameters": [
                     {
                         "type": "string",
@@ -3560,12 +3817,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "UpdateSemesterRequest",
-                        "name": "Semester",
+                        "description": "UpdateNotificationRequest",
+                        "name": "Notification",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateSemester"
+                            "$ref": "#/definitions/models.UpdateNotification"
                         }
                     }
                 ],
@@ -3632,15 +3889,15 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Semester",
+                "description": "Delete Notification",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Semester"
+                    "Notification"
                 ],
-                "summary": "Delete Semester",
-                "operationId": "delete_semester",
+                "summary": "Delete Notification",
+                "operationId": "delete_notification",
                 "parameters": [
                     {
                         "type": "string",
@@ -3708,22 +3965,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/top_contributors": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get the top contributors (users) based on the count of their publications",
+        "/password/forgot": {
+            "post": {
+                "description": "Send a password reset code to the email of an account",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Login"
+                ],
+                "summary": "Forgot Password",
+                "operationId": "forgot_password",
+                "parameters": [
+                    {
+                        "description": "EmailRequest",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EmailRequest"
+                        }
+                    }
                 ],
-                "summary": "Get Top Contributors",
-                "operationId": "get_top_contributors",
                 "responses": {
                     "200": {
                         "description": "Success Request",
@@ -3736,10 +3999,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.UserpublicationCounts"
-                                            }
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -3785,110 +4045,163 @@ const docTemplate = `{
                 }
             }
         },
-        "/upload_profile": {
+        "/password/reset": {
             "post": {
-                "description": "Upload an image file to the server and store it locally.",
+                "description": "Set a new password using the code from the reset email. Every session of the account is logged out.",
                 "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "Login"
                 ],
-                "summary": "Upload an Image",
+                "summary": "Reset Password",
+                "operationId": "reset_password",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Image File",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "ResetPasswordRequest",
+                        "name": "reset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResetPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "File uploaded successfully",
+                        "description": "Success Request",
                         "schema": {
-                            "$ref": "#/definitions/handler.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handler.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
-                        "description": "Server Error",
+                        "description": "Server error",
                         "schema": {
-                            "$ref": "#/definitions/handler.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/uploadd": {
-            "post": {
-                "description": "Upload an image file to the server and store it locally.",
+        "/profile_image/{filename}": {
+            "get": {
+                "description": "Get an image by its filename",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "image/png",
+                    " image/jpeg",
+                    " image/gif"
                 ],
                 "tags": [
                     "File"
                 ],
-                "summary": "Upload an Image",
+                "summary": "Get a specific Profile image",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Image File",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "File ID of the image, as returned on upload",
+                        "name": "filename",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "original (default), medium (1024px) or thumb (256px)",
+                        "name": "size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "File uploaded successfully",
+                        "description": "The image file",
                         "schema": {
-                            "$ref": "#/definitions/handler.Response"
+                            "type": "file"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handler.Response"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Image Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Server Error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handler.Response"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/user": {
+        "/publication": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get List User",
+                "description": "Get List Publication. Only published publications are listed, except for admins and for contributors listing their own (contributor_id).",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Publication"
                 ],
-                "summary": "Get List User",
-                "operationId": "get_list_user",
+                "summary": "Get List Publication",
+                "operationId": "get_list_publication",
                 "parameters": [
                     {
                         "type": "string",
@@ -3907,6 +4220,66 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "course_id",
+                        "name": "course_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "semester_id",
+                        "name": "semester_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "contributor_id",
+                        "name": "contributor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, pending_review, published, rejected or archived",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from, 2006-01-02 or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created until, 2006-01-02 or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "tags, repeated or separated with commas",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "all (default) or any of the tags",
+                        "name": "tag_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at, updated_at, title, status, like_count",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default desc",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -3965,30 +4338,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get By ID User",
+                "description": "Create Publication. It is submitted for review unless status is draft; admins may create it published. The response lists warnings when the file already backs another publication of the course.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Publication"
                 ],
-                "summary": "Get By ID User",
-                "operationId": "get_by_id_user",
+                "summary": "Create Publication",
+                "operationId": "create_publication",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path"
+                        "description": "CreatePublicationRequest",
+                        "name": "Publication",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePublication"
+                        }
                     }
                 ],
                 "responses": {
@@ -4028,6 +4402,24 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "File belongs to another user",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -4047,38 +4439,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/publication/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update User",
+                "description": "Get By ID Publication. Unpublished publications are only visible to their contributor and admins.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Publication"
                 ],
-                "summary": "Update User",
-                "operationId": "update_user",
+                "summary": "Get By ID Publication",
+                "operationId": "get_by_id_publication",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "id",
                         "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "UpdateUserRequest",
-                        "name": "User",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateUser"
-                        }
+                        "in": "path"
                     }
                 ],
                 "responses": {
@@ -4138,21 +4522,21 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete User",
+                "description": "Update Publication",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Publication"
                 ],
-                "summary": "Delete User",
-                "operationId": "delete_user",
+                "summary": "Update Publication",
+                "operationId": "update_publication",
                 "parameters": [
                     {
                         "type": "string",
@@ -4160,6 +4544,15 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "UpdatePublicationRequest",
+                        "name": "Publication",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePublication"
+                        }
                     }
                 ],
                 "responses": {
@@ -4199,8 +4592,8 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "403": {
+                        "description": "Not the contributor",
                         "schema": {
                             "allOf": [
                                 {
@@ -4216,32 +4609,67 @@ const docTemplate = `{
                                 }
                             ]
                         }
-                    }
-                }
-            }
-        },
-        "/users/scores": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Get users ranked by score (likes + downloads) / publications",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "User"
-                ],
-                "summary": "Get User Scores",
-                "operationId": "get_user_scores",
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Publication",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Publication"
+                ],
+                "summary": "Delete Publication",
+                "operationId": "delete_publication",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Number of top users to return (default 10)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -4256,10 +4684,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.UserScore"
-                                            }
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -4284,6 +4709,42 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Not the contributor",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -4305,34 +4766,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/statistics": {
-            "get": {
+        "/publication/{id}/archive": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get count of publications, downloads, likes and rank for a specific user",
+                "description": "Archive a draft, published or rejected publication",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Publication"
                 ],
-                "summary": "Get User Statistics",
-                "operationId": "get_user_statistics",
+                "summary": "Archive Publication",
+                "operationId": "archive_publication",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User Statistics",
+                        "description": "Success Request",
                         "schema": {
                             "allOf": [
                                 {
@@ -4342,7 +4803,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserStatistics"
+                                            "$ref": "#/definitions/models.Publication"
                                         }
                                     }
                                 }
@@ -4367,6 +4828,42 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Not the contributor",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Not allowed in the current status",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -4388,29 +4885,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user_id}/rank": {
+        "/publication/{id}/download-link": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get the rank of a specific user based on their score",
+                "description": "Mint a signed link to the publication file that expires after DOWNLOAD_LINK_TTL. With bind_user=true only the caller can use it.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Publication"
                 ],
-                "summary": "Get User Rank",
-                "operationId": "get_user_rank",
+                "summary": "Get Publication Download Link",
+                "operationId": "get_publication_download_link",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "bind the link to the caller",
+                        "name": "bind_user",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -4425,7 +4928,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserRank"
+                                            "$ref": "#/definitions/models.DownloadLink"
                                         }
                                     }
                                 }
@@ -4450,6 +4953,42 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -4470,92 +5009,3600 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.Response": {
-            "type": "object",
-            "properties": {
-                "data": {},
-                "description": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.CreateAdmin": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.CreateCourse": {
-            "type": "object",
-            "properties": {
-                "course_title": {
-                    "type": "string"
-                },
-                "semester_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.CreateDownload": {
-            "type": "object",
-            "properties": {
-                "contributor_id": {
-                    "type": "string"
-                },
-                "count": {
-                    "type": "number"
-                },
-                "publication_id": {
-                    "type": "string"
-                }
-            }
         },
-        "models.CreateLike": {
-            "type": "object",
-            "properties": {
-                "contributor_id": {
-                    "type": "string"
-                },
-                "count": {
-                    "type": "number"
+        "/publication/{id}/like": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Like the publication as the current user. Liking twice keeps a single like.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Like"
+                ],
+                "summary": "Like Publication",
+                "operationId": "like_publication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "publication id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.LikeStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Not a user",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove the like of the current user. Unliking a publication that isn't liked is not an error.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Like"
+                ],
+                "summary": "Unlike Publication",
+                "operationId": "unlike_publication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "publication id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.LikeStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Not a user",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/publication/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Every version of a publication, newest first, with the changed fields, the replaced file and the editor",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Publication"
+                ],
+                "summary": "Get Publication Revisions",
+                "operationId": "get_publication_revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PublicationRevision"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Not the contributor",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/publication/{id}/revisions/diff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fields that differ between two revisions. to defaults to the latest revision, from to the one before to.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Publication"
+                ],
+                "summary": "Get Publication Revision Diff",
+                "operationId": "get_publication_revision_diff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "older revision",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "newer revision",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PublicationRevisionDiff"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Not the contributor",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/publication/{id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Submit a draft or rejected publication for review",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Publication"
+                ],
+                "summary": "Submit Publication",
+                "operationId": "submit_publication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Publication"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Not the contributor",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Not allowed in the current status",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/publication/{id}/transitions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moderation history of a publication, oldest first, with rejection reasons",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Publication"
+                ],
+                "summary": "Get Publication Transitions",
+                "operationId": "get_publication_transitions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PublicationTransition"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Not the contributor",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/publication/{id}/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Take a publication waiting for review back to draft",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Publication"
+                ],
+                "summary": "Withdraw Publication",
+                "operationId": "withdraw_publication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Publication"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Not the contributor",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Not allowed in the current status",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/publications/tags": {
+            "get": {
+                "description": "Get the published publications that have the specified tags, all of them unless match=any. Tags match exactly, case and extra spaces aside.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Publication"
+                ],
+                "summary": "Get Publications by Tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag to search for, repeat it or separate tags with commas",
+                        "name": "tag",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "all (default) or any",
+                        "name": "match",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Publication"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token. The refresh token is rotated on every call.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Login"
+                ],
+                "summary": "Refresh",
+                "operationId": "refresh",
+                "parameters": [
+                    {
+                        "description": "RefreshTokenRequest",
+                        "name": "refresh",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Register",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Register"
+                ],
+                "summary": "Register",
+                "operationId": "register",
+                "parameters": [
+                    {
+                        "description": "CreateUserRequest",
+                        "name": "register",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/register/resend": {
+            "post": {
+                "description": "Send a new email verification code, invalidating the previous one",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Register"
+                ],
+                "summary": "Resend Verification Code",
+                "operationId": "resend_verification",
+                "parameters": [
+                    {
+                        "description": "EmailRequest",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/register/verify": {
+            "post": {
+                "description": "Confirm the email address of a new account with the code sent on registration",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Register"
+                ],
+                "summary": "Verify Email",
+                "operationId": "verify_email",
+                "parameters": [
+                    {
+                        "description": "VerifyEmailRequest",
+                        "name": "verify",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/semester": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get List Semester",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Semester"
+                ],
+                "summary": "Get List Semester",
+                "operationId": "get_list_semester",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from, 2006-01-02 or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created until, 2006-01-02 or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at, updated_at, semester_number",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default desc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create Semester",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Semester"
+                ],
+                "summary": "Create Semester",
+                "operationId": "create_semester",
+                "parameters": [
+                    {
+                        "description": "CreateSemesterRequest",
+                        "name": "Semester",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSemester"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/semester/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get By ID Semester",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Semester"
+                ],
+                "summary": "Get By ID Semester",
+                "operationId": "get_by_id_semester",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Semester",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Semester"
+                ],
+                "summary": "Update Semester",
+                "operationId": "update_semester",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateSemesterRequest",
+                        "name": "Semester",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSemester"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete Semester",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Semester"
+                ],
+                "summary": "Delete Semester",
+                "operationId": "delete_semester",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Tags carried by published publications with how many carry each, most used first. prefix narrows the list for autocompletion.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "Get List Tag",
+                "operationId": "get_list_tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "start of the tag",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "count, name, created_at",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default desc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TagGetListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/top_contributors": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the top contributors (users) based on the count of their publications",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get Top Contributors",
+                "operationId": "get_top_contributors",
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UserpublicationCounts"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deleted semesters, courses, publications, users and notifications, most recently deleted first, with when they are purged for good",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get List Trash",
+                "operationId": "get_list_trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "semester, course, publication, user or notification",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TrashGetListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{kind}/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Take a row out of the trash. Restoring a semester or course also restores the courses and publications deleted with it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Restore From Trash",
+                "operationId": "restore_trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "semester, course, publication, user or notification",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not in the trash",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Parent in the trash or email/username taken",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/upload_profile": {
+            "post": {
+                "description": "Upload a file to the configured blob store. The response carries the file id to use as image_id or file_id of a publication.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Upload an Image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Image File",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File uploaded successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large or quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "File type not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploadd": {
+            "post": {
+                "description": "Upload a file to the configured blob store. The response carries the file id to use as image_id or file_id of a publication.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Upload an Image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Image File",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "publication_cover, publication_file (default) or publication_video",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "File uploaded successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large or quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "File type not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a tus upload of Upload-Length bytes. Upload-Metadata may carry base64 encoded \"filename\" and \"kind\" (publication_file by default, or publication_video). The upload URL is returned in the Location header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Start a resumable upload",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Total size in bytes",
+                        "name": "Upload-Length",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "tus metadata, e.g. filename bGVjdHVyZS5tcDQ=,kind cHVibGljYXRpb25fdmlkZW8=",
+                        "name": "Upload-Metadata",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Upload created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UploadSession"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large or quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the state of a resumable upload",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Get a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "upload id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upload",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UploadSession"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "410": {
+                        "description": "Upload expired",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the received chunks and gives the reserved quota back",
+                "tags": [
+                    "File"
+                ],
+                "summary": "Cancel a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "upload id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Upload cancelled"
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the received byte count in Upload-Offset, the place to resume from.",
+                "tags": [
+                    "File"
+                ],
+                "summary": "Resumable upload offset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "upload id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upload-Offset and Upload-Length headers"
+                    },
+                    "404": {
+                        "description": "Upload not found"
+                    },
+                    "410": {
+                        "description": "Upload expired"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Appends the body at Upload-Offset. An optional Upload-Checksum \"sha256 \u003cbase64 digest\u003e\" is verified against the chunk.",
+                "consumes": [
+                    "application/offset+octet-stream"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Upload a chunk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "upload id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset the chunk starts at",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sha256 \u003cbase64 digest\u003e",
+                        "name": "Upload-Checksum",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Chunk stored, new Upload-Offset in the header"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Upload-Offset doesn't match",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "410": {
+                        "description": "Upload expired",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Chunk too large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "415": {
+                        "description": "Wrong Content-Type or file type not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "460": {
+                        "description": "Checksum mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get List User",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get List User",
+                "operationId": "get_list_user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created from, 2006-01-02 or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created until, 2006-01-02 or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at, updated_at, name, surname, email, grade",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, default desc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get By ID User",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get By ID User",
+                "operationId": "get_by_id_user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update User. Changing the email unverifies it and sends a code to the new address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User",
+                "operationId": "update_user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "UpdateUserRequest",
+                        "name": "User",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete User",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete User",
+                "operationId": "delete_user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/users/scores": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get users ranked by score (likes + downloads) / publications",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get User Scores",
+                "operationId": "get_user_scores",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of top users to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UserScore"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/users/statistics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get count of publications, downloads, likes and rank for a specific user",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get User Statistics",
+                "operationId": "get_user_statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Statistics",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserStatistics"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/rank": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the rank of a specific user based on their score",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get User Rank",
+                "operationId": "get_user_rank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserRank"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "description": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Blob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "ref_count": {
+                    "type": "integer"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "storage_key": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateAdmin": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateCourse": {
+            "type": "object",
+            "properties": {
+                "course_title": {
+                    "type": "string"
+                },
+                "semester_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateNotification": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "message_type": {
+                    "type": "string"
+                },
+                "user_image": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePublication": {
+            "type": "object",
+            "properties": {
+                "contributor_id": {
+                    "type": "string"
+                },
+                "course_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "image_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is the initial status: draft, or pending_review (the\ndefault) to submit right away. Admins may also publish directly.",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSemester": {
+            "type": "object",
+            "properties": {
+                "semester_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateUser": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "profile_image": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DownloadLink": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EmailRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.File": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "original_name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "storage_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GarbageReport": {
+            "type": "object",
+            "properties": {
+                "blobs": {
+                    "description": "Blobs are stored contents no file refers to anymore.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Blob"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "files": {
+                    "description": "Files are files rows no publication, user or upload refers to.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.File"
+                    }
+                },
+                "freed_bytes": {
+                    "type": "integer"
+                },
+                "stray_keys": {
+                    "description": "StrayKeys are objects in the blob store nothing in the database\nknows about.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.LikeStatus": {
+            "type": "object",
+            "properties": {
+                "like_count": {
+                    "type": "integer"
+                },
+                "liked_by_me": {
+                    "type": "boolean"
+                },
+                "publication_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginInfo": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MergeTags": {
+            "type": "object",
+            "properties": {
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationBulkItem": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationBulkRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationBulkResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModerationBulkItem"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ModerationClaim": {
+            "type": "object",
+            "properties": {
+                "claimed_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "moderator_id": {
+                    "type": "string"
+                },
+                "publication_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationContributor": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "published": {
+                    "description": "Published and Rejected count the publications of the contributor in\nthese statuses, a hint of their track record.",
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationFile": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "original_name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ModerationQueueItem": {
+            "type": "object",
+            "properties": {
+                "claim": {
+                    "description": "Claim is the live claim on the item, nil when nobody holds one.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ModerationClaim"
+                        }
+                    ]
+                },
+                "contributor": {
+                    "$ref": "#/definitions/models.ModerationContributor"
+                },
+                "duplicates": {
+                    "description": "Duplicates are other publications whose file has the same content.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PublicationDuplicate"
+                    }
+                },
+                "file": {
+                    "description": "File is the attached file, nil for publications without one.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ModerationFile"
+                        }
+                    ]
+                },
+                "publication": {
+                    "$ref": "#/definitions/models.Publication"
+                },
+                "submitted_at": {
+                    "description": "SubmittedAt is when the publication last entered pending_review.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationQueueResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModerationQueueItem"
+                    }
+                }
+            }
+        },
+        "models.ModerationStats": {
+            "type": "object",
+            "properties": {
+                "moderators": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModeratorThroughput"
+                    }
                 },
-                "publication_id": {
+                "oldest_pending_at": {
                     "type": "string"
+                },
+                "pending": {
+                    "description": "Pending is the current length of the queue and OldestPendingAt the\nsubmission time of its first item.",
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateNotification": {
+        "models.ModeratorThroughput": {
             "type": "object",
             "properties": {
-                "file": {
-                    "type": "string"
+                "active_claims": {
+                    "type": "integer"
                 },
-                "message": {
-                    "type": "string"
+                "approved": {
+                    "type": "integer"
                 },
-                "message_type": {
+                "avg_review_seconds": {
+                    "type": "number"
+                },
+                "email": {
                     "type": "string"
                 },
-                "user_image": {
+                "last_decision_at": {
                     "type": "string"
                 },
-                "username": {
+                "moderator_id": {
                     "type": "string"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreatePublication": {
+        "models.Publication": {
             "type": "object",
             "properties": {
                 "contributor_id": {
@@ -4564,15 +8611,31 @@ const docTemplate = `{
                 "course_id": {
                     "type": "string"
                 },
+                "created_at": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
                 "file_id": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
                 "image_id": {
                     "type": "string"
                 },
+                "image_thumbnail": {
+                    "description": "ImageThumbnail is the URL of the small rendition of the cover.",
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer"
+                },
+                "liked_by_me": {
+                    "type": "boolean"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -4581,63 +8644,54 @@ const docTemplate = `{
                 },
                 "title": {
                     "type": "string"
-                }
-            }
-        },
-        "models.CreateSemester": {
-            "type": "object",
-            "properties": {
-                "semester_number": {
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "warnings": {
+                    "description": "Warnings are non-blocking notes about the request, such as a file\nthat already backs another publication.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.CreateUser": {
+        "models.PublicationDuplicate": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "grade": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                },
-                "profile_image": {
+                "course_id": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "boolean"
-                },
-                "student_id": {
+                "file_id": {
                     "type": "string"
                 },
-                "surname": {
+                "id": {
                     "type": "string"
                 },
-                "username": {
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "models.LoginInfo": {
+        "models.PublicationReview": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
+                "reason": {
                     "type": "string"
                 }
             }
         },
-        "models.Publication": {
+        "models.PublicationRevision": {
             "type": "object",
             "properties": {
+                "changed_fields": {
+                    "description": "ChangedFields names the fields that differ from the previous\nrevision.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "contributor_id": {
                     "type": "string"
                 },
@@ -4650,6 +8704,9 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "editor_id": {
+                    "type": "string"
+                },
                 "file_id": {
                     "type": "string"
                 },
@@ -4659,17 +8716,53 @@ const docTemplate = `{
                 "image_id": {
                     "type": "string"
                 },
-                "status": {
+                "previous_file_id": {
+                    "description": "PreviousFileID is the file this revision replaced, empty when the\nfile didn't change.",
                     "type": "string"
                 },
+                "publication_id": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "rollback_of": {
+                    "description": "RollbackOf is the revision restored by a rollback.",
+                    "type": "integer"
+                },
                 "tags": {
                     "type": "string"
                 },
                 "title": {
                     "type": "string"
+                }
+            }
+        },
+        "models.PublicationRevisionDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
                 },
-                "updated_at": {
+                "from": {
+                    "type": "integer"
+                },
+                "publication_id": {
                     "type": "string"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PublicationRollback": {
+            "type": "object",
+            "properties": {
+                "revision": {
+                    "type": "integer"
                 }
             }
         },
@@ -4677,71 +8770,170 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "download_count": {
-                    "type": "number"
+                    "type": "integer"
                 },
                 "like_count": {
-                    "type": "number"
+                    "type": "integer"
                 }
             }
         },
-        "models.UpdateAdmin": {
+        "models.PublicationTransition": {
             "type": "object",
             "properties": {
-                "email": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "password": {
+                "publication_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_status": {
                     "type": "string"
                 }
             }
         },
-        "models.UpdateCourse": {
+        "models.RefreshTokenRequest": {
             "type": "object",
             "properties": {
-                "course_title": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RenameTag": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ResetPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string"
                 },
-                "id": {
+                "email": {
                     "type": "string"
                 },
-                "semester_id": {
+                "new_password": {
                     "type": "string"
                 }
             }
         },
-        "models.UpdateDownload": {
+        "models.Tag": {
             "type": "object",
             "properties": {
-                "contributor_id": {
+                "count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "string"
                 },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagChange": {
+            "type": "object",
+            "properties": {
+                "publications": {
+                    "type": "integer"
+                },
+                "tag": {
+                    "$ref": "#/definitions/models.Tag"
+                }
+            }
+        },
+        "models.TagGetListResponse": {
+            "type": "object",
+            "properties": {
                 "count": {
-                    "type": "number"
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                }
+            }
+        },
+        "models.TrashGetListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrashItem"
+                    }
+                }
+            }
+        },
+        "models.TrashItem": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "publication_id": {
+                "kind": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "purge_at": {
+                    "description": "PurgeAt is when the retention job deletes the row for good.",
                     "type": "string"
                 }
             }
         },
-        "models.UpdateLike": {
+        "models.UpdateAdmin": {
             "type": "object",
             "properties": {
-                "contributor_id": {
+                "email": {
                     "type": "string"
                 },
-                "count": {
-                    "type": "number"
+                "id": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateCourse": {
+            "type": "object",
+            "properties": {
+                "course_title": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "publication_id": {
+                "semester_id": {
                     "type": "string"
                 }
             }
@@ -4791,6 +8983,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "status": {
+                    "description": "Status is ignored, it changes through the moderation endpoints.",
                     "type": "string"
                 },
                 "tags": {
@@ -4847,14 +9040,56 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UploadSession": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "ExpiresIn is the number of seconds left before the session expires.",
+                    "type": "integer"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UserActivityCounts": {
             "type": "object",
             "properties": {
                 "download_count": {
-                    "type": "number"
+                    "type": "integer"
                 },
                 "like_count": {
-                    "type": "number"
+                    "type": "integer"
                 },
                 "publication_count": {
                     "type": "integer"
@@ -4916,6 +9151,17 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.VerifyEmailRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
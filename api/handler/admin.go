@@ -31,6 +31,12 @@ func (h *handler) CreateAdmin(c *gin.Context) {
 		return
 	}
 
+	createAdmin.Password, err = helper.HashPassword(createAdmin.Password)
+	if err != nil {
+		h.handlerResponse(c, "helper.HashPassword", http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	id, err := h.strg.Admin().Create(c.Request.Context(), &createAdmin)
 	if err != nil {
 		h.handlerResponse(c, "storage.Admin.create", http.StatusInternalServerError, err.Error())
@@ -102,6 +108,10 @@ func (h *handler) GetByIdAdmin(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param date_from query string false "created from, 2006-01-02 or RFC3339"
+// @Param date_to query string false "created until, 2006-01-02 or RFC3339"
+// @Param sort_by query string false "created_at, updated_at, email"
+// @Param order query string false "asc or desc, default desc"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
@@ -119,13 +129,23 @@ func (h *handler) GetListAdmin(c *gin.Context) {
 		return
 	}
 
+	err = h.validateListQuery(c)
+	if err != nil {
+		h.handlerResponse(c, "get list Admin filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
 	resp, err := h.strg.Admin().GetList(c.Request.Context(), &models.AdminGetListRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+		Offset:   offset,
+		Limit:    limit,
+		Search:   c.Query("search"),
+		DateFrom: c.Query("date_from"),
+		DateTo:   c.Query("date_to"),
+		SortBy:   c.Query("sort_by"),
+		Order:    c.Query("order"),
 	})
 	if err != nil {
-		h.handlerResponse(c, "storage.Admin.get_list", http.StatusInternalServerError, err.Error())
+		h.listErrorResponse(c, "storage.Admin.get_list", err)
 		return
 	}
 
@@ -163,6 +183,14 @@ func (h *handler) UpdateAdmin(c *gin.Context) {
 		h.handlerResponse(c, "error Admin should bind json", http.StatusBadRequest, err.Error())
 		return
 	}
+
+	if len(updateAdmin.Password) > 0 {
+		updateAdmin.Password, err = helper.HashPassword(updateAdmin.Password)
+		if err != nil {
+			h.handlerResponse(c, "helper.HashPassword", http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
 	updateAdmin.Id = id
 	rowsAffected, err := h.strg.Admin().Update(c.Request.Context(), &updateAdmin)
 	if err != nil {
@@ -2,14 +2,18 @@ package handler
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"net/http"
-	"time"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 
 	"app/api/models"
+	"app/config"
 	"app/pkg/helper"
+	"app/pkg/logger"
 )
 
 // Login godoc
@@ -29,6 +33,8 @@ func (h *handler) Login(c *gin.Context) {
 	var role string
 	var user_id string
 	var passw string
+	var emailVerified = true
+	var updatePassword func(ctx context.Context, id string, password string) (int64, error)
 
 	err := c.ShouldBindJSON(&login) // parse req body to given type struct
 	if err != nil {
@@ -39,9 +45,9 @@ func (h *handler) Login(c *gin.Context) {
 	admin, err := h.strg.Admin().GetByID(context.Background(), &models.AdminPrimaryKey{Email: login.Email})
 	if err == nil {
 		user_id = admin.Id
-		role = "admin"
+		role = config.RoleAdmin
 		passw = admin.Password
-		// hashedPassword = admin.Password
+		updatePassword = h.strg.Admin().UpdatePassword
 	} else {
 		// Check in HeadNurse table
 		user, err := h.strg.User().GetByID(context.Background(), &models.UserPrimaryKey{Email: login.Email})
@@ -51,8 +57,10 @@ func (h *handler) Login(c *gin.Context) {
 				return
 			}
 			user_id = user.Id
-			role = "user"
+			emailVerified = user.EmailVerified
+			role = config.RoleUser
 			passw = user.Password
+			updatePassword = h.strg.User().UpdatePassword
 		} else {
 			// No matching user found
 			if err.Error() == "no rows in result set" {
@@ -64,20 +72,267 @@ func (h *handler) Login(c *gin.Context) {
 		}
 	}
 
-	if login.Password != passw {
+	needsRehash, err := helper.ComparePassword(passw, login.Password)
+	if err != nil {
 		h.handlerResponse(c, "Wrong password", http.StatusBadRequest, "Wrong password")
 		return
 	}
 
-	token, err := helper.GenerateJWT(map[string]interface{}{
-		"user_id": user_id,
-		"role":    role,
-	}, time.Hour*24, h.cfg.SecretKey)
+	// Only told after the password matched, so it doesn't reveal accounts
+	if !emailVerified {
+		h.handlerResponse(c, "Email is not verified", http.StatusForbidden, "Email is not verified")
+		return
+	}
 
-	h.handlerResponseLogin(c, "token", http.StatusCreated, token, role, user_id)
+	// Legacy rows still hold the plaintext password, replace it with a hash
+	if needsRehash {
+		hashed, err := helper.HashPassword(login.Password)
+		if err != nil {
+			h.handlerResponse(c, "helper.HashPassword", http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		_, err = updatePassword(context.Background(), user_id, hashed)
+		if err != nil {
+			h.handlerResponse(c, "storage.updatePassword", http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	token, refreshToken, err := h.createSession(c, user_id, role)
+	if err != nil {
+		h.handlerResponse(c, "create session", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponseLogin(c, "token", http.StatusCreated, token, role, user_id, refreshToken)
 
 }
 
+// Refresh godoc
+// @ID refresh
+// @Router /refresh [POST]
+// @Summary Refresh
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated on every call.
+// @Tags Login
+// @Accept json
+// @Procedure json
+// @Param refresh body models.RefreshTokenRequest true "RefreshTokenRequest"
+// @Success 200 {object} Response{data=string} "Success Request"
+// @Response 401 {object} Response{data=string} "Unauthorized"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.handlerResponse(c, "refresh should bind json", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sessionID, secret, err := helper.SplitRefreshToken(req.RefreshToken)
+	if err != nil || !helper.IsValidUUID(sessionID) {
+		h.handlerResponse(c, "refresh token", http.StatusUnauthorized, "invalid refresh token")
+		return
+	}
+
+	session, err := h.strg.Session().GetByID(c.Request.Context(), &models.SessionPrimaryKey{Id: sessionID})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "refresh token", http.StatusUnauthorized, "invalid refresh token")
+			return
+		}
+		h.handlerResponse(c, "storage.Session.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if session.Revoked || session.Expired {
+		h.handlerResponse(c, "refresh token", http.StatusUnauthorized, "session is revoked or expired")
+		return
+	}
+
+	oldHash := helper.HashToken(secret)
+	if subtle.ConstantTimeCompare([]byte(oldHash), []byte(session.RefreshTokenHash)) != 1 {
+		// A rotated token was presented again, the session must be considered stolen
+		err = h.strg.Session().Revoke(c.Request.Context(), &models.SessionPrimaryKey{Id: session.Id})
+		if err != nil {
+			h.handlerResponse(c, "storage.Session.revoke", http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.handlerResponse(c, "refresh token reuse", http.StatusUnauthorized, "invalid refresh token")
+		return
+	}
+
+	active, err := h.isAccountActive(c.Request.Context(), session.UserID, session.Role)
+	if err != nil {
+		h.handlerResponse(c, "account status", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !active {
+		err = h.strg.Session().Revoke(c.Request.Context(), &models.SessionPrimaryKey{Id: session.Id})
+		if err != nil {
+			h.handlerResponse(c, "storage.Session.revoke", http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.handlerResponse(c, "Account is inactive", http.StatusForbidden, "Account is inactive")
+		return
+	}
+
+	newSecret, err := helper.GenerateRefreshSecret()
+	if err != nil {
+		h.handlerResponse(c, "helper.GenerateRefreshSecret", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rowsAffected, err := h.strg.Session().Rotate(c.Request.Context(), &models.RotateSession{
+		Id:                  session.Id,
+		OldRefreshTokenHash: oldHash,
+		NewRefreshTokenHash: helper.HashToken(newSecret),
+		ExpiresIn:           int64(h.cfg.RefreshTokenTTL.Seconds()),
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.Session.rotate", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if rowsAffected <= 0 {
+		h.handlerResponse(c, "storage.Session.rotate", http.StatusUnauthorized, "invalid refresh token")
+		return
+	}
+
+	token, err := h.generateAccessToken(session.UserID, session.Role, session.Id)
+	if err != nil {
+		h.handlerResponse(c, "helper.GenerateJWT", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponseLogin(c, "refresh token", http.StatusOK, token, session.Role, session.UserID, helper.JoinRefreshToken(session.Id, newSecret))
+}
+
+// @Security ApiKeyAuth
+// Logout godoc
+// @ID logout
+// @Router /logout [POST]
+// @Summary Logout
+// @Description Revoke the session of the current access token
+// @Tags Login
+// @Accept json
+// @Procedure json
+// @Success 204 {object} Response{data=string} "Success Request"
+// @Response 401 {object} Response{data=string} "Unauthorized"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) Logout(c *gin.Context) {
+	info, _ := getAuthInfo(c)
+
+	err := h.strg.Session().Revoke(c.Request.Context(), &models.SessionPrimaryKey{Id: info.SessionID})
+	if err != nil {
+		h.handlerResponse(c, "storage.Session.revoke", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "logout", http.StatusNoContent, nil)
+}
+
+// @Security ApiKeyAuth
+// LogoutAll godoc
+// @ID logout_all
+// @Router /logout/all [POST]
+// @Summary Logout All Devices
+// @Description Revoke every session of the current user
+// @Tags Login
+// @Accept json
+// @Procedure json
+// @Success 200 {object} Response{data=string} "Success Request"
+// @Response 401 {object} Response{data=string} "Unauthorized"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) LogoutAll(c *gin.Context) {
+	info, _ := getAuthInfo(c)
+
+	revoked, err := h.strg.Session().RevokeAllByUser(c.Request.Context(), info.UserID)
+	if err != nil {
+		h.handlerResponse(c, "storage.Session.revokeAllByUser", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "logout all", http.StatusOK, map[string]int64{"revoked_sessions": revoked})
+}
+
+// createSession stores a new session and returns its access and refresh tokens.
+// maxUserAgentLength is the size of sessions.user_agent, longer user agents
+// are cut to fit.
+const maxUserAgentLength = 255
+
+func (h *handler) createSession(c *gin.Context, userID, role string) (string, string, error) {
+
+	secret, err := helper.GenerateRefreshSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	sessionID, err := h.strg.Session().Create(c.Request.Context(), &models.CreateSession{
+		UserID:           userID,
+		Role:             role,
+		RefreshTokenHash: helper.HashToken(secret),
+		UserAgent:        truncateRunes(strings.ToValidUTF8(c.Request.UserAgent(), ""), maxUserAgentLength),
+		IPAddress:        c.ClientIP(),
+		ExpiresIn:        int64(h.cfg.RefreshTokenTTL.Seconds()),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := h.generateAccessToken(userID, role, sessionID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, helper.JoinRefreshToken(sessionID, secret), nil
+}
+
+// truncateRunes cuts s to at most n characters.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+func (h *handler) generateAccessToken(userID, role, sessionID string) (string, error) {
+	return helper.GenerateJWT(map[string]interface{}{
+		"user_id":    userID,
+		"role":       role,
+		"session_id": sessionID,
+	}, h.cfg.AccessTokenTTL, h.cfg.SecretKey)
+}
+
+// isAccountActive reports whether the owner of a session may still use it.
+func (h *handler) isAccountActive(ctx context.Context, userID, role string) (bool, error) {
+
+	switch role {
+	case config.RoleAdmin:
+		_, err := h.strg.Admin().GetByID(ctx, &models.AdminPrimaryKey{Id: userID})
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	case config.RoleUser:
+		user, err := h.strg.User().GetByID(ctx, &models.UserPrimaryKey{Id: userID})
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				return false, nil
+			}
+			return false, err
+		}
+		return user.Status, nil
+	default:
+		return false, nil
+	}
+}
+
 // Register godoc
 // @ID register
 // @Router /register [POST]
@@ -100,11 +355,26 @@ func (h *handler) Register(c *gin.Context) {
 		return
 	}
 
+	createUser.Email = strings.TrimSpace(createUser.Email)
+	if !helper.IsValidEmail(createUser.Email) {
+		h.handlerResponse(c, "is valid email", http.StatusBadRequest, "invalid email")
+		return
+	}
+
+	// New accounts are active but can't log in until the email is verified
+	createUser.Status = true
+
 	if len(createUser.Password) < 7 {
 		h.handlerResponse(c, "Password should inculude more than 7 elements", http.StatusBadRequest, errors.New("Password len should inculude more than 8 elements"))
 		return
 	}
 
+	createUser.Password, err = helper.HashPassword(createUser.Password)
+	if err != nil {
+		h.handlerResponse(c, "helper.HashPassword", http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	resp, err := h.strg.User().GetByID(context.Background(), &models.UserPrimaryKey{Email: createUser.Email})
 	if err != nil {
 		if err.Error() == "no rows in result set" {
@@ -122,6 +392,17 @@ func (h *handler) Register(c *gin.Context) {
 		return
 	}
 	resp, err = h.strg.User().GetByID(context.Background(), &models.UserPrimaryKey{Id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.user.getByID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The account exists at this point, a failed delivery can be retried
+	// through /register/resend
+	err = h.sendOtp(c.Request.Context(), resp.Email, models.OtpPurposeEmailVerification)
+	if err != nil {
+		h.logger.Error("send verification code", logger.Error(err))
+	}
 
 	h.handlerResponse(c, "create user resposne", http.StatusCreated, resp)
 }
@@ -102,6 +102,11 @@ func (h *handler) GetByIdCourse(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param semester_id query string false "semester_id"
+// @Param date_from query string false "created from, 2006-01-02 or RFC3339"
+// @Param date_to query string false "created until, 2006-01-02 or RFC3339"
+// @Param sort_by query string false "created_at, updated_at, course_title"
+// @Param order query string false "asc or desc, default desc"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
@@ -119,13 +124,24 @@ func (h *handler) GetListCourse(c *gin.Context) {
 		return
 	}
 
+	err = h.validateListQuery(c, "semester_id")
+	if err != nil {
+		h.handlerResponse(c, "get list Course filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
 	resp, err := h.strg.Course().GetList(c.Request.Context(), &models.CourseGetListRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+		Offset:     offset,
+		Limit:      limit,
+		Search:     c.Query("search"),
+		SemesterID: c.Query("semester_id"),
+		DateFrom:   c.Query("date_from"),
+		DateTo:     c.Query("date_to"),
+		SortBy:     c.Query("sort_by"),
+		Order:      c.Query("order"),
 	})
 	if err != nil {
-		h.handlerResponse(c, "storage.Course.get_list", http.StatusInternalServerError, err.Error())
+		h.listErrorResponse(c, "storage.Course.get_list", err)
 		return
 	}
 
@@ -226,55 +242,40 @@ func (h *handler) DeleteCourse(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
-// @Param semester_id query string true "Semester ID" // New query parameter for semester_id
+// @Param semester_id query string true "Semester ID"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
 func (h *handler) GetListCoursesBySemesterId(c *gin.Context) {
+
 	semesterId := c.Query("semester_id")
-	if semesterId == "" {
-		h.handlerResponse(c, "get list PetrolHistory by semester_id", http.StatusBadRequest, "semester_id is required")
+	if !helper.IsValidUUID(semesterId) {
+		h.handlerResponse(c, "get list Course by semester_id", http.StatusBadRequest, "valid semester_id is required")
 		return
 	}
+
 	offset, err := h.getOffsetQuery(c.Query("offset"))
 	if err != nil {
-		h.handlerResponse(c, "get list PetrolHistory offset", http.StatusBadRequest, "invalid offset")
+		h.handlerResponse(c, "get list Course offset", http.StatusBadRequest, "invalid offset")
 		return
 	}
 
-	// limit, err := h.getLimitQuery(c.Query("limit"))
-	// if err != nil {
-	// 	h.handlerResponse(c, "get list PetrolHistory limit", http.StatusBadRequest, "invalid limit")
-	// 	return
-	// }
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list Course limit", http.StatusBadRequest, "invalid limit")
+		return
+	}
 
 	resp, err := h.strg.Course().GetList(c.Request.Context(), &models.CourseGetListRequest{
-		Offset: offset,
-		Limit:  100000,
-		Search: c.Query("search"),
+		Offset:     offset,
+		Limit:      limit,
+		Search:     c.Query("search"),
+		SemesterID: semesterId,
 	})
-
-	// cars, err := h.strg.Car().GetList(c.Request.Context(), &models.CarGetListRequest{
-	// 	Offset: offset,
-	// 	Limit:  limit,
-	// 	Search: c.Query("search"),
-	// })
-	// if err != nil {
-	// 	h.handlerResponse(c, "storage.Car.get_list", http.StatusInternalServerError, err.Error())
-	// 	return
-	// }
-	var all []*models.Course
-
-	for _, ptrh := range resp.Courses {
-		if semesterId == ptrh.SemesterID {
-			all = append(all, ptrh)
-		}
-	}
-
 	if err != nil {
-		h.handlerResponse(c, "storage.Semester.get_list", http.StatusInternalServerError, err.Error())
+		h.handlerResponse(c, "storage.Course.get_list", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	h.handlerResponse(c, "get list Semester resposne", http.StatusOK, all)
+	h.handlerResponse(c, "get list Course resposne", http.StatusOK, resp)
 }
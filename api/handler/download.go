@@ -9,43 +9,6 @@ import (
 	"app/pkg/helper"
 )
 
-// @Security ApiKeyAuth
-// Create download godoc
-// @ID create_download
-// @Router /download [POST]
-// @Summary Create Download
-// @Description Create Download
-// @Tags Download
-// @Accept json
-// @Procedure json
-// @Param Download body models.CreateDownload true "CreateDownloadRequest"
-// @Success 200 {object} Response{data=string} "Success Request"
-// @Response 400 {object} Response{data=string} "Bad Request"
-// @Failure 500 {object} Response{data=string} "Server error"
-func (h *handler) CreateDownload(c *gin.Context) {
-
-	var createDownload models.CreateDownload
-	err := c.ShouldBindJSON(&createDownload)
-	if err != nil {
-		h.handlerResponse(c, "error Download should bind json", http.StatusBadRequest, err.Error())
-		return
-	}
-
-	id, err := h.strg.Download().Create(c.Request.Context(), &createDownload)
-	if err != nil {
-		h.handlerResponse(c, "storage.Download.create", http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	resp, err := h.strg.Download().GetByID(c.Request.Context(), &models.DownloadPrimaryKey{Id: id})
-	if err != nil {
-		h.handlerResponse(c, "storage.Download.getById", http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	h.handlerResponse(c, "create Download resposne", http.StatusCreated, resp)
-}
-
 // @Security ApiKeyAuth
 // GetByID download godoc
 // @ID get_by_id_download
@@ -102,6 +65,12 @@ func (h *handler) GetByIdDownload(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param publication_id query string false "publication_id"
+// @Param contributor_id query string false "contributor_id"
+// @Param date_from query string false "created from, 2006-01-02 or RFC3339"
+// @Param date_to query string false "created until, 2006-01-02 or RFC3339"
+// @Param sort_by query string false "created_at, updated_at"
+// @Param order query string false "asc or desc, default desc"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
@@ -119,69 +88,29 @@ func (h *handler) GetListDownload(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.strg.Download().GetList(c.Request.Context(), &models.DownloadGetListRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
-	})
-	if err != nil {
-		h.handlerResponse(c, "storage.Download.get_list", http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	h.handlerResponse(c, "get list Download resposne", http.StatusOK, resp)
-}
-
-// @Security ApiKeyAuth
-// Update download godoc
-// @ID update_download
-// @Router /download/{id} [PUT]
-// @Summary Update Download
-// @Description Update Download
-// @Tags Download
-// @Accept json
-// @Procedure json
-// @Param id path string true "id"
-// @Param Download body models.UpdateDownload true "UpdateDownloadRequest"
-// @Success 200 {object} Response{data=string} "Success Request"
-// @Response 400 {object} Response{data=string} "Bad Request"
-// @Failure 500 {object} Response{data=string} "Server error"
-func (h *handler) UpdateDownload(c *gin.Context) {
-
-	var (
-		id             string = c.Param("id")
-		updateDownload models.UpdateDownload
-	)
-
-	if !helper.IsValidUUID(id) {
-		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
-		return
-	}
-
-	err := c.ShouldBindJSON(&updateDownload)
+	err = h.validateListQuery(c, "publication_id", "contributor_id")
 	if err != nil {
-		h.handlerResponse(c, "error Download should bind json", http.StatusBadRequest, err.Error())
-		return
-	}
-	updateDownload.Id = id
-	rowsAffected, err := h.strg.Download().Update(c.Request.Context(), &updateDownload)
-	if err != nil {
-		h.handlerResponse(c, "storage.Download.update", http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	if rowsAffected <= 0 {
-		h.handlerResponse(c, "storage.Download.update", http.StatusBadRequest, "now rows affected")
+		h.handlerResponse(c, "get list Download filters", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	resp, err := h.strg.Download().GetByID(c.Request.Context(), &models.DownloadPrimaryKey{Id: id})
+	resp, err := h.strg.Download().GetList(c.Request.Context(), &models.DownloadGetListRequest{
+		Offset:        offset,
+		Limit:         limit,
+		Search:        c.Query("search"),
+		PublicationID: c.Query("publication_id"),
+		ContributorID: c.Query("contributor_id"),
+		DateFrom:      c.Query("date_from"),
+		DateTo:        c.Query("date_to"),
+		SortBy:        c.Query("sort_by"),
+		Order:         c.Query("order"),
+	})
 	if err != nil {
-		h.handlerResponse(c, "storage.Download.getById", http.StatusInternalServerError, err.Error())
+		h.listErrorResponse(c, "storage.Download.get_list", err)
 		return
 	}
 
-	h.handlerResponse(c, "create Download resposne", http.StatusAccepted, resp)
+	h.handlerResponse(c, "get list Download resposne", http.StatusOK, resp)
 }
 
 // @Security ApiKeyAuth
@@ -196,6 +125,8 @@ func (h *handler) UpdateDownload(c *gin.Context) {
 // @Param id path string true "id"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Response 404 {object} Response{data=string} "Not Found"
 // @Failure 500 {object} Response{data=string} "Server error"
 func (h *handler) DeleteDownload(c *gin.Context) {
 
@@ -206,11 +137,17 @@ func (h *handler) DeleteDownload(c *gin.Context) {
 		return
 	}
 
-	err := h.strg.Download().Delete(c.Request.Context(), &models.DownloadPrimaryKey{Id: id})
+	rowsAffected, err := h.strg.Download().Delete(c.Request.Context(), &models.DownloadPrimaryKey{Id: id, OwnerID: ownerScope(c)})
 	if err != nil {
 		h.handlerResponse(c, "storage.Download.delete", http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if rowsAffected <= 0 {
+		_, err = h.strg.Download().GetByID(c.Request.Context(), &models.DownloadPrimaryKey{Id: id})
+		h.notOwnedResponse(c, "storage.Download.delete", "download", err)
+		return
+	}
+
 	h.handlerResponse(c, "create Download resposne", http.StatusNoContent, nil)
 }
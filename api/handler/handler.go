@@ -2,9 +2,16 @@ package handler
 
 import (
 	"app/config"
+	"app/pkg/blobstore"
+	"app/pkg/helper"
 	"app/pkg/logger"
+	"app/pkg/mailer"
 	"app/storage"
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +20,8 @@ type handler struct {
 	cfg    *config.Config
 	logger logger.LoggerI
 	strg   storage.StorageI
+	mailer mailer.Mailer
+	blobs  blobstore.Store
 }
 
 type Response struct {
@@ -21,11 +30,13 @@ type Response struct {
 	Data        interface{} `json:"data"`
 }
 
-func NewHandler(cfg *config.Config, storage storage.StorageI, logger logger.LoggerI) *handler {
+func NewHandler(cfg *config.Config, storage storage.StorageI, logger logger.LoggerI, mailer mailer.Mailer, blobs blobstore.Store) *handler {
 	return &handler{
 		cfg:    cfg,
 		logger: logger,
 		strg:   storage,
+		mailer: mailer,
+		blobs:  blobs,
 	}
 }
 
@@ -46,26 +57,34 @@ func (h *handler) handlerResponse(c *gin.Context, path string, code int, message
 }
 
 type ResponseLogin struct {
-	Status      int         `json:"status"`
-	Description string      `json:"description"`
-	Data        interface{} `json:"data"`
-	Role        string      `json:"role"`
-	UserID      string      `json:"user_id"`
+	Status       int         `json:"status"`
+	Description  string      `json:"description"`
+	Data         interface{} `json:"data"`
+	Role         string      `json:"role"`
+	UserID       string      `json:"user_id"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int64       `json:"expires_in"`
 }
 
-func (h *handler) handlerResponseLogin(c *gin.Context, path string, code int, message interface{}, role string, user_id string) {
+func (h *handler) handlerResponseLogin(c *gin.Context, path string, code int, message interface{}, role string, user_id string, refresh_token string) {
 	response := ResponseLogin{
-		Status: code,
-		Data:   message,
-		Role:   role,
-		UserID: user_id,
+		Status:       code,
+		Data:         message,
+		Role:         role,
+		UserID:       user_id,
+		RefreshToken: refresh_token,
+		ExpiresIn:    int64(h.cfg.AccessTokenTTL.Seconds()),
 	}
 
+	logged := response
+	logged.Data = nil
+	logged.RefreshToken = ""
+
 	switch {
 	case code < 300:
-		h.logger.Info(path, logger.Any("info", response))
+		h.logger.Info(path, logger.Any("info", logged))
 	case code >= 400:
-		h.logger.Error(path, logger.Any("error", response))
+		h.logger.Error(path, logger.Any("error", logged))
 	}
 
 	c.JSON(code, response)
@@ -88,3 +107,36 @@ func (h *handler) getLimitQuery(limit string) (int, error) {
 
 	return strconv.Atoi(limit)
 }
+
+// validateListQuery checks the filters shared by the GetList endpoints and
+// the given query parameters that must hold a UUID.
+func (h *handler) validateListQuery(c *gin.Context, uuidParams ...string) error {
+
+	for _, name := range []string{"date_from", "date_to"} {
+		if value := c.Query(name); len(value) > 0 && !helper.IsValidDate(value) {
+			return fmt.Errorf("invalid %s, use 2006-01-02 or RFC3339", name)
+		}
+	}
+
+	if order := c.Query("order"); len(order) > 0 && !strings.EqualFold(order, "asc") && !strings.EqualFold(order, "desc") {
+		return errors.New("invalid order, use asc or desc")
+	}
+
+	for _, name := range uuidParams {
+		if value := c.Query(name); len(value) > 0 && !helper.IsValidUUID(value) {
+			return fmt.Errorf("invalid %s", name)
+		}
+	}
+
+	return nil
+}
+
+// listErrorResponse answers a failed GetList: an unknown sort field is the
+// caller's mistake, anything else is a server error.
+func (h *handler) listErrorResponse(c *gin.Context, path string, err error) {
+	if errors.Is(err, helper.ErrInvalidSort) {
+		h.handlerResponse(c, path, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.handlerResponse(c, path, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"app/api/models"
+	"app/pkg/imaging"
+	"app/pkg/logger"
+	"app/pkg/upload"
+)
+
+// storeVariant stores a rendition of an image file. When the variant was
+// registered concurrently the existing one is returned.
+func (h *handler) storeVariant(ctx context.Context, file *models.File, image *imaging.Encoded) (*models.FileVariant, error) {
+
+	sum := sha256.Sum256(image.Data)
+	sha := hex.EncodeToString(sum[:])
+
+	blob, err := h.putBlob(ctx, &models.CreateBlob{
+		Sha256:   sha,
+		Size:     int64(len(image.Data)),
+		MimeType: image.ContentType,
+	}, func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(image.Data)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.FileVariantPrimaryKey{FileID: file.Id, Variant: string(image.Variant)}
+
+	created, err := h.strg.File().CreateVariant(ctx, &models.CreateFileVariant{
+		FileID:     file.Id,
+		Variant:    string(image.Variant),
+		Sha256:     sha,
+		StorageKey: blob.StorageKey,
+		Size:       int64(len(image.Data)),
+		MimeType:   image.ContentType,
+		Width:      image.Width,
+		Height:     image.Height,
+	})
+	if err != nil || created <= 0 {
+		if err := h.strg.Blob().Release(ctx, &models.BlobPrimaryKey{Sha256: sha}); err != nil {
+			h.logger.Error("storage.Blob.release", logger.Error(err))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return h.strg.File().GetVariant(ctx, key)
+}
+
+// imageVariant returns the file to serve for the size query parameter of
+// an image request: the stored variant, rendered now if the image was
+// uploaded before variants existed. Files without variants, such as blobs
+// stored before the files table, and variants that fail to render fall
+// back to the original. On failure the response is already written.
+func (h *handler) imageVariant(c *gin.Context, file *models.File) (*models.File, bool) {
+
+	variant, err := imaging.ParseVariant(c.Query("size"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "size must be original, medium or thumb"})
+		return nil, false
+	}
+
+	if variant == imaging.VariantOriginal || len(file.Id) <= 0 || !upload.Processed(upload.Kind(file.Kind)) {
+		return file, true
+	}
+
+	ctx := c.Request.Context()
+
+	stored, err := h.strg.File().GetVariant(ctx, &models.FileVariantPrimaryKey{FileID: file.Id, Variant: string(variant)})
+	if err != nil {
+		if err.Error() != "no rows in result set" {
+			h.logger.Error("storage.File.getVariant", logger.Error(err))
+			return file, true
+		}
+
+		stored, err = h.renderVariant(ctx, file, variant)
+		if err != nil {
+			h.logger.Error("render image variant", logger.String("file_id", file.Id), logger.Error(err))
+			return file, true
+		}
+	}
+
+	resp := *file
+	resp.Sha256 = stored.Sha256
+	resp.StorageKey = stored.StorageKey
+	resp.Size = stored.Size
+	resp.MimeType = stored.MimeType
+
+	return &resp, true
+}
+
+// renderVariant renders and stores a variant from the original of an
+// image file.
+func (h *handler) renderVariant(ctx context.Context, file *models.File, variant imaging.Variant) (*models.FileVariant, error) {
+
+	policy, err := upload.PolicyFor(upload.Kind(file.Kind), h.uploadLimits())
+	if err != nil {
+		return nil, err
+	}
+
+	content, _, err := h.blobs.Get(ctx, file.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	// Originals stored before the limits existed may be larger
+	data, err := io.ReadAll(io.LimitReader(content, policy.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > policy.MaxSize {
+		return nil, upload.ErrTooLarge
+	}
+
+	images, err := imaging.Process(data, variant)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.storeVariant(ctx, file, images[0])
+}
+
+// imageURL is the path of a variant of the image file with the given id.
+func imageURL(route, id string, variant imaging.Variant) string {
+	return route + id + "?size=" + string(variant)
+}
+
+// setImageThumbnails points the publications at the thumbnail of their
+// cover, so lists don't make clients fetch full size images.
+func setImageThumbnails(publications ...*models.Publication) {
+	for _, publication := range publications {
+		if len(publication.ImageID) > 0 {
+			publication.ImageThumbnail = imageURL("/image/", publication.ImageID, imaging.VariantThumb)
+		}
+	}
+}
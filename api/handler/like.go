@@ -6,44 +6,82 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"app/api/models"
+	"app/config"
 	"app/pkg/helper"
 )
 
 // @Security ApiKeyAuth
-// Create like godoc
-// @ID create_like
-// @Router /like [POST]
-// @Summary Create Like
-// @Description Create Like
+// LikePublication godoc
+// @ID like_publication
+// @Router /publication/{id}/like [POST]
+// @Summary Like Publication
+// @Description Like the publication as the current user. Liking twice keeps a single like.
 // @Tags Like
 // @Accept json
 // @Procedure json
-// @Param Like body models.CreateLike true "CreateLikeRequest"
-// @Success 200 {object} Response{data=string} "Success Request"
+// @Param id path string true "publication id"
+// @Success 200 {object} Response{data=models.LikeStatus} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not a user"
+// @Response 404 {object} Response{data=string} "Not Found"
 // @Failure 500 {object} Response{data=string} "Server error"
-func (h *handler) CreateLike(c *gin.Context) {
+func (h *handler) LikePublication(c *gin.Context) {
 
-	var createLike models.CreateLike
-	err := c.ShouldBindJSON(&createLike)
-	if err != nil {
-		h.handlerResponse(c, "error Like should bind json", http.StatusBadRequest, err.Error())
+	key, ok := h.getLikeKey(c)
+	if !ok {
 		return
 	}
 
-	id, err := h.strg.Like().Create(c.Request.Context(), &createLike)
+	_, err := h.strg.Like().Create(c.Request.Context(), key)
 	if err != nil {
 		h.handlerResponse(c, "storage.Like.create", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	resp, err := h.strg.Like().GetByID(c.Request.Context(), &models.LikePrimaryKey{Id: id})
+	resp, err := h.strg.Like().GetStatus(c.Request.Context(), key)
 	if err != nil {
-		h.handlerResponse(c, "storage.Like.getById", http.StatusInternalServerError, err.Error())
+		h.handlerResponse(c, "storage.Like.getStatus", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "like Publication resposne", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// UnlikePublication godoc
+// @ID unlike_publication
+// @Router /publication/{id}/like [DELETE]
+// @Summary Unlike Publication
+// @Description Remove the like of the current user. Unliking a publication that isn't liked is not an error.
+// @Tags Like
+// @Accept json
+// @Procedure json
+// @Param id path string true "publication id"
+// @Success 200 {object} Response{data=models.LikeStatus} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not a user"
+// @Response 404 {object} Response{data=string} "Not Found"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) UnlikePublication(c *gin.Context) {
+
+	key, ok := h.getLikeKey(c)
+	if !ok {
+		return
+	}
+
+	_, err := h.strg.Like().DeleteByKey(c.Request.Context(), key)
+	if err != nil {
+		h.handlerResponse(c, "storage.Like.deleteByKey", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	h.handlerResponse(c, "create Like resposne", http.StatusCreated, resp)
+	resp, err := h.strg.Like().GetStatus(c.Request.Context(), key)
+	if err != nil {
+		h.handlerResponse(c, "storage.Like.getStatus", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "unlike Publication resposne", http.StatusOK, resp)
 }
 
 // @Security ApiKeyAuth
@@ -102,6 +140,12 @@ func (h *handler) GetByIdLike(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param publication_id query string false "publication_id"
+// @Param contributor_id query string false "contributor_id"
+// @Param date_from query string false "created from, 2006-01-02 or RFC3339"
+// @Param date_to query string false "created until, 2006-01-02 or RFC3339"
+// @Param sort_by query string false "created_at, updated_at"
+// @Param order query string false "asc or desc, default desc"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
@@ -119,13 +163,25 @@ func (h *handler) GetListLike(c *gin.Context) {
 		return
 	}
 
+	err = h.validateListQuery(c, "publication_id", "contributor_id")
+	if err != nil {
+		h.handlerResponse(c, "get list Like filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
 	resp, err := h.strg.Like().GetList(c.Request.Context(), &models.LikeGetListRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+		Offset:        offset,
+		Limit:         limit,
+		Search:        c.Query("search"),
+		PublicationID: c.Query("publication_id"),
+		ContributorID: c.Query("contributor_id"),
+		DateFrom:      c.Query("date_from"),
+		DateTo:        c.Query("date_to"),
+		SortBy:        c.Query("sort_by"),
+		Order:         c.Query("order"),
 	})
 	if err != nil {
-		h.handlerResponse(c, "storage.Like.get_list", http.StatusInternalServerError, err.Error())
+		h.listErrorResponse(c, "storage.Like.get_list", err)
 		return
 	}
 
@@ -133,84 +189,80 @@ func (h *handler) GetListLike(c *gin.Context) {
 }
 
 // @Security ApiKeyAuth
-// Update like godoc
-// @ID update_like
-// @Router /like/{id} [PUT]
-// @Summary Update Like
-// @Description Update Like
+// Delete like godoc
+// @ID delete_like
+// @Router /like/{id} [DELETE]
+// @Summary Delete Like
+// @Description Delete Like
 // @Tags Like
 // @Accept json
 // @Procedure json
 // @Param id path string true "id"
-// @Param Like body models.UpdateLike true "UpdateLikeRequest"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Response 404 {object} Response{data=string} "Not Found"
 // @Failure 500 {object} Response{data=string} "Server error"
-func (h *handler) UpdateLike(c *gin.Context) {
+func (h *handler) DeleteLike(c *gin.Context) {
 
-	var (
-		id         string = c.Param("id")
-		updateLike models.UpdateLike
-	)
+	var id string = c.Param("id")
 
 	if !helper.IsValidUUID(id) {
 		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
 		return
 	}
 
-	err := c.ShouldBindJSON(&updateLike)
+	rowsAffected, err := h.strg.Like().Delete(c.Request.Context(), &models.LikePrimaryKey{Id: id, OwnerID: ownerScope(c)})
 	if err != nil {
-		h.handlerResponse(c, "error Like should bind json", http.StatusBadRequest, err.Error())
-		return
-	}
-	updateLike.Id = id
-	rowsAffected, err := h.strg.Like().Update(c.Request.Context(), &updateLike)
-	if err != nil {
-		h.handlerResponse(c, "storage.Like.update", http.StatusInternalServerError, err.Error())
+		h.handlerResponse(c, "storage.Like.delete", http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	if rowsAffected <= 0 {
-		h.handlerResponse(c, "storage.Like.update", http.StatusBadRequest, "now rows affected")
+		_, err = h.strg.Like().GetByID(c.Request.Context(), &models.LikePrimaryKey{Id: id})
+		h.notOwnedResponse(c, "storage.Like.delete", "like", err)
 		return
 	}
 
-	resp, err := h.strg.Like().GetByID(c.Request.Context(), &models.LikePrimaryKey{Id: id})
-	if err != nil {
-		h.handlerResponse(c, "storage.Like.getById", http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	h.handlerResponse(c, "create Like resposne", http.StatusAccepted, resp)
+	h.handlerResponse(c, "create Like resposne", http.StatusNoContent, nil)
 }
 
-// @Security ApiKeyAuth
-// Delete like godoc
-// @ID delete_like
-// @Router /like/{id} [DELETE]
-// @Summary Delete Like
-// @Description Delete Like
-// @Tags Like
-// @Accept json
-// @Procedure json
-// @Param id path string true "id"
-// @Success 200 {object} Response{data=string} "Success Request"
-// @Response 400 {object} Response{data=string} "Bad Request"
-// @Failure 500 {object} Response{data=string} "Server error"
-func (h *handler) DeleteLike(c *gin.Context) {
+// getLikeKey builds the like of the caller, who must be a user, on the
+// publication in the path and checks that the caller can see the
+// publication. On failure the response is already written.
+func (h *handler) getLikeKey(c *gin.Context) (*models.LikeKey, bool) {
 
 	var id string = c.Param("id")
 
 	if !helper.IsValidUUID(id) {
 		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
-		return
+		return nil, false
+	}
+
+	// Likes belong to user accounts, admins have none to like with
+	info, _ := getAuthInfo(c)
+	if info.Role != config.RoleUser {
+		h.handlerResponse(c, "like permission", http.StatusForbidden, "only users can like publications")
+		return nil, false
 	}
 
-	err := h.strg.Like().Delete(c.Request.Context(), &models.LikePrimaryKey{Id: id})
+	publication, err := h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
 	if err != nil {
-		h.handlerResponse(c, "storage.Like.delete", http.StatusInternalServerError, err.Error())
-		return
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.Publication.getById", http.StatusNotFound, "publication not found")
+			return nil, false
+		}
+		h.handlerResponse(c, "storage.Publication.getById", http.StatusInternalServerError, err.Error())
+		return nil, false
 	}
 
-	h.handlerResponse(c, "create Like resposne", http.StatusNoContent, nil)
+	if !canViewPublication(c, publication) {
+		h.handlerResponse(c, "storage.Publication.getById", http.StatusNotFound, "publication not found")
+		return nil, false
+	}
+
+	return &models.LikeKey{
+		PublicationID: id,
+		ContributorID: info.UserID,
+	}, true
 }
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"app/jobs"
+	"app/pkg/logger"
+)
+
+// @Security ApiKeyAuth
+// CollectGarbage godoc
+// @ID collect_garbage
+// @Router /admin/gc [POST]
+// @Summary Collect Garbage
+// @Description Delete files no publication or user refers to, unreferenced blobs and stray objects in the blob store that are older than GC_GRACE_PERIOD. Runs as a dry run unless dry_run=false.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param dry_run query bool false "only report what would be deleted, default true"
+// @Success 200 {object} Response{data=models.GarbageReport} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) CollectGarbage(c *gin.Context) {
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		h.handlerResponse(c, "collect garbage dry_run", http.StatusBadRequest, "dry_run must be true or false")
+		return
+	}
+
+	report, err := jobs.CollectGarbage(c.Request.Context(), h.strg, h.blobs, h.logger, jobs.GCOptions{
+		GracePeriod: h.cfg.GCGracePeriod,
+		BatchSize:   h.cfg.GCBatchSize,
+		DryRun:      dryRun,
+	})
+	if err != nil {
+		h.logger.Error("jobs.CollectGarbage", logger.Error(err))
+		h.handlerResponse(c, "jobs.CollectGarbage", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "collect garbage resposne", http.StatusOK, report)
+}
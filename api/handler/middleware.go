@@ -1,26 +1,167 @@
 package handler
 
 import (
-	"app/pkg/helper"
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"app/api/models"
+	"app/config"
+	"app/pkg/helper"
 )
 
+const authInfoKey = "auth_info"
+
 func (h *handler) AuthMiddleware() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 
 		value := c.GetHeader("Authorization")
+		if len(value) <= 0 {
+			h.handlerResponse(c, "auth middleware", http.StatusUnauthorized, "authorization header is required")
+			c.Abort()
+			return
+		}
 
-		info, err := helper.ParseClaims(value, h.cfg.SecretKey)
-
+		info, code, err := h.authenticate(c.Request.Context(), value)
 		if err != nil {
-			c.AbortWithError(http.StatusForbidden, err)
+			h.handlerResponse(c, "auth middleware", code, err.Error())
+			c.Abort()
 			return
 		}
 
-		c.Set("user_id", info)
+		c.Set(authInfoKey, info)
+		c.Set("user_id", info.UserID)
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware identifies the caller when a valid token is sent
+// and lets guests through otherwise. It is meant for public reads that add
+// caller specific fields such as liked_by_me.
+func (h *handler) OptionalAuthMiddleware() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		value := c.GetHeader("Authorization")
+		if len(value) > 0 {
+			info, _, err := h.authenticate(c.Request.Context(), value)
+			if err == nil {
+				c.Set(authInfoKey, info)
+				c.Set("user_id", info.UserID)
+			}
+		}
+
 		c.Next()
 	}
 }
+
+// authenticate validates the token, its session and the account behind it.
+// On failure it returns the status code to answer with.
+func (h *handler) authenticate(ctx context.Context, value string) (helper.TokenInfo, int, error) {
+
+	info, err := helper.ParseClaims(value, h.cfg.SecretKey)
+	if err != nil || !helper.IsValidUUID(info.SessionID) {
+		return helper.TokenInfo{}, http.StatusUnauthorized, errors.New("invalid token")
+	}
+
+	session, err := h.strg.Session().GetByID(ctx, &models.SessionPrimaryKey{Id: info.SessionID})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return helper.TokenInfo{}, http.StatusUnauthorized, errors.New("invalid token")
+		}
+		return helper.TokenInfo{}, http.StatusInternalServerError, err
+	}
+
+	if session.Revoked || session.UserID != info.UserID {
+		return helper.TokenInfo{}, http.StatusUnauthorized, errors.New("session is revoked")
+	}
+
+	active, err := h.isAccountActive(ctx, info.UserID, info.Role)
+	if err != nil {
+		return helper.TokenInfo{}, http.StatusInternalServerError, err
+	}
+
+	if !active {
+		return helper.TokenInfo{}, http.StatusForbidden, errors.New("Account is inactive")
+	}
+
+	return info, http.StatusOK, nil
+}
+
+// RoleMiddleware lets the request through only when the authenticated
+// caller has one of the given roles. It must run after AuthMiddleware.
+func (h *handler) RoleMiddleware(roles ...string) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		info, ok := getAuthInfo(c)
+		if !ok {
+			h.handlerResponse(c, "role middleware", http.StatusUnauthorized, "unauthorized")
+			c.Abort()
+			return
+		}
+
+		for _, role := range roles {
+			if info.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		h.handlerResponse(c, "role middleware", http.StatusForbidden, "permission denied")
+		c.Abort()
+	}
+}
+
+func getAuthInfo(c *gin.Context) (helper.TokenInfo, bool) {
+	val, exist := c.Get(authInfoKey)
+	if !exist {
+		return helper.TokenInfo{}, false
+	}
+
+	info, ok := val.(helper.TokenInfo)
+	return info, ok
+}
+
+func isAdmin(c *gin.Context) bool {
+	info, ok := getAuthInfo(c)
+	return ok && info.Role == config.RoleAdmin
+}
+
+// isSelfOrAdmin reports whether the caller is the given user or an admin.
+func isSelfOrAdmin(c *gin.Context, userID string) bool {
+	info, ok := getAuthInfo(c)
+	if !ok {
+		return false
+	}
+
+	return info.Role == config.RoleAdmin || (info.Role == config.RoleUser && info.UserID == userID)
+}
+
+// ownerScope is the OwnerID that limits the changes of the caller to their
+// own rows. It is empty for admins, who may change any row.
+func ownerScope(c *gin.Context) string {
+	if isAdmin(c) {
+		return ""
+	}
+
+	info, _ := getAuthInfo(c)
+	return info.UserID
+}
+
+// notOwnedResponse answers a change limited by ownerScope that matched no
+// row. lookupErr is the result of loading the row without that limit: the
+// row exists, so it belongs to someone else, or it is not found.
+func (h *handler) notOwnedResponse(c *gin.Context, path, name string, lookupErr error) {
+	switch {
+	case lookupErr == nil:
+		h.handlerResponse(c, path, http.StatusForbidden, "permission denied")
+	case lookupErr.Error() == "no rows in result set":
+		h.handlerResponse(c, path, http.StatusNotFound, name+" not found")
+	default:
+		h.handlerResponse(c, path, http.StatusInternalServerError, lookupErr.Error())
+	}
+}
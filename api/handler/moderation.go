@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/moderation"
+)
+
+// @Security ApiKeyAuth
+// SubmitPublication godoc
+// @ID submit_publication
+// @Router /publication/{id}/submit [POST]
+// @Summary Submit Publication
+// @Description Submit a draft or rejected publication for review
+// @Tags Publication
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.Publication} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Response 409 {object} Response{data=string} "Not allowed in the current status"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) SubmitPublication(c *gin.Context) {
+	h.moveOwnPublication(c, moderation.PendingReview)
+}
+
+// @Security ApiKeyAuth
+// WithdrawPublication godoc
+// @ID withdraw_publication
+// @Router /publication/{id}/withdraw [POST]
+// @Summary Withdraw Publication
+// @Description Take a publication waiting for review back to draft
+// @Tags Publication
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.Publication} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Response 409 {object} Response{data=string} "Not allowed in the current status"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) WithdrawPublication(c *gin.Context) {
+	h.moveOwnPublication(c, moderation.Draft)
+}
+
+// @Security ApiKeyAuth
+// ArchivePublication godoc
+// @ID archive_publication
+// @Router /publication/{id}/archive [POST]
+// @Summary Archive Publication
+// @Description Archive a draft, published or rejected publication
+// @Tags Publication
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.Publication} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Response 409 {object} Response{data=string} "Not allowed in the current status"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) ArchivePublication(c *gin.Context) {
+	h.moveOwnPublication(c, moderation.Archived)
+}
+
+// @Security ApiKeyAuth
+// ApprovePublication godoc
+// @ID approve_publication
+// @Router /admin/publication/{id}/approve [POST]
+// @Summary Approve Publication
+// @Description Publish a publication waiting for review
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Param Review body models.PublicationReview false "optional note"
+// @Success 200 {object} Response{data=models.Publication} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 404 {object} Response{data=string} "Not Found"
+// @Response 409 {object} Response{data=string} "Not waiting for review or claimed by another moderator"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) ApprovePublication(c *gin.Context) {
+	h.reviewPublication(c, moderation.Published)
+}
+
+// @Security ApiKeyAuth
+// RejectPublication godoc
+// @ID reject_publication
+// @Router /admin/publication/{id}/reject [POST]
+// @Summary Reject Publication
+// @Description Reject a publication waiting for review. The reason is shown to the contributor.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Param Review body models.PublicationReview true "reason"
+// @Success 200 {object} Response{data=models.Publication} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 404 {object} Response{data=string} "Not Found"
+// @Response 409 {object} Response{data=string} "Not waiting for review or claimed by another moderator"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) RejectPublication(c *gin.Context) {
+	h.reviewPublication(c, moderation.Rejected)
+}
+
+// @Security ApiKeyAuth
+// GetPublicationTransitions godoc
+// @ID get_publication_transitions
+// @Router /publication/{id}/transitions [GET]
+// @Summary Get Publication Transitions
+// @Description Moderation history of a publication, oldest first, with rejection reasons
+// @Tags Publication
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=[]models.PublicationTransition} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) GetPublicationTransitions(c *gin.Context) {
+
+	var id string = c.Param("id")
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if !h.canReadHistory(c, id) {
+		return
+	}
+
+	resp, err := h.strg.Publication().GetTransitions(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.Publication.getTransitions", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get Publication transitions resposne", http.StatusOK, resp)
+}
+
+// moveOwnPublication changes the status of a publication of the caller.
+func (h *handler) moveOwnPublication(c *gin.Context, to moderation.Status) {
+
+	var id string = c.Param("id")
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	h.transitionPublication(c, &models.PublicationTransitionRequest{
+		Id:      id,
+		To:      string(to),
+		OwnerID: ownerScope(c),
+	})
+}
+
+// reviewPublication approves or rejects a publication for an admin.
+func (h *handler) reviewPublication(c *gin.Context, to moderation.Status) {
+
+	var (
+		id     string = c.Param("id")
+		review models.PublicationReview
+	)
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&review); err != nil {
+			h.handlerResponse(c, "error Review should bind json", http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	h.transitionPublication(c, &models.PublicationTransitionRequest{
+		Id:     id,
+		To:     string(to),
+		Reason: strings.TrimSpace(review.Reason),
+	})
+}
+
+// transitionPublication applies a status change by the caller and responds
+// with the updated publication.
+func (h *handler) transitionPublication(c *gin.Context, req *models.PublicationTransitionRequest) {
+
+	info, _ := getAuthInfo(c)
+	req.ActorID = info.UserID
+
+	err := h.strg.Publication().Transition(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case err.Error() == "no rows in result set":
+			_, err = h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: req.Id})
+			h.notOwnedResponse(c, "storage.Publication.transition", "publication", err)
+		case errors.Is(err, moderation.ErrReasonRequired):
+			h.handlerResponse(c, "storage.Publication.transition", http.StatusBadRequest, err.Error())
+		case errors.Is(err, moderation.ErrInvalidTransition), errors.Is(err, moderation.ErrInvalidStatus), errors.Is(err, moderation.ErrClaimed):
+			h.handlerResponse(c, "storage.Publication.transition", http.StatusConflict, err.Error())
+		default:
+			h.handlerResponse(c, "storage.Publication.transition", http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	resp, err := h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: req.Id})
+	if err != nil {
+		h.handlerResponse(c, "storage.Publication.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	setImageThumbnails(resp)
+
+	h.handlerResponse(c, "transition Publication resposne", http.StatusOK, resp)
+}
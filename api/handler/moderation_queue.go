@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/logger"
+	"app/pkg/moderation"
+)
+
+// maxBulkReview is how many publications one bulk approve or reject may
+// name.
+const maxBulkReview = 100
+
+// @Security ApiKeyAuth
+// GetModerationQueue godoc
+// @ID get_moderation_queue
+// @Router /admin/moderation/queue [GET]
+// @Summary Get Moderation Queue
+// @Description Publications waiting for review, oldest submission first, with contributor, file, claim and duplicate-file hints
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Param course_id query string false "course_id"
+// @Param available query bool false "hide items claimed by other moderators"
+// @Success 200 {object} Response{data=models.ModerationQueueResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) GetModerationQueue(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get moderation queue offset", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get moderation queue limit", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	err = h.validateListQuery(c, "course_id")
+	if err != nil {
+		h.handlerResponse(c, "get moderation queue filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	available, err := strconv.ParseBool(c.DefaultQuery("available", "false"))
+	if err != nil {
+		h.handlerResponse(c, "get moderation queue available", http.StatusBadRequest, "available must be true or false")
+		return
+	}
+
+	info, _ := getAuthInfo(c)
+
+	resp, err := h.strg.Moderation().GetQueue(c.Request.Context(), &models.ModerationQueueRequest{
+		Offset:      offset,
+		Limit:       limit,
+		CourseID:    c.Query("course_id"),
+		ModeratorID: info.UserID,
+		Available:   available,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.Moderation.getQueue", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	for _, item := range resp.Items {
+		setImageThumbnails(item.Publication)
+		item.Duplicates = h.duplicateFiles(c, item.Publication)
+	}
+
+	h.handlerResponse(c, "get moderation queue resposne", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// ClaimPublication godoc
+// @ID claim_publication
+// @Router /admin/moderation/queue/{id}/claim [POST]
+// @Summary Claim Publication
+// @Description Reserve a publication waiting for review for MODERATION_CLAIM_TTL so no other moderator reviews it. Claiming again extends the claim.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=models.ModerationClaim} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 404 {object} Response{data=string} "Not Found"
+// @Response 409 {object} Response{data=models.ModerationClaim} "Claimed by another moderator or not waiting for review"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) ClaimPublication(c *gin.Context) {
+
+	var id string = c.Param("id")
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	info, _ := getAuthInfo(c)
+
+	claim, err := h.strg.Moderation().Claim(c.Request.Context(), &models.ModerationClaimRequest{
+		PublicationID: id,
+		ModeratorID:   info.UserID,
+		TTL:           int64(h.cfg.ModerationClaimTTL.Seconds()),
+	})
+	if err != nil {
+		switch {
+		case err.Error() == "no rows in result set":
+			h.handlerResponse(c, "storage.Moderation.claim", http.StatusNotFound, "publication not found")
+		case errors.Is(err, moderation.ErrClaimed):
+			h.handlerResponse(c, "storage.Moderation.claim", http.StatusConflict, claim)
+		case errors.Is(err, moderation.ErrNotPending):
+			h.handlerResponse(c, "storage.Moderation.claim", http.StatusConflict, err.Error())
+		default:
+			h.handlerResponse(c, "storage.Moderation.claim", http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.handlerResponse(c, "claim Publication resposne", http.StatusOK, claim)
+}
+
+// @Security ApiKeyAuth
+// ReleasePublication godoc
+// @ID release_publication
+// @Router /admin/moderation/queue/{id}/claim [DELETE]
+// @Summary Release Publication
+// @Description Give up the claim on a publication. With force=true the claim of another moderator is dropped too.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Param force query bool false "drop another moderator's claim"
+// @Success 204 {object} Response{data=string} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 404 {object} Response{data=string} "No claim to release"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) ReleasePublication(c *gin.Context) {
+
+	var id string = c.Param("id")
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err != nil {
+		h.handlerResponse(c, "release Publication force", http.StatusBadRequest, "force must be true or false")
+		return
+	}
+
+	info, _ := getAuthInfo(c)
+
+	rowsAffected, err := h.strg.Moderation().Release(c.Request.Context(), &models.ModerationReleaseRequest{
+		PublicationID: id,
+		ModeratorID:   info.UserID,
+		Force:         force,
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.Moderation.release", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if rowsAffected <= 0 {
+		h.handlerResponse(c, "storage.Moderation.release", http.StatusNotFound, "no claim to release")
+		return
+	}
+
+	h.handlerResponse(c, "release Publication resposne", http.StatusNoContent, nil)
+}
+
+// @Security ApiKeyAuth
+// BulkApprovePublications godoc
+// @ID bulk_approve_publications
+// @Router /admin/moderation/approve [POST]
+// @Summary Bulk Approve Publications
+// @Description Publish several publications waiting for review. Each item succeeds or fails on its own.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param Review body models.ModerationBulkRequest true "ids and an optional note"
+// @Success 200 {object} Response{data=models.ModerationBulkResult} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) BulkApprovePublications(c *gin.Context) {
+	h.reviewPublications(c, moderation.Published)
+}
+
+// @Security ApiKeyAuth
+// BulkRejectPublications godoc
+// @ID bulk_reject_publications
+// @Router /admin/moderation/reject [POST]
+// @Summary Bulk Reject Publications
+// @Description Reject several publications waiting for review with the same reason. Each item succeeds or fails on its own.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param Review body models.ModerationBulkRequest true "ids and reason"
+// @Success 200 {object} Response{data=models.ModerationBulkResult} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) BulkRejectPublications(c *gin.Context) {
+	h.reviewPublications(c, moderation.Rejected)
+}
+
+// @Security ApiKeyAuth
+// GetModerationStats godoc
+// @ID get_moderation_stats
+// @Router /admin/moderation/stats [GET]
+// @Summary Get Moderation Stats
+// @Description Queue length and, per moderator, approvals, rejections, mean review time and live claims
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param date_from query string false "decisions from, 2006-01-02 or RFC3339"
+// @Param date_to query string false "decisions until, 2006-01-02 or RFC3339"
+// @Success 200 {object} Response{data=models.ModerationStats} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) GetModerationStats(c *gin.Context) {
+
+	err := h.validateListQuery(c)
+	if err != nil {
+		h.handlerResponse(c, "get moderation stats filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.strg.Moderation().GetStats(c.Request.Context(), &models.ModerationStatsRequest{
+		DateFrom: c.Query("date_from"),
+		DateTo:   c.Query("date_to"),
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.Moderation.getStats", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get moderation stats resposne", http.StatusOK, resp)
+}
+
+// reviewPublications applies the same decision to every publication of a
+// bulk request, each in its own transaction.
+func (h *handler) reviewPublications(c *gin.Context, to moderation.Status) {
+
+	var review models.ModerationBulkRequest
+
+	if err := c.ShouldBindJSON(&review); err != nil {
+		h.handlerResponse(c, "error Review should bind json", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	review.Reason = strings.TrimSpace(review.Reason)
+
+	if len(review.Ids) <= 0 || len(review.Ids) > maxBulkReview {
+		h.handlerResponse(c, "bulk review ids", http.StatusBadRequest, "ids must name between 1 and "+strconv.Itoa(maxBulkReview)+" publications")
+		return
+	}
+
+	if to == moderation.Rejected && len(review.Reason) <= 0 {
+		h.handlerResponse(c, "bulk review reason", http.StatusBadRequest, moderation.ErrReasonRequired.Error())
+		return
+	}
+
+	var (
+		info, _ = getAuthInfo(c)
+		resp    = &models.ModerationBulkResult{}
+		seen    = map[string]bool{}
+	)
+
+	for _, id := range review.Ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		item := &models.ModerationBulkItem{Id: id}
+		resp.Items = append(resp.Items, item)
+
+		if !helper.IsValidUUID(id) {
+			item.Error = "invalid id"
+			resp.Failed++
+			continue
+		}
+
+		err := h.strg.Publication().Transition(c.Request.Context(), &models.PublicationTransitionRequest{
+			Id:      id,
+			To:      string(to),
+			ActorID: info.UserID,
+			Reason:  review.Reason,
+		})
+		switch {
+		case err == nil:
+			item.Status = string(to)
+			resp.Succeeded++
+			continue
+		case err.Error() == "no rows in result set":
+			item.Error = "publication not found"
+		case errors.Is(err, moderation.ErrInvalidTransition):
+			item.Error = moderation.ErrNotPending.Error()
+		case errors.Is(err, moderation.ErrClaimed):
+			item.Error = err.Error()
+		default:
+			item.Error = err.Error()
+			h.logger.Error("storage.Publication.transition", logger.String("id", id), logger.Error(err))
+		}
+		resp.Failed++
+	}
+
+	h.handlerResponse(c, "bulk review resposne", http.StatusOK, resp)
+}
+
+// duplicateFiles lists other publications, in any course, whose file has
+// the same content. Lookup failures only cost the hint.
+func (h *handler) duplicateFiles(c *gin.Context, publication *models.Publication) []*models.PublicationDuplicate {
+
+	if len(publication.FileID) <= 0 {
+		return nil
+	}
+
+	duplicates, err := h.strg.Publication().FindDuplicates(c.Request.Context(), &models.PublicationDuplicateRequest{
+		FileID:    publication.FileID,
+		ExcludeID: publication.Id,
+	})
+	if err != nil {
+		h.logger.Error("storage.Publication.findDuplicates", logger.Error(err))
+		return nil
+	}
+
+	return duplicates
+}
@@ -102,6 +102,11 @@ func (h *handler) GetByIdNotification(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param message_type query string false "message_type"
+// @Param date_from query string false "created from, 2006-01-02 or RFC3339"
+// @Param date_to query string false "created until, 2006-01-02 or RFC3339"
+// @Param sort_by query string false "created_at, updated_at, message_type"
+// @Param order query string false "asc or desc, default desc"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
@@ -119,13 +124,24 @@ func (h *handler) GetListNotification(c *gin.Context) {
 		return
 	}
 
+	err = h.validateListQuery(c)
+	if err != nil {
+		h.handlerResponse(c, "get list Notification filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
 	resp, err := h.strg.Notification().GetList(c.Request.Context(), &models.NotificationGetListRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+		Offset:      offset,
+		Limit:       limit,
+		Search:      c.Query("search"),
+		MessageType: c.Query("message_type"),
+		DateFrom:    c.Query("date_from"),
+		DateTo:      c.Query("date_to"),
+		SortBy:      c.Query("sort_by"),
+		Order:       c.Query("order"),
 	})
 	if err != nil {
-		h.handlerResponse(c, "storage.Notification.get_list", http.StatusInternalServerError, err.Error())
+		h.listErrorResponse(c, "storage.Notification.get_list", err)
 		return
 	}
 
@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/logger"
+	"app/pkg/mailer"
+)
+
+var (
+	errOtpInvalid  = errors.New("invalid or expired code")
+	errOtpAttempts = errors.New("too many attempts, request a new code")
+)
+
+// VerifyEmail godoc
+// @ID verify_email
+// @Router /register/verify [POST]
+// @Summary Verify Email
+// @Description Confirm the email address of a new account with the code sent on registration
+// @Tags Register
+// @Accept json
+// @Procedure json
+// @Param verify body models.VerifyEmailRequest true "VerifyEmailRequest"
+// @Success 200 {object} Response{data=string} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) VerifyEmail(c *gin.Context) {
+
+	var req models.VerifyEmailRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.handlerResponse(c, "verify email should bind json", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+
+	err = h.verifyOtp(c.Request.Context(), req.Email, models.OtpPurposeEmailVerification, req.Code)
+	if err != nil {
+		h.otpErrorResponse(c, "verify email", err)
+		return
+	}
+
+	_, err = h.strg.User().VerifyEmail(c.Request.Context(), req.Email)
+	if err != nil {
+		h.handlerResponse(c, "storage.User.verifyEmail", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "verify email", http.StatusOK, "email verified")
+}
+
+// ResendVerification godoc
+// @ID resend_verification
+// @Router /register/resend [POST]
+// @Summary Resend Verification Code
+// @Description Send a new email verification code, invalidating the previous one
+// @Tags Register
+// @Accept json
+// @Procedure json
+// @Param email body models.EmailRequest true "EmailRequest"
+// @Success 200 {object} Response{data=string} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) ResendVerification(c *gin.Context) {
+
+	var req models.EmailRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.handlerResponse(c, "resend verification should bind json", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+
+	user, err := h.strg.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Email: req.Email})
+	if err != nil && err.Error() != "no rows in result set" {
+		h.handlerResponse(c, "storage.User.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Answer the same way for unknown and verified emails so the endpoint
+	// can't be used to probe which addresses have an account
+	if err == nil && !user.EmailVerified {
+		// A failure is only logged, answering it would tell the account apart
+		err = h.sendOtp(c.Request.Context(), req.Email, models.OtpPurposeEmailVerification)
+		if err != nil {
+			h.logger.Error("send verification code", logger.Error(err))
+		}
+	}
+
+	h.handlerResponse(c, "resend verification", http.StatusOK, "if the account exists and is not verified, a code has been sent")
+}
+
+// ForgotPassword godoc
+// @ID forgot_password
+// @Router /password/forgot [POST]
+// @Summary Forgot Password
+// @Description Send a password reset code to the email of an account
+// @Tags Login
+// @Accept json
+// @Procedure json
+// @Param email body models.EmailRequest true "EmailRequest"
+// @Success 200 {object} Response{data=string} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) ForgotPassword(c *gin.Context) {
+
+	var req models.EmailRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.handlerResponse(c, "forgot password should bind json", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+
+	_, err = h.strg.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Email: req.Email})
+	if err != nil && err.Error() != "no rows in result set" {
+		h.handlerResponse(c, "storage.User.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err == nil {
+		// A failure is only logged, answering it would tell the account apart
+		err = h.sendOtp(c.Request.Context(), req.Email, models.OtpPurposePasswordReset)
+		if err != nil {
+			h.logger.Error("send password reset code", logger.Error(err))
+		}
+	}
+
+	h.handlerResponse(c, "forgot password", http.StatusOK, "if the account exists, a code has been sent")
+}
+
+// ResetPassword godoc
+// @ID reset_password
+// @Router /password/reset [POST]
+// @Summary Reset Password
+// @Description Set a new password using the code from the reset email. Every session of the account is logged out.
+// @Tags Login
+// @Accept json
+// @Procedure json
+// @Param reset body models.ResetPasswordRequest true "ResetPasswordRequest"
+// @Success 200 {object} Response{data=string} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) ResetPassword(c *gin.Context) {
+
+	var req models.ResetPasswordRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.handlerResponse(c, "reset password should bind json", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+
+	if len(req.NewPassword) < 7 {
+		h.handlerResponse(c, "Password should inculude more than 7 elements", http.StatusBadRequest, "Password len should inculude more than 7 elements")
+		return
+	}
+
+	err = h.verifyOtp(c.Request.Context(), req.Email, models.OtpPurposePasswordReset, req.Code)
+	if err != nil {
+		h.otpErrorResponse(c, "reset password", err)
+		return
+	}
+
+	user, err := h.strg.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Email: req.Email})
+	if err != nil {
+		h.handlerResponse(c, "storage.User.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	hashed, err := helper.HashPassword(req.NewPassword)
+	if err != nil {
+		h.handlerResponse(c, "helper.HashPassword", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_, err = h.strg.User().UpdatePassword(c.Request.Context(), user.Id, hashed)
+	if err != nil {
+		h.handlerResponse(c, "storage.User.updatePassword", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Receiving the code proves control of the mailbox, so it also verifies it
+	if !user.EmailVerified {
+		_, err = h.strg.User().VerifyEmail(c.Request.Context(), user.Email)
+		if err != nil {
+			h.handlerResponse(c, "storage.User.verifyEmail", http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	_, err = h.strg.Session().RevokeAllByUser(c.Request.Context(), user.Id)
+	if err != nil {
+		h.handlerResponse(c, "storage.Session.revokeAllByUser", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "reset password", http.StatusOK, "password has been reset")
+}
+
+// sendOtp generates a one-time code, stores its hash and mails the code.
+// Nothing is sent while the email is within the OTPCooldown of its last code
+// or has reached OTPMaxPerHour codes. That is not reported to the caller, the
+// endpoints answer the same way whether an account exists or not.
+func (h *handler) sendOtp(ctx context.Context, email, purpose string) error {
+
+	code, err := helper.GenerateOTP(h.cfg.OTPLength)
+	if err != nil {
+		return err
+	}
+
+	id, err := h.strg.Otp().Create(ctx, &models.CreateOtp{
+		Email:      email,
+		Purpose:    purpose,
+		CodeHash:   helper.HashToken(code),
+		ExpiresIn:  int64(h.cfg.OTPTTL.Seconds()),
+		Cooldown:   int64(h.cfg.OTPCooldown.Seconds()),
+		MaxPerHour: h.cfg.OTPMaxPerHour,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(id) <= 0 {
+		h.logger.Info("otp throttled", logger.String("purpose", purpose))
+		return nil
+	}
+
+	var subject string
+	switch purpose {
+	case models.OtpPurposeEmailVerification:
+		subject = "Verify your email"
+	case models.OtpPurposePasswordReset:
+		subject = "Reset your password"
+	}
+
+	err = h.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: subject,
+		Body:    fmt.Sprintf("Your code is %s\nIt expires in %s.\n", code, h.cfg.OTPTTL),
+	})
+	if err != nil {
+		h.logger.Error("mailer.Send", logger.String("purpose", purpose), logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// verifyOtp checks the code against the latest active code and consumes it.
+// Every check counts as an attempt, the code is locked after OTPMaxAttempts.
+func (h *handler) verifyOtp(ctx context.Context, email, purpose, code string) error {
+
+	otp, err := h.strg.Otp().GetActive(ctx, &models.OtpPrimaryKey{Email: email, Purpose: purpose})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return errOtpInvalid
+		}
+		return err
+	}
+
+	rowsAffected, err := h.strg.Otp().RegisterAttempt(ctx, &models.OtpPrimaryKey{Id: otp.Id}, h.cfg.OTPMaxAttempts)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected <= 0 {
+		return errOtpAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(helper.HashToken(code)), []byte(otp.CodeHash)) != 1 {
+		return errOtpInvalid
+	}
+
+	rowsAffected, err = h.strg.Otp().Consume(ctx, &models.OtpPrimaryKey{Id: otp.Id})
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected <= 0 {
+		return errOtpInvalid
+	}
+
+	return nil
+}
+
+func (h *handler) otpErrorResponse(c *gin.Context, path string, err error) {
+	switch {
+	case errors.Is(err, errOtpInvalid):
+		h.handlerResponse(c, path, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errOtpAttempts):
+		h.handlerResponse(c, path, http.StatusTooManyRequests, err.Error())
+	default:
+		h.handlerResponse(c, path, http.StatusInternalServerError, err.Error())
+	}
+}
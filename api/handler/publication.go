@@ -1,13 +1,19 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"app/api/models"
 	"app/pkg/helper"
+	"app/pkg/logger"
+	"app/pkg/moderation"
+	"app/pkg/tags"
 )
 
 // @Security ApiKeyAuth
@@ -15,13 +21,14 @@ import (
 // @ID create_publication
 // @Router /publication [POST]
 // @Summary Create Publication
-// @Description Create Publication
+// @Description Create Publication. It is submitted for review unless status is draft; admins may create it published. The response lists warnings when the file already backs another publication of the course.
 // @Tags Publication
 // @Accept json
 // @Procedure json
 // @Param Publication body models.CreatePublication true "CreatePublicationRequest"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "File belongs to another user"
 // @Failure 500 {object} Response{data=string} "Server error"
 func (h *handler) CreatePublication(c *gin.Context) {
 
@@ -32,8 +39,50 @@ func (h *handler) CreatePublication(c *gin.Context) {
 		return
 	}
 
+	// contributor_id is only read from admins
+	if !isAdmin(c) {
+		info, _ := getAuthInfo(c)
+		createPublication.ContributorID = info.UserID
+	}
+
+	if !helper.IsValidUUID(createPublication.ContributorID) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid contributor_id")
+		return
+	}
+
+	if len(createPublication.FileID) <= 0 {
+		h.handlerResponse(c, "Publication file", http.StatusBadRequest, "file_id is required")
+		return
+	}
+
+	if err := tags.Check(tags.Split(createPublication.Tags)); err != nil {
+		h.handlerResponse(c, "Publication tags", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// New publications wait for review unless kept as a draft; only admins
+	// publish directly
+	if len(createPublication.Status) <= 0 {
+		createPublication.Status = string(moderation.PendingReview)
+	}
+	if createPublication.Status == string(moderation.Published) && !isAdmin(c) {
+		h.handlerResponse(c, "Publication status", http.StatusForbidden, "only admins can publish directly")
+		return
+	}
+
+	info, _ := getAuthInfo(c)
+	createPublication.ActorID = info.UserID
+
+	if !h.checkPublicationFiles(c, createPublication.ImageID, createPublication.FileID, info.UserID, createPublication.ContributorID) {
+		return
+	}
+
 	id, err := h.strg.Publication().Create(c.Request.Context(), &createPublication)
 	if err != nil {
+		if errors.Is(err, moderation.ErrInvalidStatus) {
+			h.handlerResponse(c, "storage.Publication.create", http.StatusBadRequest, "status must be draft or pending_review")
+			return
+		}
 		h.handlerResponse(c, "storage.Publication.create", http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -44,6 +93,8 @@ func (h *handler) CreatePublication(c *gin.Context) {
 		return
 	}
 
+	resp.Warnings = h.duplicateFileWarnings(c, resp)
+
 	h.handlerResponse(c, "create Publication resposne", http.StatusCreated, resp)
 }
 
@@ -52,7 +103,7 @@ func (h *handler) CreatePublication(c *gin.Context) {
 // @ID get_by_id_publication
 // @Router /publication/{id} [GET]
 // @Summary Get By ID Publication
-// @Description Get By ID Publication
+// @Description Get By ID Publication. Unpublished publications are only visible to their contributor and admins.
 // @Tags Publication
 // @Accept json
 // @Procedure json
@@ -82,12 +133,25 @@ func (h *handler) GetByIdPublication(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+	info, _ := getAuthInfo(c)
+
+	resp, err := h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: id, ViewerID: info.UserID})
 	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.Publication.getById", http.StatusNotFound, "publication not found")
+			return
+		}
 		h.handlerResponse(c, "storage.Publication.getById", http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if !canViewPublication(c, resp) {
+		h.handlerResponse(c, "storage.Publication.getById", http.StatusNotFound, "publication not found")
+		return
+	}
+
+	setImageThumbnails(resp)
+
 	h.handlerResponse(c, "get by id Publication resposne", http.StatusOK, resp)
 }
 
@@ -96,13 +160,23 @@ func (h *handler) GetByIdPublication(c *gin.Context) {
 // @ID get_list_publication
 // @Router /publication [GET]
 // @Summary Get List Publication
-// @Description Get List Publication
+// @Description Get List Publication. Only published publications are listed, except for admins and for contributors listing their own (contributor_id).
 // @Tags Publication
 // @Accept json
 // @Procedure json
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param course_id query string false "course_id"
+// @Param semester_id query string false "semester_id"
+// @Param contributor_id query string false "contributor_id"
+// @Param status query string false "draft, pending_review, published, rejected or archived"
+// @Param date_from query string false "created from, 2006-01-02 or RFC3339"
+// @Param date_to query string false "created until, 2006-01-02 or RFC3339"
+// @Param tags query string false "tags, repeated or separated with commas"
+// @Param tag_match query string false "all (default) or any of the tags"
+// @Param sort_by query string false "created_at, updated_at, title, status, like_count"
+// @Param order query string false "asc or desc, default desc"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
@@ -120,16 +194,53 @@ func (h *handler) GetListPublication(c *gin.Context) {
 		return
 	}
 
+	err = h.validateListQuery(c, "course_id", "semester_id", "contributor_id")
+	if err != nil {
+		h.handlerResponse(c, "get list Publication filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status := c.Query("status")
+	if len(status) > 0 && !moderation.Valid(moderation.Status(status)) {
+		h.handlerResponse(c, "get list Publication status", http.StatusBadRequest, moderation.ErrInvalidStatus.Error())
+		return
+	}
+
+	names, match, err := tagFilter(c, "tags", "tag_match")
+	if err != nil {
+		h.handlerResponse(c, "get list Publication tags", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Others only see published work, contributors their own in any status
+	info, ok := getAuthInfo(c)
+	if !isAdmin(c) && (!ok || c.Query("contributor_id") != info.UserID) {
+		status = string(moderation.Published)
+	}
+
 	resp, err := h.strg.Publication().GetList(c.Request.Context(), &models.PublicationGetListRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+		Offset:        offset,
+		Limit:         limit,
+		Search:        c.Query("search"),
+		CourseID:      c.Query("course_id"),
+		SemesterID:    c.Query("semester_id"),
+		ContributorID: c.Query("contributor_id"),
+		Status:        status,
+		DateFrom:      c.Query("date_from"),
+		DateTo:        c.Query("date_to"),
+		SortBy:        c.Query("sort_by"),
+		Order:         c.Query("order"),
+		ViewerID:      info.UserID,
+		Tags:          names,
+		TagMatch:      string(match),
 	})
 	if err != nil {
-		h.handlerResponse(c, "storage.Publication.get_list", http.StatusInternalServerError, err.Error())
+		h.listErrorResponse(c, "storage.Publication.get_list", err)
 		return
 	}
 
+	setImageThumbnails(resp.Publications...)
+
 	h.handlerResponse(c, "get list Publication resposne", http.StatusOK, resp)
 }
 
@@ -146,6 +257,8 @@ func (h *handler) GetListPublication(c *gin.Context) {
 // @Param Publication body models.UpdatePublication true "UpdatePublicationRequest"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Response 404 {object} Response{data=string} "Not Found"
 // @Failure 500 {object} Response{data=string} "Server error"
 func (h *handler) UpdatePublication(c *gin.Context) {
 
@@ -159,12 +272,54 @@ func (h *handler) UpdatePublication(c *gin.Context) {
 		return
 	}
 
-	err := c.ShouldBindJSON(&updatePublication)
+	current, err := h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.Publication.getById", http.StatusNotFound, "publication not found")
+			return
+		}
+		h.handlerResponse(c, "storage.Publication.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = c.ShouldBindJSON(&updatePublication)
 	if err != nil {
 		h.handlerResponse(c, "error Publication should bind json", http.StatusBadRequest, err.Error())
 		return
 	}
 	updatePublication.Id = id
+	updatePublication.OwnerID = ownerScope(c)
+
+	if err := tags.Check(tags.Split(updatePublication.Tags)); err != nil {
+		h.handlerResponse(c, "Publication tags", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The contributor stays unless an admin names another one
+	if !isAdmin(c) || len(updatePublication.ContributorID) <= 0 {
+		updatePublication.ContributorID = current.ContributorID
+	}
+
+	// Files are kept unless replaced, new ones are checked like on create
+	var newImageID, newFileID string
+	if len(updatePublication.ImageID) <= 0 {
+		updatePublication.ImageID = current.ImageID
+	} else if updatePublication.ImageID != current.ImageID {
+		newImageID = updatePublication.ImageID
+	}
+	if len(updatePublication.FileID) <= 0 {
+		updatePublication.FileID = current.FileID
+	} else if updatePublication.FileID != current.FileID {
+		newFileID = updatePublication.FileID
+	}
+
+	info, _ := getAuthInfo(c)
+	if !h.checkPublicationFiles(c, newImageID, newFileID, info.UserID, updatePublication.ContributorID) {
+		return
+	}
+	updatePublication.EditorID = info.UserID
+	updatePublication.Review = !isAdmin(c)
+
 	rowsAffected, err := h.strg.Publication().Update(c.Request.Context(), &updatePublication)
 	if err != nil {
 		h.handlerResponse(c, "storage.Publication.update", http.StatusInternalServerError, err.Error())
@@ -172,7 +327,8 @@ func (h *handler) UpdatePublication(c *gin.Context) {
 	}
 
 	if rowsAffected <= 0 {
-		h.handlerResponse(c, "storage.Publication.update", http.StatusBadRequest, "now rows affected")
+		_, err = h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+		h.notOwnedResponse(c, "storage.Publication.update", "publication", err)
 		return
 	}
 
@@ -182,6 +338,10 @@ func (h *handler) UpdatePublication(c *gin.Context) {
 		return
 	}
 
+	if len(newFileID) > 0 {
+		resp.Warnings = h.duplicateFileWarnings(c, resp)
+	}
+
 	h.handlerResponse(c, "create Publication resposne", http.StatusAccepted, resp)
 }
 
@@ -197,6 +357,8 @@ func (h *handler) UpdatePublication(c *gin.Context) {
 // @Param id path string true "id"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Response 404 {object} Response{data=string} "Not Found"
 // @Failure 500 {object} Response{data=string} "Server error"
 func (h *handler) DeletePublication(c *gin.Context) {
 
@@ -207,15 +369,81 @@ func (h *handler) DeletePublication(c *gin.Context) {
 		return
 	}
 
-	err := h.strg.Publication().Delete(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+	rowsAffected, err := h.strg.Publication().Delete(c.Request.Context(), &models.PublicationPrimaryKey{Id: id, OwnerID: ownerScope(c)})
 	if err != nil {
 		h.handlerResponse(c, "storage.Publication.delete", http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if rowsAffected <= 0 {
+		_, err = h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+		h.notOwnedResponse(c, "storage.Publication.delete", "publication", err)
+		return
+	}
+
 	h.handlerResponse(c, "create Publication resposne", http.StatusNoContent, nil)
 }
 
+// @Security ApiKeyAuth
+// GetPublicationDownloadLink godoc
+// @ID get_publication_download_link
+// @Router /publication/{id}/download-link [GET]
+// @Summary Get Publication Download Link
+// @Description Mint a signed link to the publication file that expires after DOWNLOAD_LINK_TTL. With bind_user=true only the caller can use it.
+// @Tags Publication
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Param bind_user query bool false "bind the link to the caller"
+// @Success 200 {object} Response{data=models.DownloadLink} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 401 {object} Response{data=string} "Unauthorized"
+// @Failure 404 {object} Response{data=string} "Not Found"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) GetPublicationDownloadLink(c *gin.Context) {
+	var id string = c.Param("id")
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	info, ok := getAuthInfo(c)
+
+	var userID string
+	if c.Query("bind_user") == "true" {
+		if !ok {
+			h.handlerResponse(c, "download link", http.StatusUnauthorized, "bind_user requires a token")
+			return
+		}
+		userID = info.UserID
+	}
+
+	publication, err := h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.Publication.getById", http.StatusNotFound, "publication not found")
+			return
+		}
+		h.handlerResponse(c, "storage.Publication.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !canViewPublication(c, publication) || len(publication.FileID) <= 0 {
+		h.handlerResponse(c, "download link", http.StatusNotFound, "publication has no file")
+		return
+	}
+
+	expires := time.Now().Add(h.cfg.DownloadLinkTTL)
+	query := helper.SignDownloadLink(h.cfg.DownloadLinkSecret, publication.FileID, userID, expires)
+
+	h.handlerResponse(c, "download link resposne", http.StatusOK, &models.DownloadLink{
+		FileID:    publication.FileID,
+		URL:       "/file_download/" + publication.FileID + "?" + query.Encode(),
+		ExpiresAt: expires.UTC().Format(time.RFC3339),
+	})
+}
+
 // GetPublicationStats godoc
 // @ID get_publication_stats
 // @Router /get_publication_stats [GET]
@@ -253,26 +481,143 @@ func (h *handler) GetPublicationStats(c *gin.Context) {
 // GetPublicationsByTag godoc
 // @Router /publications/tags [GET]
 // @Summary Get Publications by Tag
-// @Description Get a list of publications that have the specified tag
+// @Description Get the published publications that have the specified tags, all of them unless match=any. Tags match exactly, case and extra spaces aside.
 // @Tags Publication
 // @Accept json
 // @Produce json
-// @Param tag query string true "Tag to search for"
+// @Param tag query string true "Tag to search for, repeat it or separate tags with commas"
+// @Param match query string false "all (default) or any"
 // @Success 200 {object} Response{data=[]models.Publication} "Success Request"
 // @Failure 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
 func (h *handler) GetPublicationsByTag(c *gin.Context) {
-	tag := c.DefaultQuery("tag", "")
-	if tag == "" {
+	names, match, err := tagFilter(c, "tag", "match")
+	if err != nil {
+		h.handlerResponse(c, "get publications by tag", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(names) <= 0 {
 		h.handlerResponse(c, "missing tag", http.StatusBadRequest, "Tag is required")
 		return
 	}
 
-	publications, err := h.strg.Publication().GetPublicationsByTag(c.Request.Context(), tag)
+	publications, err := h.strg.Publication().GetPublicationsByTag(c.Request.Context(), &models.PublicationTagRequest{
+		Tags:  names,
+		Match: string(match),
+	})
 	if err != nil {
 		h.handlerResponse(c, "failed to get publications by tag", http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	setImageThumbnails(publications...)
+
 	h.handlerResponse(c, "publications retrieved successfully", http.StatusOK, publications)
 }
+
+// canViewPublication reports whether the caller may see the publication:
+// anyone once it is published, its contributor and admins before.
+func canViewPublication(c *gin.Context, publication *models.Publication) bool {
+	if publication.Status == string(moderation.Published) {
+		return true
+	}
+
+	info, ok := getAuthInfo(c)
+	return isAdmin(c) || (ok && len(info.UserID) > 0 && info.UserID == publication.ContributorID)
+}
+
+// canReadHistory checks that the caller contributed the publication or is
+// an admin, who alone may read its moderation history and revisions. On
+// failure the response is already written.
+func (h *handler) canReadHistory(c *gin.Context, id string) bool {
+
+	publication, err := h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+	if err != nil || !isSelfOrAdmin(c, publication.ContributorID) {
+		h.notOwnedResponse(c, "storage.Publication.getById", "publication", err)
+		return false
+	}
+
+	return true
+}
+
+// checkPublicationFiles checks that the cover and the file of a publication
+// are uploaded files owned by one of owners, and that the cover is an
+// image. Empty ids are skipped. On failure the response is already written.
+func (h *handler) checkPublicationFiles(c *gin.Context, imageID, fileID string, owners ...string) bool {
+
+	refs := []struct {
+		field string
+		id    string
+	}{
+		{"image_id", imageID},
+		{"file_id", fileID},
+	}
+
+	for _, ref := range refs {
+		if len(ref.id) <= 0 {
+			continue
+		}
+
+		if !helper.IsValidUUID(ref.id) {
+			h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid "+ref.field)
+			return false
+		}
+
+		file, err := h.strg.File().GetByID(c.Request.Context(), &models.FilePrimaryKey{Id: ref.id})
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				h.handlerResponse(c, "storage.File.getById", http.StatusBadRequest, ref.field+" does not reference an uploaded file")
+				return false
+			}
+			h.handlerResponse(c, "storage.File.getById", http.StatusInternalServerError, err.Error())
+			return false
+		}
+
+		owned := false
+		for _, owner := range owners {
+			if len(owner) > 0 && file.OwnerID == owner {
+				owned = true
+				break
+			}
+		}
+
+		if !owned {
+			h.handlerResponse(c, "Publication file owner check", http.StatusForbidden, ref.field+" belongs to another user")
+			return false
+		}
+
+		if ref.field == "image_id" && !strings.HasPrefix(file.MimeType, "image/") {
+			h.handlerResponse(c, "Publication image type", http.StatusBadRequest, "image_id must reference an image")
+			return false
+		}
+	}
+
+	return true
+}
+
+// duplicateFileWarnings describes other publications of the same course
+// whose file has the same content. Lookup failures only cost the warning.
+func (h *handler) duplicateFileWarnings(c *gin.Context, publication *models.Publication) []string {
+
+	if len(publication.FileID) <= 0 {
+		return nil
+	}
+
+	duplicates, err := h.strg.Publication().FindDuplicates(c.Request.Context(), &models.PublicationDuplicateRequest{
+		FileID:    publication.FileID,
+		CourseID:  publication.CourseId,
+		ExcludeID: publication.Id,
+	})
+	if err != nil {
+		h.logger.Error("storage.Publication.findDuplicates", logger.Error(err))
+		return nil
+	}
+
+	var warnings []string
+	for _, duplicate := range duplicates {
+		warnings = append(warnings, fmt.Sprintf("the same file already backs publication %q (%s) in this course", duplicate.Title, duplicate.Id))
+	}
+
+	return warnings
+}
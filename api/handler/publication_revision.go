@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"app/api/models"
+	"app/pkg/helper"
+)
+
+// @Security ApiKeyAuth
+// GetPublicationRevisions godoc
+// @ID get_publication_revisions
+// @Router /publication/{id}/revisions [GET]
+// @Summary Get Publication Revisions
+// @Description Every version of a publication, newest first, with the changed fields, the replaced file and the editor
+// @Tags Publication
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Success 200 {object} Response{data=[]models.PublicationRevision} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) GetPublicationRevisions(c *gin.Context) {
+
+	var id string = c.Param("id")
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if !h.canReadHistory(c, id) {
+		return
+	}
+
+	resp, err := h.strg.Publication().GetRevisions(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.Publication.getRevisions", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get Publication revisions resposne", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// GetPublicationRevisionDiff godoc
+// @ID get_publication_revision_diff
+// @Router /publication/{id}/revisions/diff [GET]
+// @Summary Get Publication Revision Diff
+// @Description Fields that differ between two revisions. to defaults to the latest revision, from to the one before to.
+// @Tags Publication
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Param from query int false "older revision"
+// @Param to query int false "newer revision"
+// @Success 200 {object} Response{data=models.PublicationRevisionDiff} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 403 {object} Response{data=string} "Not the contributor"
+// @Response 404 {object} Response{data=string} "Revision not found"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) GetPublicationRevisionDiff(c *gin.Context) {
+
+	var id string = c.Param("id")
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	from, err := revisionQuery(c, "from")
+	if err != nil {
+		h.handlerResponse(c, "get Publication revision diff from", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	to, err := revisionQuery(c, "to")
+	if err != nil {
+		h.handlerResponse(c, "get Publication revision diff to", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.canReadHistory(c, id) {
+		return
+	}
+
+	resp, err := h.strg.Publication().GetRevisionDiff(c.Request.Context(), &models.PublicationRevisionDiffRequest{
+		PublicationID: id,
+		From:          from,
+		To:            to,
+	})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.Publication.getRevisionDiff", http.StatusNotFound, "revision not found")
+			return
+		}
+		h.handlerResponse(c, "storage.Publication.getRevisionDiff", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get Publication revision diff resposne", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// RollbackPublication godoc
+// @ID rollback_publication
+// @Router /admin/publication/{id}/rollback [POST]
+// @Summary Rollback Publication
+// @Description Restore the content of an earlier revision. The restored content is saved as a new revision; the status doesn't change.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Param Rollback body models.PublicationRollback true "revision to restore"
+// @Success 200 {object} Response{data=models.Publication} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 404 {object} Response{data=string} "Publication or revision not found"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) RollbackPublication(c *gin.Context) {
+
+	var (
+		id       string = c.Param("id")
+		rollback models.PublicationRollback
+	)
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	err := c.ShouldBindJSON(&rollback)
+	if err != nil {
+		h.handlerResponse(c, "error Rollback should bind json", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if rollback.Revision <= 0 {
+		h.handlerResponse(c, "rollback Publication revision", http.StatusBadRequest, "invalid revision")
+		return
+	}
+
+	info, _ := getAuthInfo(c)
+	rollback.Id = id
+	rollback.EditorID = info.UserID
+
+	_, err = h.strg.Publication().Rollback(c.Request.Context(), &rollback)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.Publication.rollback", http.StatusNotFound, "publication or revision not found")
+			return
+		}
+		h.handlerResponse(c, "storage.Publication.rollback", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp, err := h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{Id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.Publication.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	setImageThumbnails(resp)
+
+	h.handlerResponse(c, "rollback Publication resposne", http.StatusOK, resp)
+}
+
+// revisionQuery reads an optional revision number, 0 when absent.
+func revisionQuery(c *gin.Context, name string) (int, error) {
+
+	value := c.Query(name)
+	if len(value) <= 0 {
+		return 0, nil
+	}
+
+	revision, err := strconv.Atoi(value)
+	if err != nil || revision <= 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"app/api/models"
+	"app/config"
+	"app/pkg/helper"
+	"app/pkg/logger"
+	"app/pkg/moderation"
+	"app/storage"
+)
+
+// fakeStorage serves the publication repo only, the other repos panic if a
+// test reaches them.
+type fakeStorage struct {
+	storage.StorageI
+	publications *fakePublicationRepo
+}
+
+func (s *fakeStorage) Publication() storage.PublicationRepoI {
+	return s.publications
+}
+
+// fakePublicationRepo keeps one publication in memory and applies updates
+// the way the postgres repo does for status.
+type fakePublicationRepo struct {
+	storage.PublicationRepoI
+	pub     models.Publication
+	updated *models.UpdatePublication
+}
+
+func (r *fakePublicationRepo) GetByID(ctx context.Context, req *models.PublicationPrimaryKey) (*models.Publication, error) {
+	pub := r.pub
+	return &pub, nil
+}
+
+func (r *fakePublicationRepo) Update(ctx context.Context, req *models.UpdatePublication) (int64, error) {
+	r.updated = req
+
+	if len(req.OwnerID) > 0 && req.OwnerID != r.pub.ContributorID {
+		return 0, nil
+	}
+
+	changed := req.Title != r.pub.Title || req.Description != r.pub.Description
+	r.pub.Title = req.Title
+	r.pub.Description = req.Description
+	if req.Review && changed {
+		r.pub.Status = string(moderation.Edited(moderation.Status(r.pub.Status)))
+	}
+
+	return 1, nil
+}
+
+func TestUpdatePublicationReview(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	const (
+		publicationID = "8a1c6c1e-3b7a-4d0e-9a57-2f4b5f1d0c11"
+		contributorID = "1f0e2d3c-4b5a-4968-8776-655443322110"
+		adminID       = "0a9b8c7d-6e5f-4a3b-9c2d-1e0f9a8b7c6d"
+	)
+
+	tests := []struct {
+		name   string
+		caller helper.TokenInfo
+		status moderation.Status
+		title  string
+		review bool
+		want   moderation.Status
+	}{
+		{"contributor edits a published one", helper.TokenInfo{UserID: contributorID, Role: config.RoleUser}, moderation.Published, "Edited", true, moderation.PendingReview},
+		{"contributor edits a rejected one", helper.TokenInfo{UserID: contributorID, Role: config.RoleUser}, moderation.Rejected, "Edited", true, moderation.PendingReview},
+		{"contributor edits a draft", helper.TokenInfo{UserID: contributorID, Role: config.RoleUser}, moderation.Draft, "Edited", true, moderation.Draft},
+		{"contributor saves without changes", helper.TokenInfo{UserID: contributorID, Role: config.RoleUser}, moderation.Published, "Notes", true, moderation.Published},
+		{"admin edits a published one", helper.TokenInfo{UserID: adminID, Role: config.RoleAdmin}, moderation.Published, "Edited", false, moderation.Published},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePublicationRepo{pub: models.Publication{
+				Id:            publicationID,
+				Title:         "Notes",
+				ContributorID: contributorID,
+				Status:        string(tt.status),
+			}}
+			h := NewHandler(&config.Config{}, &fakeStorage{publications: repo}, logger.NewLogger("test", logger.LevelError), nil, nil)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/publication/"+publicationID, strings.NewReader(`{"title":"`+tt.title+`"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: publicationID}}
+			c.Set(authInfoKey, tt.caller)
+
+			h.UpdatePublication(c)
+
+			if w.Code != http.StatusAccepted {
+				t.Fatalf("status code = %d, want %d: %s", w.Code, http.StatusAccepted, w.Body.String())
+			}
+			if repo.updated.Review != tt.review {
+				t.Errorf("Review = %v, want %v", repo.updated.Review, tt.review)
+			}
+
+			var resp struct {
+				Data models.Publication `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Data.Status != string(tt.want) {
+				t.Errorf("publication status = %s, want %s", resp.Data.Status, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,634 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"app/api/models"
+	"app/config"
+	"app/jobs"
+	"app/pkg/blobstore"
+	"app/pkg/helper"
+	"app/pkg/logger"
+	"app/pkg/upload"
+)
+
+// The resumable upload endpoints follow the tus 1.0 core protocol with the
+// creation, checksum, expiration and termination extensions, so existing
+// tus clients work against them.
+const (
+	tusVersion         = "1.0.0"
+	tusOffsetMediaType = "application/offset+octet-stream"
+
+	// statusChecksumMismatch is the tus status for a chunk whose
+	// Upload-Checksum doesn't match its content.
+	statusChecksumMismatch = 460
+)
+
+// CreateResumableUpload starts a resumable upload.
+// @Security ApiKeyAuth
+// @Summary Start a resumable upload
+// @Description Creates a tus upload of Upload-Length bytes. Upload-Metadata may carry base64 encoded "filename" and "kind" (publication_file by default, or publication_video). The upload URL is returned in the Location header.
+// @Tags File
+// @Produce json
+// @Param Upload-Length header int true "Total size in bytes"
+// @Param Upload-Metadata header string false "tus metadata, e.g. filename bGVjdHVyZS5tcDQ=,kind cHVibGljYXRpb25fdmlkZW8="
+// @Success 201 {object} Response{data=models.UploadSession} "Upload created"
+// @Failure 400 {object} Response "Bad Request"
+// @Failure 413 {object} Response "File too large or quota exceeded"
+// @Failure 500 {object} Response "Server Error"
+// @Router /uploads [post]
+func (h *handler) CreateResumableUpload(c *gin.Context) {
+
+	c.Header("Tus-Resumable", tusVersion)
+
+	size, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || size <= 0 {
+		h.handlerResponse(c, "resumable upload length", http.StatusBadRequest, "Upload-Length must be a positive integer")
+		return
+	}
+
+	metadata, err := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		h.handlerResponse(c, "resumable upload metadata", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	kind := upload.Kind(metadata["kind"])
+	if len(kind) <= 0 {
+		kind = upload.KindPublicationFile
+	}
+
+	policy, err := upload.PolicyFor(kind, h.uploadLimits())
+	if err != nil {
+		h.handlerResponse(c, "resumable upload policy", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Images are re-encoded on upload, which needs the whole file at once
+	if upload.Processed(kind) {
+		h.handlerResponse(c, "resumable upload kind", http.StatusBadRequest, "images are uploaded with POST /uploadd or /upload_profile")
+		return
+	}
+
+	if size > policy.MaxSize {
+		h.handlerResponse(c, "resumable upload size", http.StatusRequestEntityTooLarge, fmt.Sprintf("%s, the limit is %d bytes", upload.ErrTooLarge, policy.MaxSize))
+		return
+	}
+
+	filename := metadata["filename"]
+	if len(filename) <= 0 {
+		filename = "upload"
+	}
+
+	// The whole size is reserved up front, so a user can't run past the
+	// quota with many half finished uploads
+	info, _ := getAuthInfo(c)
+	var reserved int64
+	if info.Role == config.RoleUser {
+		ok, err := h.strg.User().ReserveStorage(c.Request.Context(), info.UserID, size, h.cfg.UserStorageQuota)
+		if err != nil {
+			h.handlerResponse(c, "storage.User.reserveStorage", http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if !ok {
+			h.handlerResponse(c, "resumable upload quota", http.StatusRequestEntityTooLarge, fmt.Sprintf("%s, the quota is %d bytes", upload.ErrQuotaExceeded, h.cfg.UserStorageQuota))
+			return
+		}
+		reserved = size
+	}
+
+	id, err := h.strg.UploadSession().Create(c.Request.Context(), &models.CreateUploadSession{
+		OwnerID:       info.UserID,
+		Kind:          string(kind),
+		Filename:      filename,
+		Size:          size,
+		QuotaReserved: reserved,
+		ExpiresIn:     int64(h.cfg.UploadSessionTTL.Seconds()),
+	})
+	if err != nil {
+		h.releaseUpload(c, info, reserved)
+		h.handlerResponse(c, "storage.UploadSession.create", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	session, err := h.strg.UploadSession().GetByID(c.Request.Context(), &models.UploadSessionPrimaryKey{Id: id})
+	if err != nil {
+		h.handlerResponse(c, "storage.UploadSession.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Location", "/uploads/"+id)
+	h.setUploadHeaders(c, session)
+	h.handlerResponse(c, "create resumable upload", http.StatusCreated, session)
+}
+
+// HeadResumableUpload reports how much of an upload was received.
+// @Security ApiKeyAuth
+// @Summary Resumable upload offset
+// @Description Returns the received byte count in Upload-Offset, the place to resume from.
+// @Tags File
+// @Param id path string true "upload id"
+// @Success 200 "Upload-Offset and Upload-Length headers"
+// @Failure 404 "Upload not found"
+// @Failure 410 "Upload expired"
+// @Router /uploads/{id} [head]
+func (h *handler) HeadResumableUpload(c *gin.Context) {
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Cache-Control", "no-store")
+
+	session, status := h.getUploadSession(c)
+	if status != http.StatusOK {
+		c.Status(status)
+		return
+	}
+
+	h.setUploadHeaders(c, session)
+	c.Status(http.StatusOK)
+}
+
+// GetResumableUpload returns an upload session, including the file id once
+// the upload completed.
+// @Security ApiKeyAuth
+// @Summary Get a resumable upload
+// @Description Get the state of a resumable upload
+// @Tags File
+// @Produce json
+// @Param id path string true "upload id"
+// @Success 200 {object} Response{data=models.UploadSession} "Upload"
+// @Failure 404 {object} Response "Upload not found"
+// @Failure 410 {object} Response "Upload expired"
+// @Router /uploads/{id} [get]
+func (h *handler) GetResumableUpload(c *gin.Context) {
+
+	session, status := h.getUploadSession(c)
+	if status != http.StatusOK {
+		h.handlerResponse(c, "get resumable upload", status, http.StatusText(status))
+		return
+	}
+
+	h.handlerResponse(c, "get resumable upload", http.StatusOK, session)
+}
+
+// PatchResumableUpload appends a chunk at Upload-Offset. The chunk that
+// completes the upload registers the file, its id is sent in Upload-File-Id.
+// @Security ApiKeyAuth
+// @Summary Upload a chunk
+// @Description Appends the body at Upload-Offset. An optional Upload-Checksum "sha256 <base64 digest>" is verified against the chunk.
+// @Tags File
+// @Accept application/offset+octet-stream
+// @Param id path string true "upload id"
+// @Param Upload-Offset header int true "offset the chunk starts at"
+// @Param Upload-Checksum header string false "sha256 <base64 digest>"
+// @Success 204 "Chunk stored, new Upload-Offset in the header"
+// @Failure 400 {object} Response "Bad Request"
+// @Failure 404 {object} Response "Upload not found"
+// @Failure 409 {object} Response "Upload-Offset doesn't match"
+// @Failure 410 {object} Response "Upload expired"
+// @Failure 413 {object} Response "Chunk too large"
+// @Failure 415 {object} Response "Wrong Content-Type or file type not allowed"
+// @Failure 460 {object} Response "Checksum mismatch"
+// @Router /uploads/{id} [patch]
+func (h *handler) PatchResumableUpload(c *gin.Context) {
+
+	c.Header("Tus-Resumable", tusVersion)
+
+	if c.ContentType() != tusOffsetMediaType {
+		h.handlerResponse(c, "resumable upload content type", http.StatusUnsupportedMediaType, "Content-Type must be "+tusOffsetMediaType)
+		return
+	}
+
+	session, status := h.getUploadSession(c)
+	if status != http.StatusOK {
+		h.handlerResponse(c, "resumable upload", status, http.StatusText(status))
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		h.handlerResponse(c, "resumable upload offset", http.StatusBadRequest, "Upload-Offset must be a non negative integer")
+		return
+	}
+
+	if offset != session.Offset {
+		h.setUploadHeaders(c, session)
+		h.handlerResponse(c, "resumable upload offset", http.StatusConflict, fmt.Sprintf("Upload-Offset is %d, expected %d", offset, session.Offset))
+		return
+	}
+
+	length := c.Request.ContentLength
+	if length < 0 {
+		h.handlerResponse(c, "resumable upload length", http.StatusLengthRequired, "Content-Length is required")
+		return
+	}
+
+	if length > h.cfg.UploadChunkMaxSize {
+		h.handlerResponse(c, "resumable upload chunk", http.StatusRequestEntityTooLarge, fmt.Sprintf("chunks are limited to %d bytes", h.cfg.UploadChunkMaxSize))
+		return
+	}
+
+	if offset+length > session.Size {
+		h.handlerResponse(c, "resumable upload chunk", http.StatusRequestEntityTooLarge, "chunk goes past Upload-Length")
+		return
+	}
+
+	// An empty chunk at the end retries the completion of an upload whose
+	// last chunk was stored but not registered
+	if length > 0 {
+		expected, err := parseUploadChecksum(c.GetHeader("Upload-Checksum"))
+		if err != nil {
+			h.handlerResponse(c, "resumable upload checksum", http.StatusBadRequest, err.Error())
+			return
+		}
+
+		advanced, ok := h.storeChunk(c, session, length, expected)
+		if !ok {
+			return
+		}
+		session = advanced
+	}
+
+	if session.Offset == session.Size && len(session.CompletedAt) <= 0 {
+		if !h.completeUpload(c, session) {
+			return
+		}
+	}
+
+	h.setUploadHeaders(c, session)
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteResumableUpload cancels an upload and drops what was received.
+// @Security ApiKeyAuth
+// @Summary Cancel a resumable upload
+// @Description Deletes the received chunks and gives the reserved quota back
+// @Tags File
+// @Param id path string true "upload id"
+// @Success 204 "Upload cancelled"
+// @Failure 404 {object} Response "Upload not found"
+// @Router /uploads/{id} [delete]
+func (h *handler) DeleteResumableUpload(c *gin.Context) {
+
+	c.Header("Tus-Resumable", tusVersion)
+
+	session, status := h.getUploadSession(c)
+	if status != http.StatusOK && status != http.StatusGone {
+		h.handlerResponse(c, "resumable upload", status, http.StatusText(status))
+		return
+	}
+
+	err := jobs.DiscardUploadSession(c.Request.Context(), h.strg, h.blobs, session)
+	if err != nil {
+		h.handlerResponse(c, "discard upload session", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getUploadSession loads the session of the :id parameter for its owner.
+// The session is returned with 410 when it expired, so it can still be
+// discarded.
+func (h *handler) getUploadSession(c *gin.Context) (*models.UploadSession, int) {
+
+	id := c.Param("id")
+	if !helper.IsValidUUID(id) {
+		return nil, http.StatusNotFound
+	}
+
+	session, err := h.strg.UploadSession().GetByID(c.Request.Context(), &models.UploadSessionPrimaryKey{Id: id})
+	if err != nil {
+		if err.Error() != "no rows in result set" {
+			h.logger.Error("storage.UploadSession.getById", logger.Error(err))
+			return nil, http.StatusInternalServerError
+		}
+		return nil, http.StatusNotFound
+	}
+
+	// Someone else's upload is reported as missing rather than forbidden
+	if !isSelfOrAdmin(c, session.OwnerID) {
+		return nil, http.StatusNotFound
+	}
+
+	if session.ExpiresIn <= 0 {
+		return session, http.StatusGone
+	}
+
+	return session, http.StatusOK
+}
+
+// setUploadHeaders sends the tus headers describing the session.
+func (h *handler) setUploadHeaders(c *gin.Context, session *models.UploadSession) {
+
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.Size, 10))
+
+	expiresAt := time.Now().Add(time.Duration(session.ExpiresIn) * time.Second)
+	c.Header("Upload-Expires", expiresAt.UTC().Format(http.TimeFormat))
+
+	if len(session.FileID) > 0 {
+		c.Header("Upload-File-Id", session.FileID)
+	}
+}
+
+// storeChunk writes the request body as the next chunk of the session and
+// moves its offset. On failure the response is already written.
+func (h *handler) storeChunk(c *gin.Context, session *models.UploadSession, length int64, expected []byte) (*models.UploadSession, bool) {
+
+	ctx := c.Request.Context()
+	key := fmt.Sprintf("uploads/%s/%020d-%s", session.Id, session.Offset, uuid.New().String()[:8])
+
+	hash := sha256.New()
+	body := &countingReader{r: io.TeeReader(http.MaxBytesReader(c.Writer, c.Request.Body, length), hash)}
+
+	err := h.blobs.Put(ctx, key, body, length, tusOffsetMediaType)
+	if err != nil || body.n != length {
+		h.logger.Error("blobstore.Put", logger.String("key", key), logger.Error(err))
+		h.deleteChunk(ctx, key)
+		h.handlerResponse(c, "resumable upload chunk", http.StatusBadRequest, "chunk was not received completely")
+		return nil, false
+	}
+
+	if expected != nil && !bytes.Equal(hash.Sum(nil), expected) {
+		h.deleteChunk(ctx, key)
+		h.handlerResponse(c, "resumable upload checksum", statusChecksumMismatch, "Upload-Checksum doesn't match the chunk")
+		return nil, false
+	}
+
+	advanced, err := h.strg.UploadSession().AppendChunk(ctx, &models.AppendUploadChunk{
+		SessionID:  session.Id,
+		Offset:     session.Offset,
+		Size:       length,
+		StorageKey: key,
+		ExpiresIn:  int64(h.cfg.UploadSessionTTL.Seconds()),
+	})
+	if err != nil {
+		h.deleteChunk(ctx, key)
+		h.handlerResponse(c, "storage.UploadSession.appendChunk", http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	// Another request stored a chunk at this offset first
+	if advanced <= 0 {
+		h.deleteChunk(ctx, key)
+		h.handlerResponse(c, "resumable upload offset", http.StatusConflict, "Upload-Offset changed, ask for the current offset with HEAD")
+		return nil, false
+	}
+
+	resp, err := h.strg.UploadSession().GetByID(ctx, &models.UploadSessionPrimaryKey{Id: session.Id})
+	if err != nil {
+		h.handlerResponse(c, "storage.UploadSession.getById", http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	return resp, true
+}
+
+// completeUpload checks the assembled content against the policy of the
+// upload kind and registers it as a file. A disallowed type cancels the
+// upload. On failure the response is already written.
+func (h *handler) completeUpload(c *gin.Context, session *models.UploadSession) bool {
+
+	ctx := c.Request.Context()
+	key := &models.UploadSessionPrimaryKey{Id: session.Id}
+
+	chunks, err := h.strg.UploadSession().GetChunks(ctx, key)
+	if err != nil {
+		h.handlerResponse(c, "storage.UploadSession.getChunks", http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	var (
+		keys []string
+		next int64
+	)
+	for _, chunk := range chunks {
+		if chunk.Offset != next {
+			h.handlerResponse(c, "resumable upload chunks", http.StatusInternalServerError, "stored chunks are not contiguous")
+			return false
+		}
+		next += chunk.Size
+		keys = append(keys, chunk.StorageKey)
+	}
+
+	// First pass: hash the content and sniff its type
+	hash := sha256.New()
+	sniff := &headBuffer{limit: 512}
+
+	content := newChunkReader(ctx, h.blobs, keys)
+	_, err = io.Copy(io.MultiWriter(hash, sniff), content)
+	content.Close()
+	if err != nil {
+		h.logger.Error("read upload chunks", logger.Error(err))
+		h.handlerResponse(c, "resumable upload chunks", http.StatusInternalServerError, "Failed to read the upload")
+		return false
+	}
+
+	policy, err := upload.PolicyFor(upload.Kind(session.Kind), h.uploadLimits())
+	if err != nil {
+		h.handlerResponse(c, "resumable upload policy", http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	contentType := helper.DetectContentType(sniff.Bytes(), session.Filename)
+
+	err = policy.Check(session.Size, contentType)
+	if err != nil {
+		if err := jobs.DiscardUploadSession(ctx, h.strg, h.blobs, session); err != nil {
+			h.logger.Error("discard upload session", logger.Error(err))
+		}
+		h.handlerResponse(c, "resumable upload type", http.StatusUnsupportedMediaType, fmt.Sprintf("%s: %s", err, contentType))
+		return false
+	}
+
+	// Second pass, only for new content: store it
+	file, err := h.storeContent(ctx, &models.CreateFile{
+		OwnerID:      session.OwnerID,
+		Kind:         session.Kind,
+		OriginalName: session.Filename,
+		Size:         session.Size,
+		MimeType:     contentType,
+		Sha256:       hex.EncodeToString(hash.Sum(nil)),
+	}, func() (io.ReadCloser, error) {
+		return newChunkReader(ctx, h.blobs, keys), nil
+	})
+	if err != nil {
+		h.logger.Error("store upload", logger.Error(err))
+		h.handlerResponse(c, "store upload", http.StatusInternalServerError, "Failed to save the file")
+		return false
+	}
+
+	completed, err := h.strg.UploadSession().Complete(ctx, &models.CompleteUploadSession{Id: session.Id, FileID: file.Id})
+	if err != nil {
+		h.handlerResponse(c, "storage.UploadSession.complete", http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	if completed <= 0 {
+		h.handlerResponse(c, "resumable upload", http.StatusConflict, "upload was completed by another request")
+		return false
+	}
+
+	session.FileID = file.Id
+	session.CompletedAt = file.CreatedAt
+
+	// The chunks are not needed anymore, the expiry job retries what fails here
+	for _, chunkKey := range keys {
+		h.deleteChunk(ctx, chunkKey)
+	}
+	if err := h.strg.UploadSession().DeleteChunks(ctx, key); err != nil {
+		h.logger.Error("storage.UploadSession.deleteChunks", logger.Error(err))
+	}
+
+	return true
+}
+
+func (h *handler) deleteChunk(ctx context.Context, key string) {
+	if err := h.blobs.Delete(ctx, key); err != nil {
+		h.logger.Error("blobstore.Delete", logger.String("key", key), logger.Error(err))
+	}
+}
+
+// parseUploadMetadata decodes a tus Upload-Metadata header: comma separated
+// pairs of a key and a base64 encoded value.
+func parseUploadMetadata(header string) (map[string]string, error) {
+
+	metadata := map[string]string{}
+
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) <= 0 {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Upload-Metadata value for %q", key)
+		}
+
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
+
+// parseUploadChecksum decodes a tus Upload-Checksum header. Only sha256 is
+// supported; an empty header means no checksum.
+func parseUploadChecksum(header string) ([]byte, error) {
+
+	if len(header) <= 0 {
+		return nil, nil
+	}
+
+	algorithm, encoded, _ := strings.Cut(header, " ")
+	if algorithm != "sha256" {
+		return nil, errors.New("Upload-Checksum algorithm must be sha256")
+	}
+
+	sum, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sum) != sha256.Size {
+		return nil, errors.New("invalid Upload-Checksum digest")
+	}
+
+	return sum, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// headBuffer keeps the first limit bytes written to it.
+type headBuffer struct {
+	head  []byte
+	limit int
+}
+
+func (b *headBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - len(b.head); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		b.head = append(b.head, p[:room]...)
+	}
+	return len(p), nil
+}
+
+func (b *headBuffer) Bytes() []byte {
+	return b.head
+}
+
+// chunkReader reads the blobs under keys one after the other as a single
+// stream, opening each only when the previous one is done.
+type chunkReader struct {
+	ctx     context.Context
+	blobs   blobstore.Store
+	keys    []string
+	current io.ReadCloser
+}
+
+func newChunkReader(ctx context.Context, blobs blobstore.Store, keys []string) *chunkReader {
+	return &chunkReader{
+		ctx:   ctx,
+		blobs: blobs,
+		keys:  keys,
+	}
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.keys) <= 0 {
+				return 0, io.EOF
+			}
+
+			reader, _, err := r.blobs.Get(r.ctx, r.keys[0])
+			if err != nil {
+				return 0, err
+			}
+			r.current, r.keys = reader, r.keys[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.current != nil {
+		return r.current.Close()
+	}
+	return nil
+}
@@ -102,6 +102,10 @@ func (h *handler) GetByIdSemester(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param date_from query string false "created from, 2006-01-02 or RFC3339"
+// @Param date_to query string false "created until, 2006-01-02 or RFC3339"
+// @Param sort_by query string false "created_at, updated_at, semester_number"
+// @Param order query string false "asc or desc, default desc"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
@@ -119,13 +123,23 @@ func (h *handler) GetListSemester(c *gin.Context) {
 		return
 	}
 
+	err = h.validateListQuery(c)
+	if err != nil {
+		h.handlerResponse(c, "get list Semester filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
 	resp, err := h.strg.Semester().GetList(c.Request.Context(), &models.SemesterGetListRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+		Offset:   offset,
+		Limit:    limit,
+		Search:   c.Query("search"),
+		DateFrom: c.Query("date_from"),
+		DateTo:   c.Query("date_to"),
+		SortBy:   c.Query("sort_by"),
+		Order:    c.Query("order"),
 	})
 	if err != nil {
-		h.handlerResponse(c, "storage.Semester.get_list", http.StatusInternalServerError, err.Error())
+		h.listErrorResponse(c, "storage.Semester.get_list", err)
 		return
 	}
 
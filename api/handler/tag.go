@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/tags"
+)
+
+// GetListTag godoc
+// @ID get_list_tag
+// @Router /tags [GET]
+// @Summary Get List Tag
+// @Description Tags carried by published publications with how many carry each, most used first. prefix narrows the list for autocompletion.
+// @Tags Tag
+// @Accept json
+// @Procedure json
+// @Param prefix query string false "start of the tag"
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Param sort_by query string false "count, name, created_at"
+// @Param order query string false "asc or desc, default desc"
+// @Success 200 {object} Response{data=models.TagGetListResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) GetListTag(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list Tag offset", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list Tag limit", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	err = h.validateListQuery(c)
+	if err != nil {
+		h.handlerResponse(c, "get list Tag filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.strg.Tag().GetList(c.Request.Context(), &models.TagGetListRequest{
+		Prefix: c.Query("prefix"),
+		Offset: offset,
+		Limit:  limit,
+		SortBy: c.Query("sort_by"),
+		Order:  c.Query("order"),
+	})
+	if err != nil {
+		h.listErrorResponse(c, "storage.Tag.get_list", err)
+		return
+	}
+
+	h.handlerResponse(c, "get list Tag resposne", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// RenameTag godoc
+// @ID rename_tag
+// @Router /admin/tags/{id} [PUT]
+// @Summary Rename Tag
+// @Description Rename a tag on every publication carrying it, each getting a new revision. Renaming to an existing tag is refused, merge them instead.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param id path string true "id"
+// @Param Tag body models.RenameTag true "new name"
+// @Success 200 {object} Response{data=models.TagChange} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 404 {object} Response{data=string} "Not Found"
+// @Response 409 {object} Response{data=string} "Name taken by another tag"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) RenameTag(c *gin.Context) {
+
+	var (
+		id        string = c.Param("id")
+		renameTag models.RenameTag
+	)
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	err := c.ShouldBindJSON(&renameTag)
+	if err != nil {
+		h.handlerResponse(c, "error Tag should bind json", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	renameTag.Name, err = checkTagName(renameTag.Name)
+	if err != nil {
+		h.handlerResponse(c, "rename Tag name", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	info, _ := getAuthInfo(c)
+	renameTag.Id = id
+	renameTag.EditorID = info.UserID
+
+	resp, err := h.strg.Tag().Rename(c.Request.Context(), &renameTag)
+	if err != nil {
+		switch {
+		case err.Error() == "no rows in result set":
+			h.handlerResponse(c, "storage.Tag.rename", http.StatusNotFound, "tag not found")
+		case errors.Is(err, tags.ErrExists):
+			h.handlerResponse(c, "storage.Tag.rename", http.StatusConflict, err.Error())
+		default:
+			h.handlerResponse(c, "storage.Tag.rename", http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.handlerResponse(c, "rename Tag resposne", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// MergeTags godoc
+// @ID merge_tags
+// @Router /admin/tags/merge [POST]
+// @Summary Merge Tags
+// @Description Replace the source tags by the target tag, created when missing, on every publication carrying them; each gets a new revision. The source tags are removed.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param Merge body models.MergeTags true "tags to merge"
+// @Success 200 {object} Response{data=models.TagChange} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 404 {object} Response{data=string} "No source tag exists"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) MergeTags(c *gin.Context) {
+
+	var mergeTags models.MergeTags
+
+	err := c.ShouldBindJSON(&mergeTags)
+	if err != nil {
+		h.handlerResponse(c, "error Merge should bind json", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mergeTags.Target, err = checkTagName(mergeTags.Target)
+	if err != nil {
+		h.handlerResponse(c, "merge Tags target", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mergeTags.Sources = tags.Split(strings.Join(mergeTags.Sources, ","))
+	if len(mergeTags.Sources) <= 0 {
+		h.handlerResponse(c, "merge Tags sources", http.StatusBadRequest, "sources are required")
+		return
+	}
+
+	info, _ := getAuthInfo(c)
+	mergeTags.EditorID = info.UserID
+
+	resp, err := h.strg.Tag().Merge(c.Request.Context(), &mergeTags)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.Tag.merge", http.StatusNotFound, "no source tag exists")
+			return
+		}
+		h.handlerResponse(c, "storage.Tag.merge", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "merge Tags resposne", http.StatusOK, resp)
+}
+
+// checkTagName normalizes the name of one tag and validates it.
+func checkTagName(name string) (string, error) {
+
+	name = tags.Normalize(name)
+	if len(name) <= 0 {
+		return "", tags.ErrEmpty
+	}
+
+	// A comma would split the tag when publications list it
+	if strings.Contains(name, ",") {
+		return "", errors.New("a tag can't contain a comma")
+	}
+
+	return name, tags.Check([]string{name})
+}
+
+// tagFilter reads a tag filter from the query: the tags, repeated or comma
+// separated, and how to combine them.
+func tagFilter(c *gin.Context, tagsParam, matchParam string) ([]string, tags.Match, error) {
+
+	var (
+		names = tags.Split(strings.Join(c.QueryArray(tagsParam), ","))
+		match = tags.Match(c.Query(matchParam))
+	)
+
+	if !tags.ValidMatch(match) {
+		return nil, "", tags.ErrInvalidMatch
+	}
+
+	if err := tags.Check(names); err != nil {
+		return nil, "", err
+	}
+
+	return names, match, nil
+}
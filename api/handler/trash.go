@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/trash"
+)
+
+// @Security ApiKeyAuth
+// GetListTrash godoc
+// @ID get_list_trash
+// @Router /trash [GET]
+// @Summary Get List Trash
+// @Description Deleted semesters, courses, publications, users and notifications, most recently deleted first, with when they are purged for good
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param kind query string false "semester, course, publication, user or notification"
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
+// @Success 200 {object} Response{data=models.TrashGetListResponse} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) GetListTrash(c *gin.Context) {
+
+	offset, err := h.getOffsetQuery(c.Query("offset"))
+	if err != nil {
+		h.handlerResponse(c, "get list Trash offset", http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		h.handlerResponse(c, "get list Trash limit", http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	kind := c.Query("kind")
+	if len(kind) > 0 && !trash.Valid(trash.Kind(kind)) {
+		h.handlerResponse(c, "get list Trash kind", http.StatusBadRequest, trash.ErrInvalidKind.Error())
+		return
+	}
+
+	resp, err := h.strg.Trash().GetList(c.Request.Context(), &models.TrashGetListRequest{
+		Kind:      kind,
+		Offset:    offset,
+		Limit:     limit,
+		Retention: int64(h.cfg.TrashRetention.Seconds()),
+	})
+	if err != nil {
+		h.handlerResponse(c, "storage.Trash.get_list", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.handlerResponse(c, "get list Trash resposne", http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// RestoreTrash godoc
+// @ID restore_trash
+// @Router /trash/{kind}/{id}/restore [POST]
+// @Summary Restore From Trash
+// @Description Take a row out of the trash. Restoring a semester or course also restores the courses and publications deleted with it.
+// @Tags Admin
+// @Accept json
+// @Procedure json
+// @Param kind path string true "semester, course, publication, user or notification"
+// @Param id path string true "id"
+// @Success 204 {object} Response{data=string} "Success Request"
+// @Response 400 {object} Response{data=string} "Bad Request"
+// @Response 404 {object} Response{data=string} "Not in the trash"
+// @Response 409 {object} Response{data=string} "Parent in the trash or email/username taken"
+// @Failure 500 {object} Response{data=string} "Server error"
+func (h *handler) RestoreTrash(c *gin.Context) {
+
+	var (
+		kind string = c.Param("kind")
+		id   string = c.Param("id")
+	)
+
+	if !trash.Valid(trash.Kind(kind)) {
+		h.handlerResponse(c, "restore Trash kind", http.StatusBadRequest, trash.ErrInvalidKind.Error())
+		return
+	}
+
+	if !helper.IsValidUUID(id) {
+		h.handlerResponse(c, "is valid uuid", http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	restored, err := h.strg.Trash().Restore(c.Request.Context(), &models.TrashPrimaryKey{Kind: kind, Id: id})
+	if err != nil {
+		if errors.Is(err, trash.ErrParentDeleted) || errors.Is(err, trash.ErrTaken) {
+			h.handlerResponse(c, "storage.Trash.restore", http.StatusConflict, err.Error())
+			return
+		}
+		h.handlerResponse(c, "storage.Trash.restore", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if restored <= 0 {
+		h.handlerResponse(c, "storage.Trash.restore", http.StatusNotFound, "not in the trash")
+		return
+	}
+
+	h.handlerResponse(c, "restore Trash resposne", http.StatusNoContent, nil)
+}
@@ -1,17 +1,35 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"app/api/models"
+	"app/config"
+	"app/pkg/blobstore"
+	"app/pkg/helper"
+	"app/pkg/imaging"
+	"app/pkg/logger"
+	"app/pkg/upload"
 )
 
+// profileImagePrefix is the blob key prefix of profile images, other
+// uploads are stored at the root of the store.
+const profileImagePrefix = "profile_images/"
+
 func CheckType(filename string) string {
 	ext := strings.ToLower(filepath.Ext(filename))
 	switch ext {
@@ -26,130 +44,84 @@ func CheckType(filename string) string {
 	}
 }
 
-type ObjectHandle struct {
-	ObjectAttrs struct {
-		Metadata map[string]string
-	}
-}
-
-func (o *ObjectHandle) NewWriter(ctx context.Context) *ObjectWriter {
-	return &ObjectWriter{ObjectAttrs: &o.ObjectAttrs}
-}
-
-// type ObjectWriter struct {
-// 	ObjectAttrs *struct {
-// 		Metadata map[string]string
-// 	}
-// }
-
-// func (o *ObjectWriter) Close() error {
-// 	return nil
-// }
-
-func initializeObjectHandle(filename string) *ObjectHandle {
-	return &ObjectHandle{}
-}
-
-type ObjectWriter struct {
-	ObjectAttrs *struct {
-		Metadata map[string]string
-	}
-	buffer []byte // Simulate storage buffer
-}
-
-// Implement the Write method for ObjectWriter
-func (o *ObjectWriter) Write(p []byte) (n int, err error) {
-	o.buffer = append(o.buffer, p...) // Simulate writing data to a buffer
-	return len(p), nil
-}
-
-func (o *ObjectWriter) Close() error {
-	// Simulate closing the writer
-	fmt.Println("Data written:", string(o.buffer))
-	return nil
-}
-
-// UploadHandler handles file uploads and saves them locally.
+// UploadHandler handles file uploads and saves them in the blob store.
 // @Summary Upload an Image
-// @Description Upload an image file to the server and store it locally.
+// @Description Upload a file to the configured blob store. The response carries the file id to use as image_id or file_id of a publication.
 // @Tags File
 // @Accept multipart/form-data
 // @Produce application/json
 // @Param file formData file true "Image File"
+// @Param kind query string false "publication_cover, publication_file (default) or publication_video"
 // @Success 200 {object} Response "File uploaded successfully"
 // @Failure 400 {object} Response "Bad Request"
+// @Failure 413 {object} Response "File too large or quota exceeded"
+// @Failure 415 {object} Response "File type not allowed"
 // @Failure 500 {object} Response "Server Error"
 // @Router /uploadd [post]
 func (h *handler) UploadHandler(c *gin.Context) {
-	// Get the uploaded file
-	file, err := c.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No file is uploaded"})
+	kind := upload.Kind(c.DefaultQuery("kind", string(upload.KindPublicationFile)))
+	if kind == upload.KindProfileImage {
+		h.handlerResponse(c, "upload kind", http.StatusBadRequest, "kind must be publication_cover, publication_file or publication_video")
 		return
 	}
 
-	// Create a directory to store the files
-	storageDir := "./uploads"
-	if err := os.MkdirAll(storageDir, os.ModePerm); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create storage directory"})
-		return
-	}
-	uuid := uuid.New()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate UUID"})
-		return
-	}
-	filename := uuid.String() + filepath.Ext(file.Filename)
-	// Generate a unique filename
-	// timestamp := time.Now().Unix()
-	// ext := filepath.Ext(file.Filename)
-	// filename := fmt.Sprintf("%d%s", timestamp, ext)
-
-	// Save the file to the disk
-	savePath := filepath.Join(storageDir, filename)
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save the file"})
+	stored, ok := h.saveUpload(c, kind)
+	if !ok {
 		return
 	}
 
 	// Return success response
 	c.JSON(http.StatusOK, gin.H{
 		"message":  "File uploaded successfully",
-		"filename": filename,
-		"path":     savePath,
+		"id":       stored.Id,
+		"filename": path.Base(stored.StorageKey),
+		"path":     stored.StorageKey,
+		"file":     stored,
 	})
 }
 
 // ListImagesHandler lists all available images
-// @Summary List all uploaded images except Profile Images
-// @Description Get a list of all images that have been uploaded
+// @Summary List all uploaded profile images
+// @Description Get the file ids of the profile images that have been uploaded, with the URLs of their thumbnails
 // @Tags File
 // @Produce json
+// @Param offset query string false "offset"
+// @Param limit query string false "limit"
 // @Success 200 {object} map[string]interface{} "List of images"
 // @Failure 500 {object} map[string]interface{} "Internal Server Error"
 // @Router /images [get]
 func (h *handler) ListImagesHandler(c *gin.Context) {
-	uploadDir := "./uploads/profile_images"
 
-	files, err := os.ReadDir(uploadDir)
+	offset, err := h.getOffsetQuery(c.Query("offset"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read images directory"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
 		return
 	}
 
-	var images []string
+	limit, err := h.getLimitQuery(c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
 
-	// Loop through the files and get the filenames of image files
-	for _, file := range files {
-		if !file.IsDir() {
-			ext := strings.ToLower(filepath.Ext(file.Name()))
-			if ext == ".jpg" || ext == ".jpeg" || ext == ".png" || ext == ".gif" || ext == ".pdf" {
-				images = append(images, file.Name())
-			}
-		}
+	resp, err := h.strg.File().GetList(c.Request.Context(), &models.FileGetListRequest{
+		Offset: offset,
+		Limit:  limit,
+		Kind:   string(upload.KindProfileImage),
+	})
+	if err != nil {
+		h.logger.Error("storage.File.getList", logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list images"})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"images": images})
+	var images, thumbnails []string
+	for _, file := range resp.Files {
+		images = append(images, file.Id)
+		thumbnails = append(thumbnails, imageURL("/profile_image/", file.Id, imaging.VariantThumb))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"images": images, "thumbnails": thumbnails, "count": resp.Count})
 }
 
 // GetImageHandler serves the requested image dynamically
@@ -158,145 +130,81 @@ func (h *handler) ListImagesHandler(c *gin.Context) {
 // @Tags File
 // @Accept json
 // @Produce image/png, image/jpeg, image/gif
-// @Param filename path string true "Filename of the image"
+// @Param filename path string true "File ID of the image, as returned on upload"
+// @Param size query string false "original (default), medium (1024px) or thumb (256px)"
 // @Success 200 {file} file "The image file"
 // @Failure 400 {object} map[string]interface{} "Bad Request"
 // @Failure 404 {object} map[string]interface{} "Image Not Found"
 // @Failure 500 {object} map[string]interface{} "Internal Server Error"
 // @Router /image/{filename} [get]
 func (h *handler) GetImageHandler(c *gin.Context) {
-	// Get the filename from the URL parameter
-	filename := c.Param("filename")
-	if filename == "" {
-		// Handle missing filename
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Filename is required"})
+	file, ok := h.resolveStoredFile(c, "")
+	if !ok || !h.authorizeFileAccess(c, file) {
 		return
 	}
 
-	// Use the absolute path to the uploads directory
-	// Make sure to update the path according to your system's structure
-	imagePath := "./uploads/" + filename
-
-	// Check if the file exists
-	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-		// If the file doesn't exist, return a 404 error
-		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+	file, ok = h.imageVariant(c, file)
+	if !ok {
 		return
 	}
 
-	// Set the proper content type based on the file extension
-	ext := filepath.Ext(filename)
-	var contentType string
-	switch ext {
-	case ".jpg", ".jpeg":
-		contentType = "image/jpeg"
-	case ".png":
-		contentType = "image/png"
-	case ".gif":
-		contentType = "image/gif"
-	case ".pdf":
-		contentType = "image/pdf"
-	case ".docx":
-		contentType = "image/docx"
-	default:
-		// If the extension is unsupported, return an error
-		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Unsupported image type"})
-		return
-	}
-
-	// Set the response header to the content type
-	c.Header("Content-Type", contentType)
-
-	// Serve the image file
-	c.File(imagePath)
+	h.serveFile(c, file, false)
 }
 
-// UploadHandlerProfile handles file uploads and saves them locally.
+// UploadHandlerProfile handles file uploads and saves them in the blob store.
 // @Summary Upload an Image
-// @Description Upload an image file to the server and store it locally.
+// @Description Upload a file to the configured blob store. The response carries the file id to use as image_id or file_id of a publication.
 // @Tags File
 // @Accept multipart/form-data
 // @Produce application/json
 // @Param file formData file true "Image File"
 // @Success 200 {object} Response "File uploaded successfully"
 // @Failure 400 {object} Response "Bad Request"
+// @Failure 413 {object} Response "File too large or quota exceeded"
+// @Failure 415 {object} Response "File type not allowed"
 // @Failure 500 {object} Response "Server Error"
 // @Router /upload_profile [post]
 func (h *handler) UploadHandlerProfile(c *gin.Context) {
-	// Get the uploaded file
-	file, err := c.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No file is uploaded"})
-		return
-	}
-
-	// Create a directory to store the files
-	storageDir := "./uploads/profile_images"
-	if err := os.MkdirAll(storageDir, os.ModePerm); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create storage directory"})
-		return
-	}
-	uuid := uuid.New()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate UUID"})
-		return
-	}
-	filename := uuid.String() + filepath.Ext(file.Filename)
-	// Generate a unique filename
-	// timestamp := time.Now().Unix()
-	// ext := filepath.Ext(file.Filename)
-	// filename := fmt.Sprintf("%d%s", timestamp, ext)
-
-	// Save the file to the disk
-	savePath := filepath.Join(storageDir, filename)
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save the file"})
+	stored, ok := h.saveUpload(c, upload.KindProfileImage)
+	if !ok {
 		return
 	}
 
 	// Return success response
 	c.JSON(http.StatusOK, gin.H{
 		"message":  "File uploaded successfully",
-		"filename": filename,
-		"path":     savePath,
+		"id":       stored.Id,
+		"filename": path.Base(stored.StorageKey),
+		"path":     stored.StorageKey,
+		"file":     stored,
 	})
 }
 
 // FileDownloadFileHandler serves a file for download based on the filename provided in the URL.
+// When the file belongs to a publication the download is counted for it.
 // @Summary File Download a specific file
-// @Description File Download a file by its filename
+// @Description File Download a file by its filename. A client's downloads of a publication count once until DOWNLOAD_DEDUPE_WINDOW has passed since its last counted download.
 // @Tags File
-// @Param filename path string true "Filename of the file to download"
+// @Param filename path string true "File ID of the file to download, as returned on upload"
+// @Param expires query string false "expiry of a signed link"
+// @Param user query string false "user a signed link is bound to"
+// @Param signature query string false "signature of a signed link"
 // @Produce application/octet-stream
 // @Success 200 {file} file "The file for download"
 // @Failure 400 {object} map[string]interface{} "Bad Request"
+// @Failure 403 {object} map[string]interface{} "Missing, expired or invalid signed link"
 // @Failure 404 {object} map[string]interface{} "File Not Found"
 // @Failure 500 {object} map[string]interface{} "Internal Server Error"
 // @Router /file_download/{filename} [get]
 func (h *handler) FileDownloadFileHandler(c *gin.Context) {
-	// Get the filename from the URL parameter
-	filename := c.Param("filename")
-	if filename == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Filename is required"})
+	file, ok := h.resolveStoredFile(c, "")
+	if !ok || !h.authorizeFileAccess(c, file) {
 		return
 	}
 
-	// Construct the file path
-	filePath := "./uploads/" + filename
-
-	// Check if the file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
-		return
+	if h.serveFile(c, file, true) && len(file.Id) > 0 {
+		h.recordDownload(c, file.Id)
 	}
-
-	// Set the headers to indicate a file download
-	c.Header("Content-Description", "File Transfer")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	c.Header("Content-Type", "application/octet-stream")
-
-	// Serve the file
-	c.File(filePath)
 }
 
 // GetProfileImageHandler serves the requested image dynamically
@@ -305,55 +213,459 @@ func (h *handler) FileDownloadFileHandler(c *gin.Context) {
 // @Tags File
 // @Accept json
 // @Produce image/png, image/jpeg, image/gif
-// @Param filename path string true "Filename of the image"
+// @Param filename path string true "File ID of the image, as returned on upload"
+// @Param size query string false "original (default), medium (1024px) or thumb (256px)"
 // @Success 200 {file} file "The image file"
 // @Failure 400 {object} map[string]interface{} "Bad Request"
 // @Failure 404 {object} map[string]interface{} "Image Not Found"
 // @Failure 500 {object} map[string]interface{} "Internal Server Error"
 // @Router /profile_image/{filename} [get]
 func (h *handler) GetProfileImageHandler(c *gin.Context) {
-	// Get the filename from the URL parameter
+	file, ok := h.resolveStoredFile(c, profileImagePrefix)
+	if !ok || !h.authorizeFileAccess(c, file) {
+		return
+	}
+
+	file, ok = h.imageVariant(c, file)
+	if !ok {
+		return
+	}
+
+	h.serveFile(c, file, false)
+}
+
+// multipartOverhead is allowed on top of the file size for the rest of the
+// multipart body (boundaries, headers and other fields).
+const multipartOverhead = 1 << 20
+
+// saveUpload checks the "file" form field against the policy of the kind
+// and the quota of the caller, stores its content unless the same content
+// is already stored, and registers it in the files table. Images are
+// stored re-encoded without metadata, along with their resized variants. On failure the
+// response is already written.
+func (h *handler) saveUpload(c *gin.Context, kind upload.Kind) (*models.File, bool) {
+
+	policy, err := upload.PolicyFor(kind, h.uploadLimits())
+	if err != nil {
+		h.handlerResponse(c, "upload policy", http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	// Stop reading oversized bodies early instead of spooling them to disk
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, policy.MaxSize+multipartOverhead)
+
+	// Get the uploaded file
+	file, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.handlerResponse(c, "upload size", http.StatusRequestEntityTooLarge, upload.ErrTooLarge.Error())
+			return nil, false
+		}
+		h.handlerResponse(c, "upload form file", http.StatusBadRequest, "No file is uploaded")
+		return nil, false
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		h.handlerResponse(c, "upload open", http.StatusInternalServerError, "Failed to read the file")
+		return nil, false
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		h.handlerResponse(c, "upload read", http.StatusInternalServerError, "Failed to read the file")
+		return nil, false
+	}
+
+	contentType := helper.DetectContentType(head[:n], file.Filename)
+
+	err = policy.Check(file.Size, contentType)
+	if err != nil {
+		switch {
+		case errors.Is(err, upload.ErrTooLarge):
+			h.handlerResponse(c, "upload size", http.StatusRequestEntityTooLarge, fmt.Sprintf("%s, the limit is %d bytes", err, policy.MaxSize))
+		default:
+			h.handlerResponse(c, "upload type", http.StatusUnsupportedMediaType, fmt.Sprintf("%s: %s", err, contentType))
+		}
+		return nil, false
+	}
+
+	var (
+		content  io.ReadSeeker = src
+		size                   = file.Size
+		variants []*imaging.Encoded
+	)
+
+	// Images are stored re-encoded without their metadata, never as sent
+	if upload.Processed(kind) {
+		data, err := io.ReadAll(io.NewSectionReader(src, 0, file.Size))
+		if err != nil {
+			h.handlerResponse(c, "upload read", http.StatusInternalServerError, "Failed to read the file")
+			return nil, false
+		}
+
+		images, err := imaging.Process(data, imaging.Variants...)
+		if err != nil {
+			switch {
+			case errors.Is(err, imaging.ErrTooManyPixels):
+				h.handlerResponse(c, "upload image", http.StatusRequestEntityTooLarge, fmt.Sprintf("%s, the limit is %d pixels", err, imaging.MaxPixels))
+			case errors.Is(err, imaging.ErrUnsupported):
+				h.handlerResponse(c, "upload image", http.StatusUnsupportedMediaType, err.Error())
+			default:
+				h.logger.Error("imaging.Process", logger.Error(err))
+				h.handlerResponse(c, "upload image", http.StatusInternalServerError, "Failed to process the image")
+			}
+			return nil, false
+		}
+
+		original := images[0]
+		content, size, contentType = bytes.NewReader(original.Data), int64(len(original.Data)), original.ContentType
+		variants = images[1:]
+	}
+
+	// Hash the whole content first, identical files are stored only once
+	hash := sha256.New()
+	_, err = content.Seek(0, io.SeekStart)
+	if err == nil {
+		_, err = io.Copy(hash, content)
+	}
+	if err != nil {
+		h.handlerResponse(c, "upload hash", http.StatusInternalServerError, "Failed to read the file")
+		return nil, false
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		h.handlerResponse(c, "upload seek", http.StatusInternalServerError, "Failed to read the file")
+		return nil, false
+	}
+
+	// Admins have no quota, users reserve the space before storing
+	info, _ := getAuthInfo(c)
+	if info.Role == config.RoleUser {
+		reserved, err := h.strg.User().ReserveStorage(c.Request.Context(), info.UserID, size, h.cfg.UserStorageQuota)
+		if err != nil {
+			h.handlerResponse(c, "storage.User.reserveStorage", http.StatusInternalServerError, err.Error())
+			return nil, false
+		}
+
+		if !reserved {
+			h.handlerResponse(c, "upload quota", http.StatusRequestEntityTooLarge, fmt.Sprintf("%s, the quota is %d bytes", upload.ErrQuotaExceeded, h.cfg.UserStorageQuota))
+			return nil, false
+		}
+	}
+
+	resp, err := h.storeContent(c.Request.Context(), &models.CreateFile{
+		OwnerID:      info.UserID,
+		Kind:         string(kind),
+		OriginalName: file.Filename,
+		Size:         size,
+		MimeType:     contentType,
+		Sha256:       sum,
+	}, func() (io.ReadCloser, error) {
+		return io.NopCloser(content), nil
+	})
+	if err != nil {
+		h.logger.Error("store upload", logger.Error(err))
+		h.releaseUpload(c, info, size)
+		h.handlerResponse(c, "store upload", http.StatusInternalServerError, "Failed to save the file")
+		return nil, false
+	}
+
+	// The file is usable without its variants, they are rendered again on
+	// request when storing them fails here
+	for _, variant := range variants {
+		if _, err := h.storeVariant(c.Request.Context(), resp, variant); err != nil {
+			h.logger.Error("store image variant", logger.String("variant", string(variant.Variant)), logger.Error(err))
+		}
+	}
+
+	return resp, true
+}
+
+// storeContent registers a new file for content whose SHA-256 is known.
+// New content is written under a key derived from its hash, opened through
+// open only then. Writing the same content twice is harmless, so concurrent
+// uploads need no lock.
+func (h *handler) storeContent(ctx context.Context, req *models.CreateFile, open func() (io.ReadCloser, error)) (*models.File, error) {
+
+	blob, err := h.putBlob(ctx, &models.CreateBlob{
+		Sha256:   req.Sha256,
+		Size:     req.Size,
+		MimeType: req.MimeType,
+	}, open)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Id = uuid.New().String()
+	req.StorageKey = blob.StorageKey
+
+	id, err := h.strg.File().Create(ctx, req)
+	if err != nil {
+		if err := h.strg.Blob().Release(ctx, &models.BlobPrimaryKey{Sha256: req.Sha256}); err != nil {
+			h.logger.Error("storage.Blob.release", logger.Error(err))
+		}
+		return nil, err
+	}
+
+	return h.strg.File().GetByID(ctx, &models.FilePrimaryKey{Id: id})
+}
+
+// putBlob stores the content with the given SHA-256 unless it is stored
+// already, and takes a reference on its blob. The caller gives the
+// reference back with Blob().Release when it ends up not using it.
+func (h *handler) putBlob(ctx context.Context, req *models.CreateBlob, open func() (io.ReadCloser, error)) (*models.Blob, error) {
+
+	req.StorageKey = blobKey(req.Sha256)
+
+	written := false
+
+	_, err := h.strg.Blob().GetByID(ctx, &models.BlobPrimaryKey{Sha256: req.Sha256})
+	if err != nil {
+		if err.Error() != "no rows in result set" {
+			return nil, err
+		}
+
+		err = h.putContent(ctx, req.StorageKey, req, open)
+		if err != nil {
+			return nil, err
+		}
+		written = true
+	}
+
+	blob, err := h.strg.Blob().Acquire(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The garbage collector may have deleted the unreferenced blob between
+	// the lookup and Acquire. It deletes the content before giving up the
+	// row, so a first reference means the content has to be checked.
+	if !written && blob.RefCount == 1 {
+		_, err = h.blobs.Stat(ctx, blob.StorageKey)
+		if errors.Is(err, blobstore.ErrNotFound) {
+			err = h.putContent(ctx, blob.StorageKey, req, open)
+		}
+		if err != nil {
+			if err := h.strg.Blob().Release(ctx, &models.BlobPrimaryKey{Sha256: req.Sha256}); err != nil {
+				h.logger.Error("storage.Blob.release", logger.Error(err))
+			}
+			return nil, err
+		}
+	}
+
+	return blob, nil
+}
+
+func (h *handler) putContent(ctx context.Context, key string, req *models.CreateBlob, open func() (io.ReadCloser, error)) error {
+
+	content, err := open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	return h.blobs.Put(ctx, key, content, req.Size, req.MimeType)
+}
+
+// uploadLimits returns the configured size limit of every upload kind.
+func (h *handler) uploadLimits() upload.Limits {
+	return upload.Limits{
+		ProfileImage:     h.cfg.UploadMaxProfileImage,
+		PublicationCover: h.cfg.UploadMaxPublicationCover,
+		PublicationFile:  h.cfg.UploadMaxPublicationFile,
+		PublicationVideo: h.cfg.UploadMaxPublicationVideo,
+	}
+}
+
+// blobKey is the storage key of the content with the given SHA-256, fanned
+// out by the first two hex digits to keep directories small.
+func blobKey(sum string) string {
+	return "blobs/" + sum[:2] + "/" + sum
+}
+
+// releaseUpload gives back the quota a user reserved for a failed upload.
+func (h *handler) releaseUpload(c *gin.Context, info helper.TokenInfo, size int64) {
+
+	if info.Role == config.RoleUser {
+		if err := h.strg.User().ReleaseStorage(c.Request.Context(), info.UserID, size); err != nil {
+			h.logger.Error("storage.User.releaseStorage", logger.Error(err))
+		}
+	}
+}
+
+// resolveStoredFile looks up the file named by the :filename parameter. The
+// parameter is the file id returned on upload or, for older clients, the
+// stored name "<id>.<ext>". Blobs stored before the files table existed are
+// served by name under prefix. Nothing tells what such a blob holds, so it
+// has no kind and is only served through a signed link. On failure the
+// response is already written.
+func (h *handler) resolveStoredFile(c *gin.Context, prefix string) (*models.File, bool) {
+
 	filename := c.Param("filename")
 	if filename == "" {
-		// Handle missing filename
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Filename is required"})
-		return
+		return nil, false
 	}
 
-	// Use the absolute path to the uploads directory
-	// Make sure to update the path according to your system's structure
-	imagePath := "./uploads/profile_images/" + filename
+	if !helper.IsValidStoredFileName(filename) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filename"})
+		return nil, false
+	}
 
-	// Check if the file exists
-	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-		// If the file doesn't exist, return a 404 error
-		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
-		return
+	id := strings.TrimSuffix(filename, filepath.Ext(filename))
+
+	file, err := h.strg.File().GetByID(c.Request.Context(), &models.FilePrimaryKey{Id: id})
+	if err == nil {
+		return file, true
 	}
 
-	// Set the proper content type based on the file extension
-	ext := filepath.Ext(filename)
-	var contentType string
-	switch ext {
-	case ".jpg", ".jpeg":
-		contentType = "image/jpeg"
-	case ".png":
-		contentType = "image/png"
-	case ".gif":
-		contentType = "image/gif"
-	case ".pdf":
-		contentType = "image/pdf"
-	case ".docx":
-		contentType = "image/docx"
+	if err.Error() != "no rows in result set" {
+		h.logger.Error("storage.File.getById", logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the file"})
+		return nil, false
+	}
+
+	return &models.File{
+		OriginalName: filename,
+		StorageKey:   prefix + filename,
+	}, true
+}
+
+// authorizeFileAccess applies the access policy of the file kind. Files
+// that aren't public need a valid signed link, unless the caller owns the
+// file or is an admin. Files stored before the files table have no id
+// and are signed by name. On failure the response is already written.
+func (h *handler) authorizeFileAccess(c *gin.Context, file *models.File) bool {
+
+	if upload.AccessFor(upload.Kind(file.Kind)) == upload.AccessPublic {
+		return true
+	}
+
+	info, _ := getAuthInfo(c)
+	if isAdmin(c) || (len(file.OwnerID) > 0 && file.OwnerID == info.UserID) {
+		return true
+	}
+
+	subject := file.Id
+	if len(subject) <= 0 {
+		subject = file.OriginalName
+	}
+
+	err := helper.VerifyDownloadLink(h.cfg.DownloadLinkSecret, subject, info.UserID, c.Request.URL.Query(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return false
+	}
+
+	return true
+}
+
+// serveFile answers a request for the content of file. Range, If-Range,
+// If-None-Match and If-Modified-Since are handled by http.ServeContent over
+// a seekable reader of the blob, so only the requested bytes are fetched
+// from the store. The ETag is the SHA-256 of the content. The content type
+// recorded on upload is used, files without one are sniffed, and only
+// types that are safe to render are sent inline. It reports whether the
+// content was sent from its first byte; otherwise an error response, a
+// 304 or a later range was written.
+func (h *handler) serveFile(c *gin.Context, file *models.File, attachment bool) bool {
+
+	ctx := c.Request.Context()
+
+	object, err := h.blobs.Stat(ctx, file.StorageKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) || errors.Is(err, blobstore.ErrInvalidKey) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+			return false
+		}
+		h.logger.Error("blobstore.Stat", logger.String("key", file.StorageKey), logger.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the file"})
+		return false
+	}
+
+	content := blobstore.NewReadSeeker(ctx, h.blobs, file.StorageKey, object.Size)
+	defer content.Close()
+
+	contentType := file.MimeType
+	if len(contentType) <= 0 || contentType == "application/octet-stream" {
+		head := make([]byte, 512)
+		n, err := io.ReadFull(content, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			h.logger.Error("blobstore read", logger.String("key", file.StorageKey), logger.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the file"})
+			return false
+		}
+		contentType = helper.DetectContentType(head[:n], file.OriginalName)
+
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the file"})
+			return false
+		}
+	}
+
+	disposition := "inline"
+	if attachment || !helper.IsInlineContentType(contentType) {
+		disposition = "attachment"
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", helper.ContentDisposition(disposition, file.OriginalName))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Cache-Control", upload.CacheControl(upload.Kind(file.Kind)))
+	if len(file.Sha256) > 0 {
+		header.Set("ETag", `"`+file.Sha256+`"`)
+	}
+
+	http.ServeContent(c.Writer, c.Request, "", object.ModTime, content)
+
+	switch c.Writer.Status() {
+	case http.StatusOK:
+		return true
+	case http.StatusPartialContent:
+		return strings.HasPrefix(c.GetHeader("Range"), "bytes=0-")
 	default:
-		// If the extension is unsupported, return an error
-		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Unsupported image type"})
+		return false
+	}
+}
+
+// recordDownload appends a download event for the publication the file
+// with the given id belongs to. Clients are told apart by user id, or by IP and user agent
+// for guests. Failing to record never blocks the download itself.
+func (h *handler) recordDownload(c *gin.Context, fileID string) {
+
+	publication, err := h.strg.Publication().GetByID(c.Request.Context(), &models.PublicationPrimaryKey{FileID: fileID})
+	if err != nil {
+		if err.Error() != "no rows in result set" {
+			h.logger.Error("storage.Publication.getById", logger.Error(err))
+		}
 		return
 	}
 
-	// Set the response header to the content type
-	c.Header("Content-Type", contentType)
+	var (
+		info, _   = getAuthInfo(c)
+		userAgent = c.Request.UserAgent()
+		client    = "user:" + info.UserID
+	)
+
+	if info.Role != config.RoleUser {
+		info.UserID = ""
+		client = "guest:" + c.ClientIP() + "|" + userAgent
+	}
 
-	// Serve the image file
-	c.File(imagePath)
+	_, err = h.strg.Download().Create(c.Request.Context(), &models.CreateDownload{
+		PublicationID: publication.Id,
+		ContributorID: info.UserID,
+		UserAgentHash: helper.HashToken(userAgent),
+		ClientHash:    helper.HashToken(client),
+		DedupeWindow:  int64(h.cfg.DownloadDedupeWindow.Seconds()),
+	})
+	if err != nil {
+		h.logger.Error("storage.Download.create", logger.Error(err))
+	}
 }
@@ -3,12 +3,14 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"app/api/models"
 	"app/pkg/helper"
+	"app/pkg/logger"
 )
 
 //// @Security ApiKeyAuth
@@ -34,6 +36,12 @@ func (h *handler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	createUser.Password, err = helper.HashPassword(createUser.Password)
+	if err != nil {
+		h.handlerResponse(c, "helper.HashPassword", http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	id, err := h.strg.User().Create(c.Request.Context(), &createUser)
 	if err != nil {
 		h.handlerResponse(c, "storage.User.create", http.StatusInternalServerError, err.Error())
@@ -105,6 +113,11 @@ func (h *handler) GetByIdUser(c *gin.Context) {
 // @Param offset query string false "offset"
 // @Param limit query string false "limit"
 // @Param search query string false "search"
+// @Param status query string false "status"
+// @Param date_from query string false "created from, 2006-01-02 or RFC3339"
+// @Param date_to query string false "created until, 2006-01-02 or RFC3339"
+// @Param sort_by query string false "created_at, updated_at, name, surname, email, grade"
+// @Param order query string false "asc or desc, default desc"
 // @Success 200 {object} Response{data=string} "Success Request"
 // @Response 400 {object} Response{data=string} "Bad Request"
 // @Failure 500 {object} Response{data=string} "Server error"
@@ -122,13 +135,31 @@ func (h *handler) GetListUser(c *gin.Context) {
 		return
 	}
 
+	err = h.validateListQuery(c)
+	if err != nil {
+		h.handlerResponse(c, "get list User filters", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if status := c.Query("status"); len(status) > 0 {
+		if _, err := strconv.ParseBool(status); err != nil {
+			h.handlerResponse(c, "get list User status", http.StatusBadRequest, "invalid status")
+			return
+		}
+	}
+
 	resp, err := h.strg.User().GetList(c.Request.Context(), &models.UserGetListRequest{
-		Offset: offset,
-		Limit:  limit,
-		Search: c.Query("search"),
+		Offset:   offset,
+		Limit:    limit,
+		Search:   c.Query("search"),
+		Status:   c.Query("status"),
+		DateFrom: c.Query("date_from"),
+		DateTo:   c.Query("date_to"),
+		SortBy:   c.Query("sort_by"),
+		Order:    c.Query("order"),
 	})
 	if err != nil {
-		h.handlerResponse(c, "storage.User.get_list", http.StatusInternalServerError, err.Error())
+		h.listErrorResponse(c, "storage.User.get_list", err)
 		return
 	}
 
@@ -140,7 +171,7 @@ func (h *handler) GetListUser(c *gin.Context) {
 // @ID update_user
 // @Router /user/{id} [PUT]
 // @Summary Update User
-// @Description Update User
+// @Description Update User. Changing the email unverifies it and sends a code to the new address.
 // @Tags User
 // @Accept json
 // @Procedure json
@@ -161,12 +192,41 @@ func (h *handler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	if !isSelfOrAdmin(c, id) {
+		h.handlerResponse(c, "update user permission", http.StatusForbidden, "permission denied")
+		return
+	}
+
 	err := c.ShouldBindJSON(&updateUser)
 	if err != nil {
 		h.handlerResponse(c, "error User should bind json", http.StatusBadRequest, err.Error())
 		return
 	}
+
+	if len(updateUser.Password) > 0 {
+		updateUser.Password, err = helper.HashPassword(updateUser.Password)
+		if err != nil {
+			h.handlerResponse(c, "helper.HashPassword", http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
 	updateUser.Id = id
+
+	user, err := h.strg.User().GetByID(c.Request.Context(), &models.UserPrimaryKey{Id: id})
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			h.handlerResponse(c, "storage.User.getById", http.StatusNotFound, "user not found")
+			return
+		}
+		h.handlerResponse(c, "storage.User.getById", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Only admins may activate or deactivate an account
+	if !isAdmin(c) {
+		updateUser.Status = user.Status
+	}
+
 	rowsAffected, err := h.strg.User().Update(c.Request.Context(), &updateUser)
 	if err != nil {
 		h.handlerResponse(c, "storage.User.update", http.StatusInternalServerError, err.Error())
@@ -184,6 +244,15 @@ func (h *handler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	// A changed email is no longer verified, the new address gets a code. A
+	// failed delivery can be retried through /register/resend
+	if resp.Email != user.Email && !resp.EmailVerified {
+		err = h.sendOtp(c.Request.Context(), resp.Email, models.OtpPurposeEmailVerification)
+		if err != nil {
+			h.logger.Error("send verification code", logger.Error(err))
+		}
+	}
+
 	h.handlerResponse(c, "create User resposne", http.StatusAccepted, resp)
 }
 
@@ -215,6 +284,13 @@ func (h *handler) DeleteUser(c *gin.Context) {
 		return
 	}
 
+	// A user in the trash can't log in, their refresh tokens go too
+	_, err = h.strg.Session().RevokeAllByUser(c.Request.Context(), id)
+	if err != nil {
+		h.handlerResponse(c, "storage.Session.revokeAllByUser", http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	h.handlerResponse(c, "create User resposne", http.StatusNoContent, nil)
 }
 
@@ -13,7 +13,7 @@ type CreateAdmin struct {
 type Admin struct {
 	Id        string `json:"id"`
 	Email     string `json:"email"`
-	Password  string `json:"password"`
+	Password  string `json:"-"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -25,9 +25,13 @@ type UpdateAdmin struct {
 }
 
 type AdminGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	Search   string `json:"search"`
+	DateFrom string `json:"date_from"`
+	DateTo   string `json:"date_to"`
+	SortBy   string `json:"sort_by"`
+	Order    string `json:"order"`
 }
 
 type AdminGetListResponse struct {
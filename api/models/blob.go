@@ -0,0 +1,22 @@
+package models
+
+type BlobPrimaryKey struct {
+	Sha256 string `json:"sha256"`
+}
+
+type CreateBlob struct {
+	Sha256     string `json:"sha256"`
+	StorageKey string `json:"storage_key"`
+	Size       int64  `json:"size"`
+	MimeType   string `json:"mime_type"`
+}
+
+type Blob struct {
+	Sha256     string `json:"sha256"`
+	StorageKey string `json:"storage_key"`
+	Size       int64  `json:"size"`
+	MimeType   string `json:"mime_type"`
+	RefCount   int    `json:"ref_count"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
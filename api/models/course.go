@@ -24,9 +24,14 @@ type UpdateCourse struct {
 }
 
 type CourseGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+	Search     string `json:"search"`
+	SemesterID string `json:"semester_id"`
+	DateFrom   string `json:"date_from"`
+	DateTo     string `json:"date_to"`
+	SortBy     string `json:"sort_by"`
+	Order      string `json:"order"`
 }
 
 type CourseGetListResponse struct {
@@ -2,34 +2,38 @@ package models
 
 type DownloadPrimaryKey struct {
 	Id string `json:"id"`
+	// OwnerID, when set, limits Delete to the downloads of that user.
+	OwnerID string `json:"-"`
 }
 
+// CreateDownload is one download served by the API. Downloads by the same
+// client of the same publication within DedupeWindow seconds count once.
 type CreateDownload struct {
-	Count         float64 `json:"count"`
-	PublicationID string  `json:"publication_id"`
-	ContributorID string  `json:"contributor_id"`
+	PublicationID string `json:"publication_id"`
+	ContributorID string `json:"contributor_id"`
+	UserAgentHash string `json:"-"`
+	ClientHash    string `json:"-"`
+	DedupeWindow  int64  `json:"-"`
 }
 
 type Download struct {
-	Id            string  `json:"id"`
-	Count         float64 `json:"count"`
-	PublicationID string  `json:"publication_id"`
-	ContributorID string  `json:"contributor_id"`
-	CreatedAt     string  `json:"created_at"`
-	UpdatedAt     string  `json:"updated_at"`
-}
-
-type UpdateDownload struct {
-	Id            string  `json:"id"`
-	Count         float64 `json:"count"`
-	PublicationID string  `json:"publication_id"`
-	ContributorID string  `json:"contributor_id"`
+	Id            string `json:"id"`
+	PublicationID string `json:"publication_id"`
+	ContributorID string `json:"contributor_id"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
 }
 
 type DownloadGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset        int    `json:"offset"`
+	Limit         int    `json:"limit"`
+	Search        string `json:"search"`
+	PublicationID string `json:"publication_id"`
+	ContributorID string `json:"contributor_id"`
+	DateFrom      string `json:"date_from"`
+	DateTo        string `json:"date_to"`
+	SortBy        string `json:"sort_by"`
+	Order         string `json:"order"`
 }
 
 type DownloadGetListResponse struct {
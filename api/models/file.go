@@ -0,0 +1,77 @@
+package models
+
+type FilePrimaryKey struct {
+	Id         string `json:"id"`
+	StorageKey string `json:"storage_key"`
+}
+
+type CreateFile struct {
+	Id           string `json:"id"`
+	OwnerID      string `json:"owner_id"`
+	Kind         string `json:"kind"`
+	OriginalName string `json:"original_name"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mime_type"`
+	Sha256       string `json:"sha256"`
+	StorageKey   string `json:"storage_key"`
+}
+
+type File struct {
+	Id           string `json:"id"`
+	OwnerID      string `json:"owner_id"`
+	Kind         string `json:"kind"`
+	OriginalName string `json:"original_name"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mime_type"`
+	Sha256       string `json:"sha256"`
+	StorageKey   string `json:"storage_key"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type FileGetListRequest struct {
+	Offset  int    `json:"offset"`
+	Limit   int    `json:"limit"`
+	OwnerID string `json:"owner_id"`
+	Kind    string `json:"kind"`
+}
+
+type FileGetListResponse struct {
+	Count int     `json:"count"`
+	Files []*File `json:"files"`
+}
+
+// DownloadLink is a signed, expiring URL for a file that isn't public.
+type DownloadLink struct {
+	FileID    string `json:"file_id"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type FileVariantPrimaryKey struct {
+	FileID  string `json:"file_id"`
+	Variant string `json:"variant"`
+}
+
+type CreateFileVariant struct {
+	FileID     string `json:"file_id"`
+	Variant    string `json:"variant"`
+	Sha256     string `json:"sha256"`
+	StorageKey string `json:"storage_key"`
+	Size       int64  `json:"size"`
+	MimeType   string `json:"mime_type"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+}
+
+// FileVariant is a resized rendition of an image file.
+type FileVariant struct {
+	FileID     string `json:"file_id"`
+	Variant    string `json:"variant"`
+	Sha256     string `json:"sha256"`
+	StorageKey string `json:"storage_key"`
+	Size       int64  `json:"size"`
+	MimeType   string `json:"mime_type"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	CreatedAt  string `json:"created_at"`
+}
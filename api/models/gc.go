@@ -0,0 +1,23 @@
+package models
+
+// OrphanRequest selects unreferenced rows that haven't changed for
+// OlderThan seconds.
+type OrphanRequest struct {
+	OlderThan int64 `json:"older_than"`
+	Offset    int   `json:"offset"`
+	Limit     int   `json:"limit"`
+}
+
+// GarbageReport lists what a garbage collection run deleted, or would
+// delete in dry-run mode.
+type GarbageReport struct {
+	DryRun bool `json:"dry_run"`
+	// Files are files rows no publication, user or upload refers to.
+	Files []*File `json:"files"`
+	// Blobs are stored contents no file refers to anymore.
+	Blobs []*Blob `json:"blobs"`
+	// StrayKeys are objects in the blob store nothing in the database
+	// knows about.
+	StrayKeys  []string `json:"stray_keys"`
+	FreedBytes int64    `json:"freed_bytes"`
+}
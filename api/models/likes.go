@@ -2,34 +2,41 @@ package models
 
 type LikePrimaryKey struct {
 	Id string `json:"id"`
+	// OwnerID, when set, limits Delete to the likes of that user.
+	OwnerID string `json:"-"`
 }
 
-type CreateLike struct {
-	Count         float64 `json:"count"`
-	PublicationID string  `json:"publication_id"`
-	ContributorID string  `json:"contributor_id"`
+// LikeKey identifies the like of one user on one publication.
+type LikeKey struct {
+	PublicationID string `json:"publication_id"`
+	ContributorID string `json:"contributor_id"`
 }
 
 type Like struct {
-	Id            string  `json:"id"`
-	Count         float64 `json:"count"`
-	PublicationID string  `json:"publication_id"`
-	ContributorID string  `json:"contributor_id"`
-	CreatedAt     string  `json:"created_at"`
-	UpdatedAt     string  `json:"updated_at"`
+	Id            string `json:"id"`
+	PublicationID string `json:"publication_id"`
+	ContributorID string `json:"contributor_id"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
 }
 
-type UpdateLike struct {
-	Id            string  `json:"id"`
-	Count         float64 `json:"count"`
-	PublicationID string  `json:"publication_id"`
-	ContributorID string  `json:"contributor_id"`
+// LikeStatus is returned by the like and unlike endpoints.
+type LikeStatus struct {
+	PublicationID string `json:"publication_id"`
+	LikedByMe     bool   `json:"liked_by_me"`
+	LikeCount     int    `json:"like_count"`
 }
 
 type LikeGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset        int    `json:"offset"`
+	Limit         int    `json:"limit"`
+	Search        string `json:"search"`
+	PublicationID string `json:"publication_id"`
+	ContributorID string `json:"contributor_id"`
+	DateFrom      string `json:"date_from"`
+	DateTo        string `json:"date_to"`
+	SortBy        string `json:"sort_by"`
+	Order         string `json:"order"`
 }
 
 type LikeGetListResponse struct {
@@ -0,0 +1,124 @@
+package models
+
+// ModerationQueueRequest lists the publications waiting for review, oldest
+// submission first.
+type ModerationQueueRequest struct {
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	CourseID string `json:"course_id"`
+	// ModeratorID is the caller. With Available, items another moderator
+	// holds a live claim on are left out.
+	ModeratorID string `json:"-"`
+	Available   bool   `json:"available"`
+}
+
+type ModerationQueueResponse struct {
+	Count int                    `json:"count"`
+	Items []*ModerationQueueItem `json:"items"`
+}
+
+// ModerationQueueItem is a pending publication with what a moderator needs
+// to decide on it.
+type ModerationQueueItem struct {
+	Publication *Publication `json:"publication"`
+	// SubmittedAt is when the publication last entered pending_review.
+	SubmittedAt string                 `json:"submitted_at"`
+	Contributor *ModerationContributor `json:"contributor"`
+	// File is the attached file, nil for publications without one.
+	File *ModerationFile `json:"file,omitempty"`
+	// Claim is the live claim on the item, nil when nobody holds one.
+	Claim *ModerationClaim `json:"claim,omitempty"`
+	// Duplicates are other publications whose file has the same content.
+	Duplicates []*PublicationDuplicate `json:"duplicates,omitempty"`
+}
+
+type ModerationContributor struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Surname  string `json:"surname"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	// Published and Rejected count the publications of the contributor in
+	// these statuses, a hint of their track record.
+	Published int `json:"published"`
+	Rejected  int `json:"rejected"`
+}
+
+type ModerationFile struct {
+	Id           string `json:"id"`
+	OriginalName string `json:"original_name"`
+	MimeType     string `json:"mime_type"`
+	Size         int64  `json:"size"`
+}
+
+// ModerationClaimRequest takes or extends a claim on PublicationID for TTL
+// seconds.
+type ModerationClaimRequest struct {
+	PublicationID string `json:"publication_id"`
+	ModeratorID   string `json:"moderator_id"`
+	TTL           int64  `json:"ttl"`
+}
+
+// ModerationReleaseRequest drops the claim of ModeratorID, or any claim
+// with Force.
+type ModerationReleaseRequest struct {
+	PublicationID string `json:"publication_id"`
+	ModeratorID   string `json:"moderator_id"`
+	Force         bool   `json:"force"`
+}
+
+type ModerationClaim struct {
+	PublicationID string `json:"publication_id"`
+	ModeratorID   string `json:"moderator_id"`
+	ClaimedAt     string `json:"claimed_at"`
+	ExpiresAt     string `json:"expires_at"`
+}
+
+// ModerationBulkRequest approves or rejects several publications. Reason
+// is required to reject and applies to every item.
+type ModerationBulkRequest struct {
+	Ids    []string `json:"ids"`
+	Reason string   `json:"reason"`
+}
+
+// ModerationBulkResult reports each item of a bulk review on its own, one
+// failing item doesn't stop the others.
+type ModerationBulkResult struct {
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Items     []*ModerationBulkItem `json:"items"`
+}
+
+type ModerationBulkItem struct {
+	Id     string `json:"id"`
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ModerationStatsRequest limits the stats to decisions made in a date
+// range.
+type ModerationStatsRequest struct {
+	DateFrom string `json:"date_from"`
+	DateTo   string `json:"date_to"`
+}
+
+type ModerationStats struct {
+	// Pending is the current length of the queue and OldestPendingAt the
+	// submission time of its first item.
+	Pending         int                    `json:"pending"`
+	OldestPendingAt string                 `json:"oldest_pending_at"`
+	Moderators      []*ModeratorThroughput `json:"moderators"`
+}
+
+// ModeratorThroughput counts the decisions of one moderator.
+// AvgReviewSeconds is the mean time from submission to decision.
+type ModeratorThroughput struct {
+	ModeratorID      string  `json:"moderator_id"`
+	Email            string  `json:"email"`
+	Approved         int     `json:"approved"`
+	Rejected         int     `json:"rejected"`
+	Total            int     `json:"total"`
+	AvgReviewSeconds float64 `json:"avg_review_seconds"`
+	LastDecisionAt   string  `json:"last_decision_at"`
+	ActiveClaims     int     `json:"active_claims"`
+}
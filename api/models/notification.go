@@ -33,9 +33,14 @@ type UpdateNotification struct {
 }
 
 type NotificationGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset      int    `json:"offset"`
+	Limit       int    `json:"limit"`
+	Search      string `json:"search"`
+	MessageType string `json:"message_type"`
+	DateFrom    string `json:"date_from"`
+	DateTo      string `json:"date_to"`
+	SortBy      string `json:"sort_by"`
+	Order       string `json:"order"`
 }
 
 type NotificationGetListResponse struct {
@@ -0,0 +1,46 @@
+package models
+
+const (
+	OtpPurposeEmailVerification = "email_verification"
+	OtpPurposePasswordReset     = "password_reset"
+)
+
+type OtpPrimaryKey struct {
+	Id      string `json:"id"`
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+}
+
+type CreateOtp struct {
+	Email      string `json:"email"`
+	Purpose    string `json:"purpose"`
+	CodeHash   string `json:"code_hash"`
+	ExpiresIn  int64  `json:"expires_in"`
+	Cooldown   int64  `json:"cooldown"`
+	MaxPerHour int    `json:"max_per_hour"`
+}
+
+type Otp struct {
+	Id        string `json:"id"`
+	Email     string `json:"email"`
+	Purpose   string `json:"purpose"`
+	CodeHash  string `json:"-"`
+	Attempts  int    `json:"attempts"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type VerifyEmailRequest struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Email       string `json:"email"`
+	Code        string `json:"code"`
+	NewPassword string `json:"new_password"`
+}
@@ -1,7 +1,12 @@
 package models
 
 type PublicationPrimaryKey struct {
-	Id string `json:"id"`
+	Id     string `json:"id"`
+	FileID string `json:"file_id"`
+	// ViewerID is the caller, used to fill LikedByMe. Empty for guests.
+	ViewerID string `json:"-"`
+	// OwnerID, when set, limits Delete to the publications of that user.
+	OwnerID string `json:"-"`
 }
 
 type CreatePublication struct {
@@ -12,10 +17,35 @@ type CreatePublication struct {
 	ImageID       string `json:"image_id"`
 	FileID        string `json:"file_id"`
 	ContributorID string `json:"contributor_id"`
-	Status        string `json:"status"`
+	// Status is the initial status: draft, or pending_review (the
+	// default) to submit right away. Admins may also publish directly.
+	Status string `json:"status"`
+	// ActorID is the caller, recorded as the author of the initial status.
+	ActorID string `json:"-"`
 }
 
 type Publication struct {
+	Id          string `json:"id"`
+	CourseId    string `json:"course_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Tags        string `json:"tags"`
+	ImageID     string `json:"image_id"`
+	// ImageThumbnail is the URL of the small rendition of the cover.
+	ImageThumbnail string `json:"image_thumbnail,omitempty"`
+	FileID         string `json:"file_id"`
+	ContributorID  string `json:"contributor_id"`
+	Status         string `json:"status"`
+	LikeCount      int    `json:"like_count"`
+	LikedByMe      bool   `json:"liked_by_me"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+	// Warnings are non-blocking notes about the request, such as a file
+	// that already backs another publication.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type UpdatePublication struct {
 	Id            string `json:"id"`
 	CourseId      string `json:"course_id"`
 	Title         string `json:"title"`
@@ -24,13 +54,98 @@ type Publication struct {
 	ImageID       string `json:"image_id"`
 	FileID        string `json:"file_id"`
 	ContributorID string `json:"contributor_id"`
+	// Status is ignored, it changes through the moderation endpoints.
+	Status string `json:"status"`
+	// EditorID is the caller, recorded as the author of the revision.
+	EditorID string `json:"-"`
+	// OwnerID, when set, only updates the publication if that user is its
+	// contributor.
+	OwnerID string `json:"-"`
+	// Review sends a published or rejected publication back to review when
+	// its content changes. It is set for edits by contributors.
+	Review bool `json:"-"`
+}
+
+type PublicationGetListRequest struct {
+	Offset        int    `json:"offset"`
+	Limit         int    `json:"limit"`
+	Search        string `json:"search"`
+	CourseID      string `json:"course_id"`
+	SemesterID    string `json:"semester_id"`
+	ContributorID string `json:"contributor_id"`
 	Status        string `json:"status"`
+	DateFrom      string `json:"date_from"`
+	DateTo        string `json:"date_to"`
+	SortBy        string `json:"sort_by"`
+	Order         string `json:"order"`
+	ViewerID      string `json:"-"`
+	// Tags keeps the publications carrying all of the tags, or any of
+	// them when TagMatch is "any".
+	Tags     []string `json:"tags"`
+	TagMatch string   `json:"tag_match"`
+}
+
+// PublicationTagRequest finds the published publications carrying all of
+// Tags, or any of them when Match is "any".
+type PublicationTagRequest struct {
+	Tags  []string `json:"tags"`
+	Match string   `json:"match"`
+}
+
+type PublicationGetListResponse struct {
+	Count        int            `json:"count"`
+	Publications []*Publication `json:"publications"`
+}
+
+// PublicationDuplicateRequest looks for publications whose file has the
+// same content as FileID, optionally within one course.
+type PublicationDuplicateRequest struct {
+	FileID    string `json:"file_id"`
+	CourseID  string `json:"course_id"`
+	ExcludeID string `json:"exclude_id"`
+}
+
+type PublicationDuplicate struct {
+	Id       string `json:"id"`
+	CourseId string `json:"course_id"`
+	Title    string `json:"title"`
+	FileID   string `json:"file_id"`
+}
+
+// PublicationTransitionRequest moves a publication to status To.
+type PublicationTransitionRequest struct {
+	Id      string `json:"id"`
+	To      string `json:"to"`
+	ActorID string `json:"actor_id"`
+	Reason  string `json:"reason"`
+	// OwnerID, when set, only moves the publication if that user is its
+	// contributor.
+	OwnerID string `json:"-"`
+}
+
+// PublicationReview is the body of the approve and reject endpoints.
+type PublicationReview struct {
+	Reason string `json:"reason"`
+}
+
+// PublicationTransition is one status change in the moderation history.
+// FromStatus is empty for the status the publication was created in.
+type PublicationTransition struct {
+	Id            string `json:"id"`
+	PublicationID string `json:"publication_id"`
+	FromStatus    string `json:"from_status"`
+	ToStatus      string `json:"to_status"`
+	ActorID       string `json:"actor_id"`
+	Reason        string `json:"reason"`
 	CreatedAt     string `json:"created_at"`
-	UpdatedAt     string `json:"updated_at"`
 }
 
-type UpdatePublication struct {
+// PublicationRevision is the content of a publication after a change.
+// Revisions are numbered from 1 per publication and never change.
+type PublicationRevision struct {
 	Id            string `json:"id"`
+	PublicationID string `json:"publication_id"`
+	Revision      int    `json:"revision"`
 	CourseId      string `json:"course_id"`
 	Title         string `json:"title"`
 	Description   string `json:"description"`
@@ -38,21 +153,54 @@ type UpdatePublication struct {
 	ImageID       string `json:"image_id"`
 	FileID        string `json:"file_id"`
 	ContributorID string `json:"contributor_id"`
-	Status        string `json:"status"`
+	// ChangedFields names the fields that differ from the previous
+	// revision.
+	ChangedFields []string `json:"changed_fields"`
+	// PreviousFileID is the file this revision replaced, empty when the
+	// file didn't change.
+	PreviousFileID string `json:"previous_file_id,omitempty"`
+	EditorID       string `json:"editor_id"`
+	// RollbackOf is the revision restored by a rollback.
+	RollbackOf int    `json:"rollback_of,omitempty"`
+	CreatedAt  string `json:"created_at"`
 }
 
-type PublicationGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+type PublicationRevisionPrimaryKey struct {
+	PublicationID string `json:"publication_id"`
+	Revision      int    `json:"revision"`
 }
 
-type PublicationGetListResponse struct {
-	Count        int            `json:"count"`
-	Publications []*Publication `json:"publications"`
+// PublicationRevisionDiffRequest compares revision From with revision To.
+// To defaults to the latest revision and From to the one before To, so
+// revision 1 is compared with an empty publication.
+type PublicationRevisionDiffRequest struct {
+	PublicationID string `json:"publication_id"`
+	From          int    `json:"from"`
+	To            int    `json:"to"`
+}
+
+type PublicationRevisionDiff struct {
+	PublicationID string         `json:"publication_id"`
+	From          int            `json:"from"`
+	To            int            `json:"to"`
+	Changes       []*FieldChange `json:"changes"`
+}
+
+// FieldChange is one field that differs between two revisions.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// PublicationRollback restores the content of Revision as a new revision.
+type PublicationRollback struct {
+	Id       string `json:"-"`
+	Revision int    `json:"revision"`
+	EditorID string `json:"-"`
 }
 
 type PublicationStats struct {
-	LikeCount     float64 `json:"like_count"`
-	DownloadCount float64 `json:"download_count"`
+	LikeCount     int `json:"like_count"`
+	DownloadCount int `json:"download_count"`
 }
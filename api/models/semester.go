@@ -21,9 +21,13 @@ type UpdateSemester struct {
 }
 
 type SemesterGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	Search   string `json:"search"`
+	DateFrom string `json:"date_from"`
+	DateTo   string `json:"date_to"`
+	SortBy   string `json:"sort_by"`
+	Order    string `json:"order"`
 }
 
 type SemesterGetListResponse struct {
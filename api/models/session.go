@@ -0,0 +1,39 @@
+package models
+
+type SessionPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateSession struct {
+	UserID           string `json:"user_id"`
+	Role             string `json:"role"`
+	RefreshTokenHash string `json:"refresh_token_hash"`
+	UserAgent        string `json:"user_agent"`
+	IPAddress        string `json:"ip_address"`
+	ExpiresIn        int64  `json:"expires_in"`
+}
+
+type Session struct {
+	Id               string `json:"id"`
+	UserID           string `json:"user_id"`
+	Role             string `json:"role"`
+	RefreshTokenHash string `json:"-"`
+	UserAgent        string `json:"user_agent"`
+	IPAddress        string `json:"ip_address"`
+	ExpiresAt        string `json:"expires_at"`
+	Revoked          bool   `json:"revoked"`
+	Expired          bool   `json:"expired"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+type RotateSession struct {
+	Id                  string `json:"id"`
+	OldRefreshTokenHash string `json:"old_refresh_token_hash"`
+	NewRefreshTokenHash string `json:"new_refresh_token_hash"`
+	ExpiresIn           int64  `json:"expires_in"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
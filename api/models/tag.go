@@ -0,0 +1,48 @@
+package models
+
+// Tag is an entry of the tag catalog. Count is how many published
+// publications carry it.
+type Tag struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	Count     int    `json:"count"`
+	CreatedAt string `json:"created_at"`
+}
+
+// TagGetListRequest lists the tags in use, those starting with Prefix when
+// it is set.
+type TagGetListRequest struct {
+	Prefix string `json:"prefix"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+	SortBy string `json:"sort_by"`
+	Order  string `json:"order"`
+}
+
+type TagGetListResponse struct {
+	Count int    `json:"count"`
+	Tags  []*Tag `json:"tags"`
+}
+
+// RenameTag gives a tag a new name on every publication carrying it.
+type RenameTag struct {
+	Id   string `json:"-"`
+	Name string `json:"name"`
+	// EditorID is the caller, recorded as the author of the revisions.
+	EditorID string `json:"-"`
+}
+
+// MergeTags replaces the Sources tags by Target, created when missing, on
+// every publication carrying them. The source tags are removed.
+type MergeTags struct {
+	Sources  []string `json:"sources"`
+	Target   string   `json:"target"`
+	EditorID string   `json:"-"`
+}
+
+// TagChange reports a rename or merge: the resulting tag and how many
+// publications were rewritten.
+type TagChange struct {
+	Tag          *Tag `json:"tag"`
+	Publications int  `json:"publications"`
+}
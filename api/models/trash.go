@@ -0,0 +1,41 @@
+package models
+
+// TrashGetListRequest lists the trash, of one kind when Kind is set.
+type TrashGetListRequest struct {
+	Kind   string `json:"kind"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+	// Retention is how long, in seconds, deleted rows are kept; it is
+	// used to fill PurgeAt.
+	Retention int64 `json:"-"`
+}
+
+// TrashItem is a deleted row. Label is what identifies it to a person: the
+// semester number, course or publication title, username or message.
+type TrashItem struct {
+	Kind      string `json:"kind"`
+	Id        string `json:"id"`
+	Label     string `json:"label"`
+	DeletedAt string `json:"deleted_at"`
+	// PurgeAt is when the retention job deletes the row for good.
+	PurgeAt string `json:"purge_at"`
+}
+
+type TrashGetListResponse struct {
+	Count int          `json:"count"`
+	Items []*TrashItem `json:"items"`
+}
+
+type TrashPrimaryKey struct {
+	Kind string `json:"kind"`
+	Id   string `json:"id"`
+}
+
+// PurgeReport counts the rows a purge deleted for good.
+type PurgeReport struct {
+	Semesters     int64 `json:"semesters"`
+	Courses       int64 `json:"courses"`
+	Publications  int64 `json:"publications"`
+	Users         int64 `json:"users"`
+	Notifications int64 `json:"notifications"`
+}
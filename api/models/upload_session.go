@@ -0,0 +1,57 @@
+package models
+
+type UploadSessionPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+// CreateUploadSession starts a resumable upload of Size bytes. The session
+// expires ExpiresIn seconds after the last received chunk.
+type CreateUploadSession struct {
+	OwnerID       string `json:"owner_id"`
+	Kind          string `json:"kind"`
+	Filename      string `json:"filename"`
+	Size          int64  `json:"size"`
+	QuotaReserved int64  `json:"-"`
+	ExpiresIn     int64  `json:"-"`
+}
+
+type UploadSession struct {
+	Id            string `json:"id"`
+	OwnerID       string `json:"owner_id"`
+	Kind          string `json:"kind"`
+	Filename      string `json:"filename"`
+	Size          int64  `json:"size"`
+	Offset        int64  `json:"offset"`
+	QuotaReserved int64  `json:"-"`
+	FileID        string `json:"file_id"`
+	CompletedAt   string `json:"completed_at"`
+	ExpiresAt     string `json:"expires_at"`
+	// ExpiresIn is the number of seconds left before the session expires.
+	ExpiresIn int64  `json:"expires_in"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// UploadChunk is a stored part of a resumable upload, starting at Offset.
+type UploadChunk struct {
+	SessionID  string `json:"session_id"`
+	Offset     int64  `json:"offset"`
+	Size       int64  `json:"size"`
+	StorageKey string `json:"storage_key"`
+}
+
+// AppendUploadChunk records a chunk written at Offset, which must still be
+// the offset of the session.
+type AppendUploadChunk struct {
+	SessionID  string
+	Offset     int64
+	Size       int64
+	StorageKey string
+	ExpiresIn  int64
+}
+
+// CompleteUploadSession marks a fully received session as registered as FileID.
+type CompleteUploadSession struct {
+	Id     string
+	FileID string
+}
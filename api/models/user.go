@@ -21,18 +21,19 @@ type CreateUser struct {
 }
 
 type User struct {
-	Id           string `json:"id"`
-	StudentId    string `json:"student_id"`
-	Name         string `json:"name"`
-	Surname      string `json:"surname"`
-	Email        string `json:"email"`
-	Grade        string `json:"grade"`
-	Username     string `json:"username"`
-	Password     string `json:"password"`
-	ProfileImage string `json:"profile_image"`
-	Status       bool   `json:"status"`
-	CreatedAt    string `json:"created_at"`
-	UpdatedAt    string `json:"updated_at"`
+	Id            string `json:"id"`
+	StudentId     string `json:"student_id"`
+	Name          string `json:"name"`
+	Surname       string `json:"surname"`
+	Email         string `json:"email"`
+	Grade         string `json:"grade"`
+	Username      string `json:"username"`
+	Password      string `json:"-"`
+	ProfileImage  string `json:"profile_image"`
+	Status        bool   `json:"status"`
+	EmailVerified bool   `json:"email_verified"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
 }
 
 type UpdateUser struct {
@@ -49,9 +50,14 @@ type UpdateUser struct {
 }
 
 type UserGetListRequest struct {
-	Offset int    `json:"offset"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	Search   string `json:"search"`
+	Status   string `json:"status"`
+	DateFrom string `json:"date_from"`
+	DateTo   string `json:"date_to"`
+	SortBy   string `json:"sort_by"`
+	Order    string `json:"order"`
 }
 
 type UserGetListResponse struct {
@@ -60,9 +66,9 @@ type UserGetListResponse struct {
 }
 
 type UserActivityCounts struct {
-	PublicationCount int     `json:"publication_count"`
-	DownloadCount    float64 `json:"download_count"`
-	LikeCount        float64 `json:"like_count"`
+	PublicationCount int `json:"publication_count"`
+	DownloadCount    int `json:"download_count"`
+	LikeCount        int `json:"like_count"`
 }
 type UserpublicationCounts struct {
 	UserID           string `json:"user_id"`
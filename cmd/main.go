@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
 	"app/api"
 	"app/config"
+	"app/jobs"
+	"app/pkg/blobstore"
 	"app/pkg/logger"
+	"app/pkg/mailer"
+	"app/storage"
 	"app/storage/postgres"
 )
 
@@ -38,17 +46,86 @@ func main() {
 		}
 	}()
 
+	// go run cmd/main.go migrate up | down [steps] | status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(&cfg, log, os.Args[2:])
+		if err != nil {
+			log.Fatal("migrate", logger.Error(err))
+		}
+		return
+	}
+
+	if cfg.MigrateOnStart {
+		err := runMigrate(&cfg, log, []string{"up"})
+		if err != nil {
+			panic("postgres migrations: " + err.Error())
+		}
+	}
+
 	pgconn, err := postgres.NewConnectionPostgres(&cfg)
 	if err != nil {
 		panic("postgres no connection: " + err.Error())
 	}
 	defer pgconn.Close()
 
+	var mail mailer.Mailer
+	switch cfg.MailDriver {
+	case mailer.DriverSMTP:
+		mail = mailer.NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	case mailer.DriverFile:
+		mail = mailer.NewFile(cfg.MailDir, cfg.MailFrom, log)
+	default:
+		panic(mailer.ErrUnknownDriver.Error() + ": " + cfg.MailDriver)
+	}
+
+	var blobs blobstore.Store
+	switch cfg.BlobDriver {
+	case blobstore.DriverLocal:
+		blobs = blobstore.NewLocal(cfg.BlobLocalDir)
+	case blobstore.DriverS3:
+		blobs, err = blobstore.NewS3(blobstore.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+		if err != nil {
+			panic(err)
+		}
+	default:
+		panic(blobstore.ErrUnknownDriver.Error() + ": " + cfg.BlobDriver)
+	}
+
+	gcOptions := jobs.GCOptions{
+		GracePeriod: cfg.GCGracePeriod,
+		BatchSize:   cfg.GCBatchSize,
+		DryRun:      cfg.GCDryRun,
+	}
+
+	// go run cmd/main.go gc [-dry-run]
+	if len(os.Args) > 1 && os.Args[1] == "gc" {
+		err := runGC(pgconn, blobs, log, gcOptions, os.Args[2:])
+		if err != nil {
+			log.Fatal("gc", logger.Error(err))
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := jobs.NewRunner(log)
+	runner.Add(jobs.ExpireUploads(pgconn, blobs, log, cfg.UploadExpiryInterval))
+	runner.Add(jobs.SweepOrphans(pgconn, blobs, log, cfg.GCInterval, gcOptions))
+	runner.Add(jobs.PurgeTrash(pgconn, log, cfg.TrashPurgeInterval, cfg.TrashRetention))
+	runner.Start(ctx)
+
 	r := gin.New()
 
 	r.Use(gin.Recovery(), gin.Logger())
 
-	api.NewApi(r, &cfg, pgconn, log)
+	api.NewApi(r, &cfg, pgconn, log, mail, blobs)
 
 	fmt.Println("Listening server", cfg.ServerHost+cfg.HTTPPort)
 	err = r.Run(cfg.ServerHost + cfg.HTTPPort)
@@ -56,3 +133,82 @@ func main() {
 		panic(err)
 	}
 }
+
+func runMigrate(cfg *config.Config, log logger.LoggerI, args []string) error {
+	ctx := context.Background()
+
+	migrator, err := postgres.NewMigrator(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close(ctx)
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			log.Info("migration applied", logger.Any("version", migration.Version), logger.String("name", migration.Name))
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			log.Info("migration reverted", logger.Any("version", migration.Version), logger.String("name", migration.Name))
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			fmt.Printf("%06d %-40s applied=%t %s\n", status.Version, status.Name, status.Applied, status.AppliedAt)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", command)
+	}
+}
+
+func runGC(strg storage.StorageI, blobs blobstore.Store, log logger.LoggerI, opts jobs.GCOptions, args []string) error {
+
+	flags := flag.NewFlagSet("gc", flag.ContinueOnError)
+	flags.BoolVar(&opts.DryRun, "dry-run", opts.DryRun, "only report what would be deleted")
+	flags.DurationVar(&opts.GracePeriod, "grace", opts.GracePeriod, "keep anything younger than this")
+	flags.IntVar(&opts.BatchSize, "batch", opts.BatchSize, "rows handled per batch")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := jobs.CollectGarbage(context.Background(), strg, blobs, log, opts)
+	if report != nil {
+		for _, file := range report.Files {
+			fmt.Printf("file  %s %s %d\n", file.Id, file.StorageKey, file.Size)
+		}
+		for _, blob := range report.Blobs {
+			fmt.Printf("blob  %s %s %d\n", blob.Sha256, blob.StorageKey, blob.Size)
+		}
+		for _, key := range report.StrayKeys {
+			fmt.Printf("stray %s\n", key)
+		}
+		fmt.Printf("dry_run=%t files=%d blobs=%d stray_keys=%d freed_bytes=%d\n",
+			report.DryRun, len(report.Files), len(report.Blobs), len(report.StrayKeys), report.FreedBytes)
+	}
+
+	return err
+}
@@ -0,0 +1,197 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/api/models"
+	"app/pkg/blobstore"
+	"app/pkg/logger"
+	"app/storage"
+)
+
+// defaultGCBatch is used when GCOptions.BatchSize is not set.
+const defaultGCBatch = 100
+
+// GCOptions configures a garbage collection run.
+type GCOptions struct {
+	// GracePeriod keeps recent files and objects, so uploads that are not
+	// attached to a publication yet and content being written survive.
+	GracePeriod time.Duration
+	// BatchSize is how many rows one transaction or lookup handles.
+	BatchSize int
+	// DryRun only reports what would be deleted.
+	DryRun bool
+}
+
+// CollectGarbage deletes, in order, the files nothing refers to, the blobs
+// no file refers to anymore and the objects in the store the database
+// doesn't know. Anything younger than the grace period is kept. Work is
+// done in batches so no transaction holds many locks, and a failing item
+// doesn't stop the run: its error is returned with the report of what
+// was done. A dry run deletes nothing; it can't foresee the blobs that
+// deleting the reported files would orphan.
+func CollectGarbage(ctx context.Context, strg storage.StorageI, blobs blobstore.Store, log logger.LoggerI, opts GCOptions) (*models.GarbageReport, error) {
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultGCBatch
+	}
+
+	var (
+		report    = &models.GarbageReport{DryRun: opts.DryRun}
+		olderThan = int64(opts.GracePeriod.Seconds())
+		errs      []error
+	)
+
+	err := collectFiles(ctx, strg, report, olderThan, opts)
+	if err != nil {
+		return report, err
+	}
+
+	errs = append(errs, collectBlobs(ctx, strg, blobs, report, olderThan, opts))
+	errs = append(errs, collectStrayKeys(ctx, strg, blobs, report, opts))
+
+	log.Info("garbage collected",
+		logger.Bool("dry_run", opts.DryRun),
+		logger.Int("files", len(report.Files)),
+		logger.Int("blobs", len(report.Blobs)),
+		logger.Int("stray_keys", len(report.StrayKeys)),
+		logger.Any("freed_bytes", report.FreedBytes),
+	)
+
+	return report, errors.Join(errs...)
+}
+
+func collectFiles(ctx context.Context, strg storage.StorageI, report *models.GarbageReport, olderThan int64, opts GCOptions) error {
+
+	for {
+		var (
+			req   = &models.OrphanRequest{OlderThan: olderThan, Limit: opts.BatchSize}
+			files []*models.File
+			err   error
+		)
+
+		if opts.DryRun {
+			req.Offset = len(report.Files)
+			files, err = strg.File().GetOrphans(ctx, req)
+		} else {
+			files, err = strg.File().DeleteOrphans(ctx, req)
+		}
+		if err != nil {
+			return err
+		}
+
+		report.Files = append(report.Files, files...)
+
+		if len(files) < opts.BatchSize {
+			return nil
+		}
+	}
+}
+
+func collectBlobs(ctx context.Context, strg storage.StorageI, blobs blobstore.Store, report *models.GarbageReport, olderThan int64, opts GCOptions) error {
+
+	var (
+		errs []error
+		// kept counts the listed blobs that are still there, they are
+		// skipped when fetching the next batch
+		kept int
+	)
+
+	for {
+		orphans, err := strg.Blob().GetOrphans(ctx, &models.OrphanRequest{
+			OlderThan: olderThan,
+			Offset:    kept,
+			Limit:     opts.BatchSize,
+		})
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		for _, blob := range orphans {
+			if opts.DryRun {
+				kept++
+				report.Blobs = append(report.Blobs, blob)
+				report.FreedBytes += blob.Size
+				continue
+			}
+
+			deleted, err := strg.Blob().DeleteOrphan(ctx, &models.BlobPrimaryKey{Sha256: blob.Sha256}, olderThan, func(blob *models.Blob) error {
+				return blobs.Delete(ctx, blob.StorageKey)
+			})
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if !deleted {
+				kept++
+				continue
+			}
+
+			report.Blobs = append(report.Blobs, blob)
+			report.FreedBytes += blob.Size
+		}
+
+		if len(orphans) < opts.BatchSize {
+			return errors.Join(errs...)
+		}
+	}
+}
+
+func collectStrayKeys(ctx context.Context, strg storage.StorageI, blobs blobstore.Store, report *models.GarbageReport, opts GCOptions) error {
+
+	objects, err := blobs.List(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var (
+		errs   []error
+		cutoff = time.Now().Add(-opts.GracePeriod)
+		sizes  = map[string]int64{}
+		keys   []string
+	)
+
+	for _, object := range objects {
+		if object.ModTime.Before(cutoff) {
+			sizes[object.Key] = object.Size
+			keys = append(keys, object.Key)
+		}
+	}
+
+	for start := 0; start < len(keys); start += opts.BatchSize {
+		batch := keys[start:min(start+opts.BatchSize, len(keys))]
+
+		unknown, err := strg.Blob().GetUnknownKeys(ctx, batch)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, key := range unknown {
+			if !opts.DryRun {
+				if err := blobs.Delete(ctx, key); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
+
+			report.StrayKeys = append(report.StrayKeys, key)
+			report.FreedBytes += sizes[key]
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SweepOrphans returns the job collecting garbage every interval.
+func SweepOrphans(strg storage.StorageI, blobs blobstore.Store, log logger.LoggerI, interval time.Duration, opts GCOptions) Job {
+	return Job{
+		Name:     "collect_garbage",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			_, err := CollectGarbage(ctx, strg, blobs, log, opts)
+			return err
+		},
+	}
+}
@@ -0,0 +1,82 @@
+// Package jobs runs the periodic maintenance tasks of the API, such as
+// expiring abandoned uploads. Each task is also callable on its own so
+// handlers and CLI commands can trigger it.
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"app/pkg/logger"
+)
+
+// Job is a task run every Interval. A job that returns an error is logged
+// and tried again on the next tick.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Runner struct {
+	log  logger.LoggerI
+	jobs []Job
+}
+
+func NewRunner(log logger.LoggerI) *Runner {
+	return &Runner{
+		log: log,
+	}
+}
+
+// Add registers a job. Jobs with a non positive interval are disabled.
+func (r *Runner) Add(job Job) {
+	if job.Interval <= 0 {
+		r.log.Info("job disabled", logger.String("job", job.Name))
+		return
+	}
+
+	r.jobs = append(r.jobs, job)
+}
+
+// Start runs every job in its own goroutine until ctx is done. The first run
+// happens one interval after start.
+func (r *Runner) Start(ctx context.Context) {
+	for _, job := range r.jobs {
+		go r.loop(ctx, job)
+	}
+}
+
+func (r *Runner) loop(ctx context.Context, job Job) {
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.runOnce(ctx, job)
+		}
+	}
+}
+
+func (r *Runner) runOnce(ctx context.Context, job Job) {
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			r.log.Error("job panicked", logger.String("job", job.Name), logger.Any("panic", recovered))
+		}
+	}()
+
+	started := time.Now()
+
+	err := job.Run(ctx)
+	if err != nil {
+		r.log.Error("job failed", logger.String("job", job.Name), logger.Error(err))
+		return
+	}
+
+	r.log.Debug("job done", logger.String("job", job.Name), logger.Any("took", time.Since(started)))
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"app/api/models"
+	"app/pkg/logger"
+	"app/storage"
+)
+
+// EmptyTrash deletes for good what has been in the trash for longer than
+// retention. Purged publications leave their files behind, the garbage
+// collection removes them.
+func EmptyTrash(ctx context.Context, strg storage.StorageI, log logger.LoggerI, retention time.Duration) (*models.PurgeReport, error) {
+
+	report, err := strg.Trash().Purge(ctx, int64(retention.Seconds()))
+	if err != nil {
+		return report, err
+	}
+
+	log.Info("trash emptied",
+		logger.Any("semesters", report.Semesters),
+		logger.Any("courses", report.Courses),
+		logger.Any("publications", report.Publications),
+		logger.Any("users", report.Users),
+		logger.Any("notifications", report.Notifications),
+	)
+
+	return report, nil
+}
+
+// PurgeTrash returns the job emptying the trash every interval.
+func PurgeTrash(strg storage.StorageI, log logger.LoggerI, interval, retention time.Duration) Job {
+	return Job{
+		Name:     "purge_trash",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			_, err := EmptyTrash(ctx, strg, log, retention)
+			return err
+		},
+	}
+}
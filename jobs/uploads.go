@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/api/models"
+	"app/pkg/blobstore"
+	"app/pkg/logger"
+	"app/storage"
+)
+
+// expireBatch is how many expired upload sessions one run handles.
+const expireBatch = 100
+
+// DiscardUploadSession removes an upload session. For an unfinished upload
+// the received chunks are deleted and the reserved quota is given back;
+// a finished upload already became a file, so only the session goes.
+func DiscardUploadSession(ctx context.Context, strg storage.StorageI, blobs blobstore.Store, session *models.UploadSession) error {
+
+	key := &models.UploadSessionPrimaryKey{Id: session.Id}
+
+	chunks, err := strg.UploadSession().GetChunks(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		err = blobs.Delete(ctx, chunk.StorageKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(session.CompletedAt) <= 0 && session.QuotaReserved > 0 {
+		err = strg.User().ReleaseStorage(ctx, session.OwnerID, session.QuotaReserved)
+		if err != nil {
+			return err
+		}
+	}
+
+	return strg.UploadSession().Delete(ctx, key)
+}
+
+// ExpireUploads returns the job discarding upload sessions whose expiry
+// passed: abandoned uploads and finished ones nobody asks about anymore.
+func ExpireUploads(strg storage.StorageI, blobs blobstore.Store, log logger.LoggerI, interval time.Duration) Job {
+	return Job{
+		Name:     "expire_uploads",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+
+			sessions, err := strg.UploadSession().GetExpired(ctx, expireBatch)
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, session := range sessions {
+				err = DiscardUploadSession(ctx, strg, blobs, session)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+
+				log.Info("upload session expired", logger.String("id", session.Id), logger.Bool("completed", len(session.CompletedAt) > 0))
+			}
+
+			return errors.Join(errs...)
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrUnknownDriver = errors.New("blobstore: unknown driver")
+	ErrNotFound      = errors.New("blobstore: object not found")
+	ErrInvalidKey    = errors.New("blobstore: invalid key")
+)
+
+// Object describes a stored blob.
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Store keeps blobs under slash separated keys such as
+// "profile_images/<id>.png". Implementations must be safe for concurrent use.
+type Store interface {
+	// Put streams r into key, replacing any existing blob. size may be -1
+	// when unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob for reading. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	// GetRange opens length bytes of the blob starting at offset, up to the
+	// end when length is negative. Object.Size is the size of the whole blob.
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, *Object, error)
+	Stat(ctx context.Context, key string) (*Object, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns the blobs whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]*Object, error)
+}
+
+// ValidateKey rejects empty keys, absolute keys and keys with "." or ".."
+// segments, so a key can never point outside of the store.
+func ValidateKey(key string) error {
+	if len(key) <= 0 || strings.HasPrefix(key, "/") || strings.ContainsAny(key, "\\\x00") {
+		return ErrInvalidKey
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+
+	return nil
+}
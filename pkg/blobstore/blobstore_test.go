@@ -0,0 +1,36 @@
+package blobstore
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+
+	tests := []struct {
+		key string
+		err error
+	}{
+		{"profile_images/3f2a.png", nil},
+		{"a", nil},
+		{"a/b/c.pdf", nil},
+		{"name..with..dots", nil},
+		{"", ErrInvalidKey},
+		{"/etc/passwd", ErrInvalidKey},
+		{"..", ErrInvalidKey},
+		{"../x", ErrInvalidKey},
+		{"a/../../x", ErrInvalidKey},
+		{"a/./b", ErrInvalidKey},
+		{"a//b", ErrInvalidKey},
+		{"a/", ErrInvalidKey},
+		{"a\\b", ErrInvalidKey},
+		{"..\\x", ErrInvalidKey},
+		{"a\x00.png", ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateKey(tt.key); !errors.Is(err, tt.err) {
+			t.Errorf("ValidateKey(%q) = %v, want %v", tt.key, err, tt.err)
+		}
+	}
+}
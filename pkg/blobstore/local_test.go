@@ -0,0 +1,149 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func readAll(t *testing.T, r io.ReadCloser) string {
+	t.Helper()
+	defer r.Close()
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestLocalStore(t *testing.T) {
+
+	var (
+		ctx   = context.Background()
+		dir   = t.TempDir()
+		store = NewLocal(dir)
+	)
+
+	err := store.Put(ctx, "files/a.pdf", strings.NewReader("0123456789"), -1, "application/pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "files", "a.pdf")); err != nil {
+		t.Fatalf("blob not stored under the key path: %v", err)
+	}
+
+	reader, object, err := store.Get(ctx, "files/a.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := readAll(t, reader); body != "0123456789" {
+		t.Errorf("Get body = %q", body)
+	}
+	if object.Key != "files/a.pdf" || object.Size != 10 || object.ContentType != "application/pdf" {
+		t.Errorf("Get object = %+v", object)
+	}
+
+	ranges := []struct {
+		offset, length int64
+		want           string
+	}{
+		{0, 4, "0123"},
+		{3, 2, "34"},
+		{6, -1, "6789"},
+		{8, 100, "89"},
+	}
+	for _, tt := range ranges {
+		reader, object, err := store.GetRange(ctx, "files/a.pdf", tt.offset, tt.length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body := readAll(t, reader); body != tt.want {
+			t.Errorf("GetRange(%d, %d) = %q, want %q", tt.offset, tt.length, body, tt.want)
+		}
+		if object.Size != 10 {
+			t.Errorf("GetRange(%d, %d) size = %d, want the whole blob", tt.offset, tt.length, object.Size)
+		}
+	}
+
+	// Put replaces the blob
+	err = store.Put(ctx, "files/a.pdf", strings.NewReader("new"), 3, "application/pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	object, err = store.Stat(ctx, "files/a.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object.Size != 3 {
+		t.Errorf("Stat size after replace = %d, want 3", object.Size)
+	}
+
+	for _, key := range []string{"files/b.png", "images/c.png"} {
+		if err := store.Put(ctx, key, strings.NewReader(key), -1, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects, err := store.List(ctx, "files/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != "files/a.pdf,files/b.png" {
+		t.Errorf("List(files/) = %v", keys)
+	}
+
+	if err := store.Delete(ctx, "files/a.pdf"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Stat(ctx, "files/a.pdf"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat after Delete = %v, want ErrNotFound", err)
+	}
+	if _, _, err := store.Get(ctx, "files/a.pdf"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "files/a.pdf"); err != nil {
+		t.Errorf("Delete of a missing blob = %v, want nil", err)
+	}
+
+	// A directory is not a blob
+	if _, err := store.Stat(ctx, "files"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat of a directory = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStoreRejectsInvalidKeys(t *testing.T) {
+
+	var (
+		ctx    = context.Background()
+		parent = t.TempDir()
+		store  = NewLocal(filepath.Join(parent, "store"))
+	)
+
+	for _, key := range []string{"../outside", "/abs", "a\\b", "a\x00b"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) = %v, want ErrInvalidKey", key, err)
+		}
+		if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Get(%q) = %v, want ErrInvalidKey", key, err)
+		}
+		if err := store.Delete(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Delete(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(parent, "outside")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a file was written outside of the store: %v", err)
+	}
+}
@@ -0,0 +1,443 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config points at an S3 compatible endpoint such as AWS S3 or MinIO.
+type S3Config struct {
+	// Endpoint is the base URL, e.g. "http://localhost:9000".
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+type s3Store struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewS3 returns a Store backed by an S3 compatible bucket. Requests use
+// path-style URLs ({endpoint}/{bucket}/{key}) signed with AWS Signature V4,
+// which every S3 compatible server accepts.
+func NewS3(cfg S3Config) (Store, error) {
+
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("blobstore: s3 endpoint must start with http:// or https://")
+	}
+
+	if len(cfg.Bucket) <= 0 {
+		return nil, fmt.Errorf("blobstore: s3 bucket is required")
+	}
+
+	if len(cfg.Region) <= 0 {
+		cfg.Region = "us-east-1"
+	}
+
+	return &s3Store{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{},
+	}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	// S3 needs the length up front, a stream of unknown size is buffered
+	if size < 0 {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		r, size = bytes.NewReader(body), int64(len(body))
+	}
+
+	header := http.Header{}
+	if len(contentType) > 0 {
+		header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, nil, header, r, size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+
+	if err := ValidateKey(key); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, nil, s3Error(resp)
+	}
+
+	return resp.Body, s3Object(key, resp), nil
+}
+
+func (s *s3Store) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, *Object, error) {
+
+	if err := ValidateKey(key); err != nil {
+		return nil, nil, err
+	}
+
+	byteRange := fmt.Sprintf("bytes=%d-", offset)
+	if length >= 0 {
+		byteRange += strconv.FormatInt(offset+length-1, 10)
+	}
+
+	header := http.Header{}
+	header.Set("Range", byteRange)
+
+	resp, err := s.do(ctx, http.MethodGet, key, nil, header, nil, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		object := s3Object(key, resp)
+
+		// Content-Range is "bytes <first>-<last>/<size>"
+		if _, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/"); ok {
+			object.Size, _ = strconv.ParseInt(total, 10, 64)
+		}
+
+		return resp.Body, object, nil
+	case http.StatusOK:
+		// The whole blob came back, skip to the offset
+		object := s3Object(key, resp)
+
+		_, err = io.CopyN(io.Discard, resp.Body, offset)
+		if err != nil {
+			resp.Body.Close()
+			return nil, nil, err
+		}
+
+		if length < 0 {
+			return resp.Body, object, nil
+		}
+
+		return struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(resp.Body, length), resp.Body}, object, nil
+	default:
+		defer resp.Body.Close()
+		return nil, nil, s3Error(resp)
+	}
+}
+
+func (s *s3Store) Stat(ctx context.Context, key string) (*Object, error) {
+
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodHead, key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, s3Error(resp)
+	}
+
+	return s3Object(key, resp), nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error(resp)
+	}
+
+	return nil
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key          string `xml:"Key"`
+		Size         int64  `xml:"Size"`
+		LastModified string `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *s3Store) List(ctx context.Context, prefix string) ([]*Object, error) {
+
+	var (
+		objects []*Object
+		token   string
+	)
+
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if len(token) > 0 {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err = s3Error(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, content := range result.Contents {
+			modTime, _ := time.Parse(time.RFC3339, content.LastModified)
+			objects = append(objects, &Object{
+				Key:     content.Key,
+				Size:    content.Size,
+				ModTime: modTime,
+			})
+		}
+
+		if !result.IsTruncated || len(result.NextContinuationToken) <= 0 {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a signed request for key in the bucket, or for the bucket itself
+// when key is empty.
+func (s *s3Store) do(ctx context.Context, method, key string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+
+	target := *s.endpoint
+	target.Path = s.endpoint.Path + "/" + s.cfg.Bucket
+	if len(key) > 0 {
+		target.Path += "/" + key
+	}
+	// Send the path encoded exactly the way it is signed
+	target.RawPath = uriEncode(target.Path, false)
+	target.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	if body != nil {
+		req.ContentLength = size
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+	}
+
+	s.sign(req, time.Now().UTC())
+
+	return s.client.Do(req)
+}
+
+// sign adds AWS Signature V4 headers to the request. Unless the caller set
+// x-amz-content-sha256, the payload is not hashed (UNSIGNED-PAYLOAD) so
+// uploads can be streamed.
+func (s *s3Store) sign(req *http.Request, now time.Time) {
+
+	var (
+		amzDate     = now.Format("20060102T150405Z")
+		date        = now.Format("20060102")
+		scope       = date + "/" + s.cfg.Region + "/s3/aws4_request"
+		payloadHash = req.Header.Get("x-amz-content-sha256")
+	)
+
+	if len(payloadHash) <= 0 {
+		payloadHash = "UNSIGNED-PAYLOAD"
+	}
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	canonical, signedHeaders := canonicalRequest(req, payloadHash)
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonical)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+// canonicalRequest builds the SigV4 canonical request of req, signing the
+// host and every header already set, and returns it with the list of
+// signed header names.
+func canonicalRequest(req *http.Request, payloadHash string) (string, string) {
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	return strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+// canonicalQuery encodes the query sorted by key as SigV4 expects. The same
+// string is sent, so the signed and the actual query always match.
+func canonicalQuery(query url.Values) string {
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything except the unreserved characters,
+// and "/" too when encodeSlash is set.
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~':
+			b.WriteByte(ch)
+		case ch == '/' && !encodeSlash:
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func s3Object(key string, resp *http.Response) *Object {
+	size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+
+	return &Object{
+		Key:         key,
+		Size:        size,
+		ContentType: resp.Header.Get("Content-Type"),
+		ModTime:     modTime,
+	}
+}
+
+func s3Error(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("blobstore: s3 %s %s: %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,85 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// readSeeker reads a blob through GetRange, opening it again at the new
+// offset after every Seek, so only the bytes actually read are fetched.
+type readSeeker struct {
+	ctx    context.Context
+	store  Store
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+// NewReadSeeker returns a seekable reader over the blob of the given size.
+// It lets http.ServeContent answer range requests from any backend. The
+// caller must close it.
+func NewReadSeeker(ctx context.Context, store Store, key string, size int64) io.ReadSeekCloser {
+	return &readSeeker{
+		ctx:   ctx,
+		store: store,
+		key:   key,
+		size:  size,
+	}
+}
+
+func (r *readSeeker) Read(p []byte) (int, error) {
+
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.body == nil {
+		body, _, err := r.store.GetRange(r.ctx, r.key, r.offset, -1)
+		if err != nil {
+			return 0, err
+		}
+		r.body = body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+
+	return n, err
+}
+
+func (r *readSeeker) Seek(offset int64, whence int) (int64, error) {
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("blobstore: invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("blobstore: negative position")
+	}
+
+	if offset != r.offset {
+		r.Close()
+		r.offset = offset
+	}
+
+	return offset, nil
+}
+
+func (r *readSeeker) Close() error {
+	if r.body == nil {
+		return nil
+	}
+
+	err := r.body.Close()
+	r.body = nil
+
+	return err
+}
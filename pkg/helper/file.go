@@ -0,0 +1,122 @@
+package helper
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// storedFileName matches the names given to uploads: a UUID and an
+// optional short lowercase extension. Anything else, including paths, is
+// rejected before it reaches the store.
+var (
+	storedFileName = regexp.MustCompile(`^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}(\.[a-zA-Z0-9]{1,10})?$`)
+	safeExtension  = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+)
+
+// IsValidStoredFileName reports whether name is a file ID generated on upload.
+func IsValidStoredFileName(name string) bool {
+	return storedFileName.MatchString(name)
+}
+
+// SafeExtension returns the lowercased extension of a client supplied file
+// name, or "" when it isn't a short alphanumeric one.
+func SafeExtension(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if !safeExtension.MatchString(ext) {
+		return ""
+	}
+	return ext
+}
+
+// containerTypes refines what content sniffing reports for formats that
+// are zip or OLE containers, using the extension.
+var containerTypes = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".doc":  "application/msword",
+	".xls":  "application/vnd.ms-excel",
+	".ppt":  "application/vnd.ms-powerpoint",
+}
+
+// isQuickTime reports whether head starts with the ftyp box of a QuickTime
+// movie, which http.DetectContentType doesn't know: its major brand is
+// "qt  " where MP4 files have an "mp4" one.
+func isQuickTime(head []byte) bool {
+	return len(head) >= 12 && string(head[4:8]) == "ftyp" && string(head[8:12]) == "qt  "
+}
+
+// DetectContentType returns the MIME type of a file from its first bytes
+// (up to 512). The extension is only used to name zip or OLE based office
+// formats, never to trust a type the content doesn't back.
+func DetectContentType(head []byte, filename string) string {
+	detected := http.DetectContentType(head)
+
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		return "application/octet-stream"
+	}
+
+	if mediaType == "application/octet-stream" && isQuickTime(head) {
+		return "video/quicktime"
+	}
+
+	if mediaType == "application/zip" || mediaType == "application/octet-stream" {
+		if refined, ok := containerTypes[strings.ToLower(filepath.Ext(filename))]; ok {
+			return refined
+		}
+	}
+
+	return detected
+}
+
+// IsInlineContentType reports whether a file of this type is safe to show
+// in the browser. Everything else, HTML and XML in particular, is only
+// served as an attachment.
+func IsInlineContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp", "image/bmp", "application/pdf":
+		return true
+	}
+
+	return false
+}
+
+// ContentDisposition builds a Content-Disposition header value with an
+// ASCII fallback name and the RFC 5987 encoded UTF-8 name.
+func ContentDisposition(disposition, filename string) string {
+	var fallback strings.Builder
+	for _, r := range filename {
+		switch {
+		case r == '"' || r == '\\' || r == '/' || r < 0x20 || r == 0x7f:
+			fallback.WriteByte('_')
+		case r < utf8.RuneSelf:
+			fallback.WriteRune(r)
+		default:
+			fallback.WriteByte('_')
+		}
+	}
+
+	var encoded strings.Builder
+	for _, b := range []byte(filename) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			strings.IndexByte("!#$&+-.^_`|~", b) >= 0:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, fallback.String(), encoded.String())
+}
@@ -0,0 +1,129 @@
+package helper
+
+import "testing"
+
+func TestIsValidStoredFileName(t *testing.T) {
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"0b7d6a52-3b8e-4c55-9a0e-6f1f2d9c4e11", true},
+		{"0b7d6a52-3b8e-4c55-9a0e-6f1f2d9c4e11.pdf", true},
+		{"0B7D6A52-3B8E-4C55-9A0E-6F1F2D9C4E11.JPG", true},
+		{"", false},
+		{"../x", false},
+		{"a/b", false},
+		{"%2e%2e", false},
+		{"%2e%2e/0b7d6a52-3b8e-4c55-9a0e-6f1f2d9c4e11", false},
+		{"../0b7d6a52-3b8e-4c55-9a0e-6f1f2d9c4e11.pdf", false},
+		{"0b7d6a52-3b8e-4c55-9a0e-6f1f2d9c4e11/../x", false},
+		{"0b7d6a52-3b8e-4c55-9a0e-6f1f2d9c4e11.tar.gz", false},
+		{"0b7d6a52-3b8e-4c55-9a0e-6f1f2d9c4e11.", false},
+		{"0b7d6a52-3b8e-4c55-9a0e-6f1f2d9c4e11.pdf\x00.png", false},
+		{"report.pdf", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidStoredFileName(tt.name); got != tt.want {
+			t.Errorf("IsValidStoredFileName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSafeExtension(t *testing.T) {
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.PDF", ".pdf"},
+		{"photo.jpeg", ".jpeg"},
+		{"archive.tar.gz", ".gz"},
+		{"noext", ""},
+		{"bad.p/f", ""},
+		{"weird.ext%00", ""},
+		{"long.abcdefghijk", ""},
+	}
+
+	for _, tt := range tests {
+		if got := SafeExtension(tt.name); got != tt.want {
+			t.Errorf("SafeExtension(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+
+	zip := []byte("PK\x03\x04\x14\x00\x06\x00")
+
+	tests := []struct {
+		name     string
+		head     []byte
+		filename string
+		want     string
+	}{
+		{"pdf", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), "notes.pdf", "application/pdf"},
+		{"pdf under another name", []byte("%PDF-1.4\n"), "notes.png", "application/pdf"},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "cover.png", "image/png"},
+		{"html named pdf", []byte("<!DOCTYPE html><html><script>alert(1)</script>"), "notes.pdf", "text/html; charset=utf-8"},
+		{"docx", zip, "notes.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"zip named pdf", zip, "notes.pdf", "application/zip"},
+		{"quicktime", []byte("\x00\x00\x00\x14ftypqt  \x00\x00\x02\x00"), "clip.mov", "video/quicktime"},
+		{"mp4", []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), "clip.mp4", "video/mp4"},
+		{"unknown bytes", []byte{0x00, 0x01, 0x02, 0x03}, "data.bin", "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectContentType(tt.head, tt.filename); got != tt.want {
+				t.Errorf("DetectContentType(%q) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsInlineContentType(t *testing.T) {
+
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"application/pdf", true},
+		{"image/png", true},
+		{"image/jpeg", true},
+		{"text/html; charset=utf-8", false},
+		{"text/html", false},
+		{"image/svg+xml", false},
+		{"text/xml; charset=utf-8", false},
+		{"application/octet-stream", false},
+		{"", false},
+		{"not a type", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsInlineContentType(tt.contentType); got != tt.want {
+			t.Errorf("IsInlineContentType(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestContentDisposition(t *testing.T) {
+
+	tests := []struct {
+		disposition string
+		filename    string
+		want        string
+	}{
+		{"inline", "notes.pdf", `inline; filename="notes.pdf"; filename*=UTF-8''notes.pdf`},
+		{"attachment", "Лекция 1.pdf", `attachment; filename="______ 1.pdf"; filename*=UTF-8''%D0%9B%D0%B5%D0%BA%D1%86%D0%B8%D1%8F%201.pdf`},
+		{"attachment", "café.txt", `attachment; filename="caf_.txt"; filename*=UTF-8''caf%C3%A9.txt`},
+		{"attachment", "a\"b\\c/d\r\n.txt", `attachment; filename="a_b_c_d__.txt"; filename*=UTF-8''a%22b%5Cc%2Fd%0D%0A.txt`},
+	}
+
+	for _, tt := range tests {
+		if got := ContentDisposition(tt.disposition, tt.filename); got != tt.want {
+			t.Errorf("ContentDisposition(%q, %q) =\n%s\nwant\n%s", tt.disposition, tt.filename, got, tt.want)
+		}
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"sort"
 	"strconv"
 	"strings"
 )
@@ -162,9 +161,3 @@ func StructAsMap(input interface{}) map[string]interface{} {
 
 	return result
 }
-func SortAndJoinTags(tags []string) string {
-	// Sort tags alphabetically
-	sort.Strings(tags)
-	// Join with commas (e.g., "golang,backend,api")
-	return strings.Join(tags, ",")
-}
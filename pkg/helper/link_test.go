@@ -0,0 +1,74 @@
+package helper
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestVerifyDownloadLink(t *testing.T) {
+
+	const (
+		secret = "link-secret"
+		fileID = "0b7d6a52-3b8e-4c55-9a0e-6f1f2d9c4e11"
+		userID = "5c1f0e3a-7d2b-4f6e-8a9c-2b4d6e8f0a13"
+	)
+
+	var (
+		now     = time.Unix(1700000000, 0)
+		expires = now.Add(time.Hour)
+	)
+
+	// with returns a copy of query with key set to value, or removed when
+	// value is empty
+	with := func(query url.Values, key, value string) url.Values {
+		copied := url.Values{}
+		for k, v := range query {
+			copied[k] = append([]string{}, v...)
+		}
+		if len(value) <= 0 {
+			copied.Del(key)
+		} else {
+			copied.Set(key, value)
+		}
+		return copied
+	}
+
+	open := SignDownloadLink(secret, fileID, "", expires)
+	bound := SignDownloadLink(secret, fileID, userID, expires)
+
+	tests := []struct {
+		name     string
+		secret   string
+		fileID   string
+		callerID string
+		query    url.Values
+		now      time.Time
+		err      error
+	}{
+		{"guest with open link", secret, fileID, "", open, now, nil},
+		{"user with open link", secret, fileID, userID, open, now, nil},
+		{"valid until the expiry second", secret, fileID, "", open, expires, nil},
+		{"expired", secret, fileID, "", open, expires.Add(time.Second), ErrLinkExpired},
+		{"bound link for its user", secret, fileID, userID, bound, now, nil},
+		{"bound link for a guest", secret, fileID, "", bound, now, ErrLinkWrongUser},
+		{"bound link for another user", secret, fileID, "another-user", bound, now, ErrLinkWrongUser},
+		{"user removed from a bound link", secret, fileID, "", with(bound, "user", ""), now, ErrLinkInvalid},
+		{"user added to an open link", secret, fileID, "another-user", with(open, "user", "another-user"), now, ErrLinkInvalid},
+		{"expiry pushed back", secret, fileID, "", with(open, "expires", "1900000000"), now, ErrLinkInvalid},
+		{"expiry not a number", secret, fileID, "", with(open, "expires", "soon"), now, ErrLinkInvalid},
+		{"other file", secret, "other-file", "", open, now, ErrLinkInvalid},
+		{"other secret", "rotated-secret", fileID, "", open, now, ErrLinkInvalid},
+		{"no signature", secret, fileID, "", with(open, "signature", ""), now, ErrLinkUnsigned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyDownloadLink(tt.secret, tt.fileID, tt.callerID, tt.query, tt.now)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("VerifyDownloadLink() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package helper
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrWrongPassword = errors.New("wrong password")
+
+// HashPassword returns the bcrypt hash of the given plaintext password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// IsHashedPassword reports whether the stored value is a bcrypt hash
+// rather than a legacy plaintext password.
+func IsHashedPassword(stored string) bool {
+	_, err := bcrypt.Cost([]byte(stored))
+	return err == nil
+}
+
+// ComparePassword checks the plaintext password against the stored value.
+// Legacy plaintext rows are compared in constant time; needsRehash is true
+// for them so the caller can replace the row with a bcrypt hash.
+func ComparePassword(stored, password string) (needsRehash bool, err error) {
+	if IsHashedPassword(stored) {
+		err = bcrypt.CompareHashAndPassword([]byte(stored), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, ErrWrongPassword
+		}
+		return false, err
+	}
+
+	if len(stored) == 0 || subtle.ConstantTimeCompare([]byte(stored), []byte(password)) != 1 {
+		return false, ErrWrongPassword
+	}
+
+	return true, nil
+}
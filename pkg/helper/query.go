@@ -0,0 +1,164 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidSort is reported by QueryBuilder.Err when the sort field isn't
+// one of those allowed.
+var ErrInvalidSort = errors.New("invalid sort_by")
+
+// likeEscaper escapes the LIKE wildcards, and the escape character itself,
+// so a value only matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike returns value with its LIKE wildcards escaped.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// QueryBuilder collects WHERE conditions, ordering and pagination for list
+// queries. Every value is passed as a positional argument, nothing from the
+// request is ever concatenated into the SQL text.
+type QueryBuilder struct {
+	where  []string
+	args   []interface{}
+	order  string
+	offset int
+	limit  int
+	err    error
+}
+
+func NewQueryBuilder() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+// Arg registers a value and returns its placeholder, e.g. "$3".
+func (b *QueryBuilder) Arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// Where adds a raw condition. Placeholders inside it must come from Arg.
+func (b *QueryBuilder) Where(condition string) *QueryBuilder {
+	b.where = append(b.where, condition)
+	return b
+}
+
+// Search matches the value case-insensitively against any of the columns.
+// % and _ in the value match themselves.
+func (b *QueryBuilder) Search(value string, columns ...string) *QueryBuilder {
+	value = strings.TrimSpace(value)
+	if len(value) <= 0 || len(columns) <= 0 {
+		return b
+	}
+
+	placeholder := b.Arg(EscapeLike(value))
+
+	conditions := make([]string, 0, len(columns))
+	for _, column := range columns {
+		conditions = append(conditions, fmt.Sprintf("%s::text ILIKE '%%' || %s || '%%'", column, placeholder))
+	}
+
+	return b.Where("(" + strings.Join(conditions, " OR ") + ")")
+}
+
+// Equal filters column = value, skipped when value is empty.
+func (b *QueryBuilder) Equal(column string, value string) *QueryBuilder {
+	if len(value) <= 0 {
+		return b
+	}
+
+	return b.Where(fmt.Sprintf("%s = %s", column, b.Arg(value)))
+}
+
+// DateRange filters column between from and to, either side may be empty.
+// The upper bound is inclusive for the whole day when only a date is given.
+// Times keep their offset, those without one are in the database time zone.
+func (b *QueryBuilder) DateRange(column string, from, to string) *QueryBuilder {
+	if len(from) > 0 {
+		b.Where(fmt.Sprintf("%s >= %s::timestamptz", column, b.Arg(from)))
+	}
+
+	if len(to) > 0 {
+		if len(to) == len("2006-01-02") {
+			b.Where(fmt.Sprintf("%s < %s::date + INTERVAL '1 day'", column, b.Arg(to)))
+		} else {
+			b.Where(fmt.Sprintf("%s <= %s::timestamptz", column, b.Arg(to)))
+		}
+	}
+
+	return b
+}
+
+// OrderBy sorts by one of the allowed fields. allowed maps the public sort
+// name to the column expression; an empty name sorts by the default and an
+// unknown one is reported by Err.
+func (b *QueryBuilder) OrderBy(sortBy, order string, allowed map[string]string, defaultSort string) *QueryBuilder {
+	if len(sortBy) <= 0 {
+		sortBy = defaultSort
+	}
+
+	column, ok := allowed[sortBy]
+	if !ok {
+		b.err = ErrInvalidSort
+		return b
+	}
+
+	direction := "DESC"
+	if strings.EqualFold(order, "asc") {
+		direction = "ASC"
+	}
+
+	if len(column) > 0 {
+		b.order = fmt.Sprintf(" ORDER BY %s %s", column, direction)
+	}
+
+	return b
+}
+
+func (b *QueryBuilder) Paginate(offset, limit int) *QueryBuilder {
+	b.offset = offset
+	b.limit = limit
+	return b
+}
+
+// Err returns the first invalid input met while building, such as
+// ErrInvalidSort.
+func (b *QueryBuilder) Err() error {
+	return b.err
+}
+
+// Args returns the values registered with Arg, for queries that use
+// WhereClause directly.
+func (b *QueryBuilder) Args() []interface{} {
+	return b.args
+}
+
+// WhereClause returns the " WHERE ..." part, " WHERE TRUE" when empty.
+func (b *QueryBuilder) WhereClause() string {
+	if len(b.where) <= 0 {
+		return " WHERE TRUE"
+	}
+
+	return " WHERE " + strings.Join(b.where, " AND ")
+}
+
+// Build appends the conditions, ordering and pagination to the base query.
+func (b *QueryBuilder) Build(query string) (string, []interface{}) {
+	query += b.WhereClause() + b.order
+
+	if b.offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", b.offset)
+	}
+
+	limit := 10
+	if b.limit > 0 {
+		limit = b.limit
+	}
+	query += fmt.Sprintf(" LIMIT %d", limit)
+
+	return query, b.args
+}
@@ -0,0 +1,174 @@
+package helper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestQueryBuilderBuild(t *testing.T) {
+
+	sortFields := map[string]string{
+		"created_at": "created_at",
+		"title":      "p.title",
+	}
+
+	tests := []struct {
+		name  string
+		build func(qb *QueryBuilder)
+		query string
+		args  []interface{}
+		err   error
+	}{
+		{
+			name:  "no filters",
+			build: func(qb *QueryBuilder) {},
+			query: "SELECT * FROM t WHERE TRUE LIMIT 10",
+		},
+		{
+			name: "equal skips empty values",
+			build: func(qb *QueryBuilder) {
+				qb.Equal("course_id", "c1").Equal("status", "")
+			},
+			query: "SELECT * FROM t WHERE course_id = $1 LIMIT 10",
+			args:  []interface{}{"c1"},
+		},
+		{
+			name: "search shares one placeholder across columns",
+			build: func(qb *QueryBuilder) {
+				qb.Search("  go  ", "title", "description")
+			},
+			query: "SELECT * FROM t WHERE (title::text ILIKE '%' || $1 || '%' OR description::text ILIKE '%' || $1 || '%') LIMIT 10",
+			args:  []interface{}{"go"},
+		},
+		{
+			name: "search escapes wildcards",
+			build: func(qb *QueryBuilder) {
+				qb.Search(`50%_off\`, "title")
+			},
+			query: "SELECT * FROM t WHERE (title::text ILIKE '%' || $1 || '%') LIMIT 10",
+			args:  []interface{}{`50\%\_off\\`},
+		},
+		{
+			name: "blank search is skipped",
+			build: func(qb *QueryBuilder) {
+				qb.Search("   ", "title")
+			},
+			query: "SELECT * FROM t WHERE TRUE LIMIT 10",
+		},
+		{
+			name: "order and pagination",
+			build: func(qb *QueryBuilder) {
+				qb.OrderBy("title", "ASC", sortFields, "created_at").Paginate(20, 5)
+			},
+			query: "SELECT * FROM t WHERE TRUE ORDER BY p.title ASC OFFSET 20 LIMIT 5",
+		},
+		{
+			name: "empty sort uses the default, descending",
+			build: func(qb *QueryBuilder) {
+				qb.OrderBy("", "", sortFields, "created_at")
+			},
+			query: "SELECT * FROM t WHERE TRUE ORDER BY created_at DESC LIMIT 10",
+		},
+		{
+			name: "unknown sort is an error",
+			build: func(qb *QueryBuilder) {
+				qb.OrderBy("password", "asc", sortFields, "created_at")
+			},
+			query: "SELECT * FROM t WHERE TRUE LIMIT 10",
+			err:   ErrInvalidSort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qb := NewQueryBuilder()
+			tt.build(qb)
+
+			query, args := qb.Build("SELECT * FROM t")
+			if query != tt.query {
+				t.Errorf("query = %q, want %q", query, tt.query)
+			}
+			if len(args) != len(tt.args) || (len(args) > 0 && !reflect.DeepEqual(args, tt.args)) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+			if !errors.Is(qb.Err(), tt.err) {
+				t.Errorf("Err() = %v, want %v", qb.Err(), tt.err)
+			}
+		})
+	}
+}
+
+func TestQueryBuilderDateRange(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		from  string
+		to    string
+		where string
+		args  []interface{}
+	}{
+		{
+			name:  "no bounds",
+			where: " WHERE TRUE",
+		},
+		{
+			name:  "from keeps the offset",
+			from:  "2024-03-01T10:00:00+05:00",
+			where: " WHERE created_at >= $1::timestamptz",
+			args:  []interface{}{"2024-03-01T10:00:00+05:00"},
+		},
+		{
+			name:  "to a date includes the whole day",
+			to:    "2024-03-31",
+			where: " WHERE created_at < $1::date + INTERVAL '1 day'",
+			args:  []interface{}{"2024-03-31"},
+		},
+		{
+			name:  "to a time is inclusive",
+			to:    "2024-03-31T23:00:00Z",
+			where: " WHERE created_at <= $1::timestamptz",
+			args:  []interface{}{"2024-03-31T23:00:00Z"},
+		},
+		{
+			name:  "both bounds",
+			from:  "2024-03-01",
+			to:    "2024-03-31",
+			where: " WHERE created_at >= $1::timestamptz AND created_at < $2::date + INTERVAL '1 day'",
+			args:  []interface{}{"2024-03-01", "2024-03-31"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qb := NewQueryBuilder().DateRange("created_at", tt.from, tt.to)
+
+			if where := qb.WhereClause(); where != tt.where {
+				t.Errorf("WhereClause() = %q, want %q", where, tt.where)
+			}
+			if args := qb.Args(); len(args) != len(tt.args) || (len(args) > 0 && !reflect.DeepEqual(args, tt.args)) {
+				t.Errorf("Args() = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{"100%", `100\%`},
+		{"a_b", `a\_b`},
+		{`c:\dir`, `c:\\dir`},
+		{`%_\`, `\%\_\\`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeLike(tt.value); got != tt.want {
+			t.Errorf("EscapeLike(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
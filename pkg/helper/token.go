@@ -1,6 +1,10 @@
 package helper
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"strings"
 	"time"
@@ -11,6 +15,8 @@ import (
 
 type TokenInfo struct {
 	UserID     string `json:"user_id"`
+	Role       string `json:"role"`
+	SessionID  string `json:"session_id"`
 	ClientType string `json:"client_type"`
 	PlatformID string `json:"platform_id"`
 }
@@ -104,7 +110,9 @@ func ExtractClaims(tokenString string, tokenSecretKey string) (jwt.MapClaims, er
 	)
 
 	token, err = jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// check token signing method etc
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
 		return []byte(tokenSecretKey), nil
 	})
 
@@ -132,12 +140,18 @@ func ExtractToken(bearer string) (token string, err error) {
 func ParseClaims(token string, secretKey string) (result TokenInfo, err error) {
 	var claims jwt.MapClaims
 
+	if bearer, err := ExtractToken(token); err == nil {
+		token = bearer
+	}
+
 	claims, err = ExtractClaims(token, secretKey)
 	if err != nil {
 		return result, err
 	}
 
 	result.UserID = cast.ToString(claims["user_id"])
+	result.Role = cast.ToString(claims["role"])
+	result.SessionID = cast.ToString(claims["session_id"])
 	result.ClientType = cast.ToString(claims["client_type"])
 	result.PlatformID = cast.ToString(claims["platform_id"])
 	if len(result.UserID) <= 0 {
@@ -145,5 +159,43 @@ func ParseClaims(token string, secretKey string) (result TokenInfo, err error) {
 		return result, err
 	}
 
+	if len(result.Role) <= 0 {
+		err = errors.New("cannot parse 'role' field")
+		return result, err
+	}
+
 	return
 }
+
+// GenerateRefreshSecret returns a random url-safe secret for a refresh token.
+func GenerateRefreshSecret() (string, error) {
+	buffer := make([]byte, 32)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// HashToken returns the hex encoded sha256 of the token, used to store
+// refresh tokens and one-time codes without keeping the raw value.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// JoinRefreshToken builds the refresh token handed to the client.
+func JoinRefreshToken(sessionID, secret string) string {
+	return sessionID + "." + secret
+}
+
+// SplitRefreshToken returns the session id and secret of a refresh token.
+func SplitRefreshToken(token string) (sessionID string, secret string, err error) {
+	strArr := strings.SplitN(token, ".", 2)
+	if len(strArr) != 2 || len(strArr[0]) <= 0 || len(strArr[1]) <= 0 {
+		return "", "", errors.New("wrong refresh token format")
+	}
+
+	return strArr[0], strArr[1], nil
+}
@@ -3,6 +3,7 @@ package helper
 import (
 	"errors"
 	"regexp"
+	"time"
 )
 
 func ValidPinfl(pinfl string) error {
@@ -47,9 +48,11 @@ func IsValidLogin(login string) bool {
 	return r.MatchString(login)
 }
 
-// IsValidUUID ...
+// IsValidUUID reports whether uuid is a UUID in the canonical 8-4-4-4-12
+// form, of any version: ids backfilled by migrations are derived from md5
+// hashes and are not version 4.
 func IsValidUUID(uuid string) bool {
-	r := regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-4[a-fA-F0-9]{3}-[8|9|aA|bB][a-fA-F0-9]{3}-[a-fA-F0-9]{12}$")
+	r := regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$")
 	return r.MatchString(uuid)
 }
 
@@ -62,4 +65,14 @@ func IsValidUUIDV1(uuid string) bool {
 func IsValidPrice(price string) bool {
 	r := regexp.MustCompile(`^\d+$`)
 	return r.MatchString(price)
-}
\ No newline at end of file
+}
+
+// IsValidDate accepts a date (2006-01-02), a date with time or RFC3339.
+func IsValidDate(date string) bool {
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339} {
+		if _, err := time.Parse(layout, date); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+// Package imaging re-encodes uploaded images without their metadata and
+// renders the standard size variants. Only the standard library decoders
+// are used, so JPEG, PNG and GIF are supported.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// Variant names a rendition of an image.
+type Variant string
+
+const (
+	// VariantOriginal is the image at full size, re-encoded without
+	// metadata.
+	VariantOriginal Variant = "original"
+	VariantMedium   Variant = "medium"
+	VariantThumb    Variant = "thumb"
+)
+
+// Variants lists every variant, the original first.
+var Variants = []Variant{VariantOriginal, VariantMedium, VariantThumb}
+
+// maxSides is the longest side in pixels of the resized variants. Smaller
+// images are not enlarged.
+var maxSides = map[Variant]int{
+	VariantMedium: 1024,
+	VariantThumb:  256,
+}
+
+// MaxPixels bounds the decoded size of an image, so a small file can't
+// expand into gigabytes of pixels.
+const MaxPixels = 40_000_000
+
+// jpegQuality is used for every JPEG that is written.
+const jpegQuality = 85
+
+var (
+	ErrUnknownVariant = errors.New("unknown image size")
+	ErrUnsupported    = errors.New("image format is not supported")
+	ErrTooManyPixels  = errors.New("image dimensions are too large")
+)
+
+// Encoded is one variant ready to be stored.
+type Encoded struct {
+	Variant     Variant
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// ParseVariant parses the size query parameter. Empty means the original.
+func ParseVariant(size string) (Variant, error) {
+	if len(size) <= 0 {
+		return VariantOriginal, nil
+	}
+
+	for _, variant := range Variants {
+		if string(variant) == size {
+			return variant, nil
+		}
+	}
+
+	return "", ErrUnknownVariant
+}
+
+// Process decodes an image, turns it upright according to its EXIF
+// orientation and encodes the requested variants. Nothing but the pixels
+// is kept: EXIF (GPS included), ICC profiles and comments are dropped.
+// JPEGs stay JPEGs, everything else is written as PNG; animated GIFs keep
+// their first frame.
+func Process(data []byte, variants ...Variant) ([]*Encoded, error) {
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	var img image.Image
+	switch format {
+	case "jpeg":
+		img, err = jpeg.Decode(bytes.NewReader(data))
+		if err == nil {
+			img = orient(img, jpegOrientation(data))
+		}
+	case "png":
+		img, err = png.Decode(bytes.NewReader(data))
+	case "gif":
+		img, err = gif.Decode(bytes.NewReader(data))
+	default:
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	var resp []*Encoded
+	for _, variant := range variants {
+		rendered := img
+		if variant != VariantOriginal {
+			maxSide, ok := maxSides[variant]
+			if !ok {
+				return nil, ErrUnknownVariant
+			}
+			rendered = Resize(img, maxSide)
+		}
+
+		encoded, err := encode(rendered, format)
+		if err != nil {
+			return nil, err
+		}
+		encoded.Variant = variant
+
+		resp = append(resp, encoded)
+	}
+
+	return resp, nil
+}
+
+func encode(img image.Image, format string) (*Encoded, error) {
+
+	var (
+		buf         bytes.Buffer
+		contentType string
+		err         error
+	)
+
+	if format == "jpeg" {
+		contentType = "image/jpeg"
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		contentType = "image/png"
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+
+	return &Encoded{
+		Data:        buf.Bytes(),
+		ContentType: contentType,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+	}, nil
+}
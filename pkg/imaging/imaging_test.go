@@ -0,0 +1,389 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// halves returns a w×h image, red on the left half and blue on the right.
+func halves(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, image.Rect(0, 0, w/2, h), image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(w/2, 0, w, h), image.NewUniform(color.RGBA{0, 0, 255, 255}), image.Point{}, draw.Src)
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// gpsMarker is written in the GPS IFD of the test EXIF data, so its
+// absence shows the location was dropped.
+const gpsMarker = "GPS-48.8584N-2.2945E"
+
+// exifSegment builds an APP1 segment with an orientation tag and a GPS IFD
+// holding a latitude reference and a map datum marked with gpsMarker.
+func exifSegment(orientation uint16) []byte {
+
+	var (
+		tiff  bytes.Buffer
+		order = binary.LittleEndian
+	)
+
+	put := func(v any) { binary.Write(&tiff, order, v) }
+
+	tiff.WriteString("II")
+	put(uint16(42))
+	put(uint32(8))
+
+	// IFD0 at 8: orientation and the GPS IFD pointer
+	put(uint16(2))
+	put([]uint16{0x0112, 3})
+	put(uint32(1))
+	put([]uint16{orientation, 0})
+	put([]uint16{0x8825, 4})
+	put(uint32(1))
+	put(uint32(8 + 2 + 2*12 + 4))
+	put(uint32(0))
+
+	// GPS IFD at 38: GPSLatitudeRef "N" inline, GPSMapDatum out of line
+	put(uint16(2))
+	put([]uint16{0x0001, 2})
+	put(uint32(2))
+	tiff.Write([]byte{'N', 0, 0, 0})
+	put([]uint16{0x0012, 2})
+	put(uint32(len(gpsMarker) + 1))
+	put(uint32(38 + 2 + 2*12 + 4))
+	put(uint32(0))
+	tiff.WriteString(gpsMarker + "\x00")
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	head := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(head[2:], uint16(len(segment)+2))
+	return append(head, segment...)
+}
+
+// withExif inserts an EXIF segment right after the SOI marker of a JPEG.
+func withExif(data []byte, orientation uint16) []byte {
+	resp := append([]byte{}, data[:2]...)
+	resp = append(resp, exifSegment(orientation)...)
+	return append(resp, data[2:]...)
+}
+
+// markers lists the JPEG markers before the image data.
+func markers(data []byte) []byte {
+
+	var resp []byte
+
+	pos := 2
+	for pos+4 <= len(data) && data[pos] == 0xFF {
+		marker := data[pos+1]
+		resp = append(resp, marker)
+		if marker == 0xDA {
+			break
+		}
+		pos += 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+	}
+
+	return resp
+}
+
+func TestProcessStripsExif(t *testing.T) {
+
+	data := withExif(encodeJPEG(t, halves(64, 32)), 1)
+
+	if !bytes.Contains(markers(data), []byte{0xE1}) || !bytes.Contains(data, []byte(gpsMarker)) {
+		t.Fatal("the test image has no EXIF segment")
+	}
+
+	encoded, err := Process(data, Variants...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range encoded {
+		if e.ContentType != "image/jpeg" {
+			t.Errorf("%s: content type = %s, want image/jpeg", e.Variant, e.ContentType)
+		}
+		for _, marker := range markers(e.Data) {
+			if marker >= 0xE1 && marker <= 0xEF {
+				t.Errorf("%s: output has an APP%d segment", e.Variant, marker-0xE0)
+			}
+		}
+		if bytes.Contains(e.Data, []byte("Exif")) || bytes.Contains(e.Data, []byte(gpsMarker)) {
+			t.Errorf("%s: output still carries EXIF or GPS data", e.Variant)
+		}
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+
+	plain := encodeJPEG(t, halves(16, 8))
+
+	for orientation := uint16(1); orientation <= 8; orientation++ {
+		if got := jpegOrientation(withExif(plain, orientation)); got != int(orientation) {
+			t.Errorf("jpegOrientation = %d, want %d", got, orientation)
+		}
+	}
+
+	if got := jpegOrientation(plain); got != 1 {
+		t.Errorf("jpegOrientation without EXIF = %d, want 1", got)
+	}
+	if got := jpegOrientation(withExif(plain, 9)); got != 1 {
+		t.Errorf("jpegOrientation of an invalid value = %d, want 1", got)
+	}
+	if got := jpegOrientation([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF}); got != 1 {
+		t.Errorf("jpegOrientation of a truncated segment = %d, want 1", got)
+	}
+}
+
+func TestProcessAppliesOrientation(t *testing.T) {
+
+	// Stored 80×40, red left and blue right. Rotated 90° clockwise the
+	// photo is 40×80, red on top and blue at the bottom.
+	data := withExif(encodeJPEG(t, halves(80, 40)), 6)
+
+	encoded, err := Process(data, VariantOriginal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := encoded[0]
+	if e.Width != 40 || e.Height != 80 {
+		t.Fatalf("size = %d×%d, want 40×80", e.Width, e.Height)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(e.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	isRed := func(c color.Color) bool {
+		r, _, b, _ := c.RGBA()
+		return r > 0xC000 && b < 0x4000
+	}
+	isBlue := func(c color.Color) bool {
+		r, _, b, _ := c.RGBA()
+		return b > 0xC000 && r < 0x4000
+	}
+
+	if c := img.At(20, 20); !isRed(c) {
+		t.Errorf("top = %v, want red", c)
+	}
+	if c := img.At(20, 60); !isBlue(c) {
+		t.Errorf("bottom = %v, want blue", c)
+	}
+}
+
+func TestOrient(t *testing.T) {
+
+	// A 3×2 image with distinct pixels, upright results written as rows
+	// of source indexes
+	src := image.NewGray(image.Rect(0, 0, 3, 2))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i)
+	}
+
+	tests := []struct {
+		orientation int
+		want        [][]uint8
+	}{
+		{1, [][]uint8{{0, 1, 2}, {3, 4, 5}}},
+		{2, [][]uint8{{2, 1, 0}, {5, 4, 3}}},
+		{3, [][]uint8{{5, 4, 3}, {2, 1, 0}}},
+		{4, [][]uint8{{3, 4, 5}, {0, 1, 2}}},
+		{5, [][]uint8{{0, 3}, {1, 4}, {2, 5}}},
+		{6, [][]uint8{{3, 0}, {4, 1}, {5, 2}}},
+		{7, [][]uint8{{5, 2}, {4, 1}, {3, 0}}},
+		{8, [][]uint8{{2, 5}, {1, 4}, {0, 3}}},
+	}
+
+	for _, tt := range tests {
+		img := orient(src, tt.orientation)
+
+		bounds := img.Bounds()
+		if bounds.Dy() != len(tt.want) || bounds.Dx() != len(tt.want[0]) {
+			t.Errorf("orientation %d: size = %v", tt.orientation, bounds.Size())
+			continue
+		}
+
+		for y, row := range tt.want {
+			for x, want := range row {
+				if got := color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y; got != want {
+					t.Errorf("orientation %d: (%d, %d) = %d, want %d", tt.orientation, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+// withSize rewrites the dimensions in the IHDR chunk of a PNG and drops
+// everything after it, so the file can't be decoded any further.
+func withSize(data []byte, w, h uint32) []byte {
+
+	// signature (8), IHDR length and type (8), 13 bytes of data, CRC
+	resp := append([]byte{}, data[:8+8+13+4]...)
+	binary.BigEndian.PutUint32(resp[16:], w)
+	binary.BigEndian.PutUint32(resp[20:], h)
+	binary.BigEndian.PutUint32(resp[29:], crc32.ChecksumIEEE(resp[12:29]))
+
+	return resp
+}
+
+func TestProcessTooManyPixels(t *testing.T) {
+
+	small := encodePNG(t, halves(4, 4))
+
+	tests := []struct {
+		name string
+		w, h uint32
+		err  error
+	}{
+		// The header alone is rejected, the missing pixel data is never read
+		{"huge", 20000, 20000, ErrTooManyPixels},
+		{"just over", MaxPixels/1000 + 1, 1000, ErrTooManyPixels},
+		// Within bounds the decode is attempted and fails on the missing data
+		{"allowed", MaxPixels / 1000, 1000, ErrUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Process(withSize(small, tt.w, tt.h), VariantOriginal); !errors.Is(err, tt.err) {
+				t.Errorf("Process = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestProcessVariantSizes(t *testing.T) {
+
+	tests := []struct {
+		name string
+		w, h int
+		want map[Variant][2]int
+	}{
+		{"landscape", 2048, 1024, map[Variant][2]int{
+			VariantOriginal: {2048, 1024},
+			VariantMedium:   {1024, 512},
+			VariantThumb:    {256, 128},
+		}},
+		{"portrait", 600, 1500, map[Variant][2]int{
+			VariantOriginal: {600, 1500},
+			VariantMedium:   {410, 1024},
+			VariantThumb:    {102, 256},
+		}},
+		{"smaller than medium", 300, 200, map[Variant][2]int{
+			VariantOriginal: {300, 200},
+			VariantMedium:   {300, 200},
+			VariantThumb:    {256, 171},
+		}},
+		{"smaller than thumb", 100, 50, map[Variant][2]int{
+			VariantOriginal: {100, 50},
+			VariantMedium:   {100, 50},
+			VariantThumb:    {100, 50},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := Process(encodePNG(t, halves(tt.w, tt.h)), Variants...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(encoded) != len(Variants) {
+				t.Fatalf("got %d variants, want %d", len(encoded), len(Variants))
+			}
+
+			for _, e := range encoded {
+				want := tt.want[e.Variant]
+				if e.Width != want[0] || e.Height != want[1] {
+					t.Errorf("%s: %d×%d, want %d×%d", e.Variant, e.Width, e.Height, want[0], want[1])
+				}
+				if e.ContentType != "image/png" {
+					t.Errorf("%s: content type = %s, want image/png", e.Variant, e.ContentType)
+				}
+
+				config, err := png.DecodeConfig(bytes.NewReader(e.Data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if config.Width != e.Width || config.Height != e.Height {
+					t.Errorf("%s: encoded as %d×%d, reported %d×%d", e.Variant, config.Width, config.Height, e.Width, e.Height)
+				}
+			}
+		})
+	}
+}
+
+func TestResizeKeepsColors(t *testing.T) {
+
+	img := Resize(halves(400, 100), 100)
+
+	if size := img.Bounds().Size(); size != image.Pt(100, 25) {
+		t.Fatalf("size = %v, want 100×25", size)
+	}
+
+	want := map[image.Point]color.RGBA{
+		{10, 12}: {255, 0, 0, 255},
+		{90, 12}: {0, 0, 255, 255},
+	}
+	for p, c := range want {
+		if got := color.RGBAModel.Convert(img.At(p.X, p.Y)); got != c {
+			t.Errorf("%v = %v, want %v", p, got, c)
+		}
+	}
+
+	// The area average doesn't bleed across a seam on a pixel boundary
+	if got := color.RGBAModel.Convert(img.At(49, 12)).(color.RGBA); got.R != 255 || got.B != 0 {
+		t.Errorf("last red column = %v", got)
+	}
+}
+
+func TestParseVariant(t *testing.T) {
+
+	tests := []struct {
+		size string
+		want Variant
+		err  error
+	}{
+		{"", VariantOriginal, nil},
+		{"original", VariantOriginal, nil},
+		{"medium", VariantMedium, nil},
+		{"thumb", VariantThumb, nil},
+		{"large", "", ErrUnknownVariant},
+		{"THUMB", "", ErrUnknownVariant},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVariant(tt.size)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("ParseVariant(%q) = %q, %v, want %q, %v", tt.size, got, err, tt.want, tt.err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+)
+
+// jpegOrientation returns the EXIF orientation (1-8) of a JPEG, or 1 when
+// it has none. Cameras store photos as the sensor saw them and record the
+// rotation here, so it has to be applied before the EXIF data is dropped.
+func jpegOrientation(data []byte) int {
+
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+
+		marker := data[pos+1]
+		switch {
+		case marker == 0xFF:
+			// Fill byte
+			pos++
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// Markers without a length
+			pos += 2
+			continue
+		case marker == 0xDA || marker == 0xD9:
+			// The image data starts, metadata comes before it
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+
+		pos = end
+	}
+
+	return 1
+}
+
+// tiffOrientation reads the orientation tag from the first IFD of the
+// TIFF structure inside an EXIF segment.
+func tiffOrientation(tiff []byte) int {
+
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	if order.Uint16(tiff[2:]) != 42 {
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		// Tag 0x0112 is the orientation, a SHORT stored inline
+		if order.Uint16(tiff[entry:]) != 0x0112 || order.Uint16(tiff[entry+2:]) != 3 {
+			continue
+		}
+
+		orientation := int(order.Uint16(tiff[entry+8:]))
+		if orientation < 1 || orientation > 8 {
+			return 1
+		}
+		return orientation
+	}
+
+	return 1
+}
+
+// orient returns img turned upright for the EXIF orientation. The pixels
+// are not copied, the returned image maps every lookup to the source.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	return &oriented{src: img, orientation: orientation}
+}
+
+type oriented struct {
+	src         image.Image
+	orientation int
+}
+
+func (o *oriented) ColorModel() color.Model {
+	return o.src.ColorModel()
+}
+
+func (o *oriented) Bounds() image.Rectangle {
+	bounds := o.src.Bounds()
+	if o.orientation >= 5 {
+		// Orientations 5-8 swap width and height
+		return image.Rect(0, 0, bounds.Dy(), bounds.Dx())
+	}
+	return image.Rect(0, 0, bounds.Dx(), bounds.Dy())
+}
+
+func (o *oriented) At(x, y int) color.Color {
+	return o.src.At(o.source(x, y))
+}
+
+// RGBA64At avoids the allocation of At when the source supports it.
+func (o *oriented) RGBA64At(x, y int) color.RGBA64 {
+	sx, sy := o.source(x, y)
+
+	if src, ok := o.src.(image.RGBA64Image); ok {
+		return src.RGBA64At(sx, sy)
+	}
+
+	r, g, b, a := o.src.At(sx, sy).RGBA()
+	return color.RGBA64{R: uint16(r), G: uint16(g), B: uint16(b), A: uint16(a)}
+}
+
+// source maps a pixel of the upright image to the source image.
+func (o *oriented) source(x, y int) (int, int) {
+
+	var (
+		bounds = o.src.Bounds()
+		w, h   = bounds.Dx(), bounds.Dy()
+		sx, sy int
+	)
+
+	switch o.orientation {
+	case 2: // mirrored
+		sx, sy = w-1-x, y
+	case 3: // rotated 180°
+		sx, sy = w-1-x, h-1-y
+	case 4: // mirrored vertically
+		sx, sy = x, h-1-y
+	case 5: // transposed
+		sx, sy = y, x
+	case 6: // rotated 90° clockwise
+		sx, sy = y, h-1-x
+	case 7: // transversed
+		sx, sy = w-1-y, h-1-x
+	case 8: // rotated 90° counter-clockwise
+		sx, sy = w-1-y, x
+	default:
+		sx, sy = x, y
+	}
+
+	return bounds.Min.X + sx, bounds.Min.Y + sy
+}
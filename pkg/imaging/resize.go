@@ -0,0 +1,116 @@
+package imaging
+
+import (
+	"image"
+)
+
+// Resize scales img down so its longest side is at most maxSide pixels,
+// keeping the aspect ratio. Every output pixel is the area weighted
+// average of the source pixels it covers, which is what downscaling needs
+// to stay free of aliasing. Source rows are read one at a time, so memory
+// grows with the output size only. Images that already fit are returned
+// as they are.
+func Resize(img image.Image, maxSide int) image.Image {
+
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	if maxSide <= 0 || (srcW <= maxSide && srcH <= maxSide) {
+		return img
+	}
+
+	dstW, dstH := maxSide, maxSide
+	if srcW >= srcH {
+		dstH = max(1, int(float64(srcH)*float64(maxSide)/float64(srcW)+0.5))
+	} else {
+		dstW = max(1, int(float64(srcW)*float64(maxSide)/float64(srcH)+0.5))
+	}
+
+	var (
+		cols = contributions(srcW, dstW)
+		rows = contributions(srcH, dstH)
+		row  = make([]float32, dstW*4)
+		acc  = make([]float32, dstW*dstH*4)
+		at   = pixelReader(img)
+	)
+
+	for sy := 0; sy < srcH; sy++ {
+		clear(row)
+
+		// Horizontal pass over one source row
+		for sx := 0; sx < srcW; sx++ {
+			r, g, b, a := at(bounds.Min.X+sx, bounds.Min.Y+sy)
+			for _, c := range cols[sx] {
+				i := c.index * 4
+				row[i] += float32(r) * c.weight
+				row[i+1] += float32(g) * c.weight
+				row[i+2] += float32(b) * c.weight
+				row[i+3] += float32(a) * c.weight
+			}
+		}
+
+		// Vertical pass: add the row to the output rows it covers
+		for _, c := range rows[sy] {
+			out := acc[c.index*dstW*4 : (c.index+1)*dstW*4]
+			for i, v := range row {
+				out[i] += v * c.weight
+			}
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for i, v := range acc {
+		dst.Pix[i] = uint8(min(max(v/257+0.5, 0), 255))
+	}
+
+	return dst
+}
+
+type contribution struct {
+	index  int
+	weight float32
+}
+
+// contributions returns, for every source index, the destination indexes
+// it overlaps and by how much. The weights of one destination index add
+// up to 1.
+func contributions(src, dst int) [][]contribution {
+
+	var (
+		scale = float64(src) / float64(dst)
+		resp  = make([][]contribution, src)
+	)
+
+	for j := 0; j < src; j++ {
+		lo, hi := float64(j), float64(j+1)
+
+		first := int(lo / scale)
+		last := min(int((hi-1e-9)/scale), dst-1)
+
+		for i := first; i <= last; i++ {
+			overlap := min(hi, float64(i+1)*scale) - max(lo, float64(i)*scale)
+			if overlap > 0 {
+				resp[j] = append(resp[j], contribution{index: i, weight: float32(overlap / scale)})
+			}
+		}
+	}
+
+	return resp
+}
+
+// pixelReader returns a function reading premultiplied 16-bit RGBA values,
+// avoiding a color.Color allocation per pixel when the image allows it.
+func pixelReader(img image.Image) func(x, y int) (r, g, b, a uint16) {
+
+	if fast, ok := img.(image.RGBA64Image); ok {
+		return func(x, y int) (uint16, uint16, uint16, uint16) {
+			c := fast.RGBA64At(x, y)
+			return c.R, c.G, c.B, c.A
+		}
+	}
+
+	return func(x, y int) (uint16, uint16, uint16, uint16) {
+		r, g, b, a := img.At(x, y).RGBA()
+		return uint16(r), uint16(g), uint16(b), uint16(a)
+	}
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"app/pkg/logger"
+)
+
+type fileMailer struct {
+	dir  string
+	from string
+	log  logger.LoggerI
+}
+
+// NewFile returns a Mailer for local development that writes every message
+// to an .eml file in dir and logs it instead of sending it.
+func NewFile(dir, from string, log logger.LoggerI) Mailer {
+	return &fileMailer{
+		dir:  dir,
+		from: from,
+		log:  log,
+	}
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(m.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	path := filepath.Join(m.dir, name)
+
+	err := os.WriteFile(path, buildMessage(m.from, msg), 0o600)
+	if err != nil {
+		return err
+	}
+
+	m.log.Info("mail written", logger.String("to", msg.To), logger.String("subject", msg.Subject), logger.String("path", path))
+
+	return nil
+}
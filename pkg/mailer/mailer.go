@@ -0,0 +1,25 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+)
+
+var ErrUnknownDriver = errors.New("mailer: unknown driver")
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers plain text messages. Implementations must be safe for
+// concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,95 @@
+// Package moderation defines the lifecycle of a publication. Contributors
+// write drafts and submit them for review, admins publish or reject them,
+// and anything that is done with ends up archived:
+//
+//	draft → pending_review → published → archived
+//	              ↓    ↑
+//	            rejected → archived
+package moderation
+
+import "errors"
+
+// Status is the moderation state of a publication.
+type Status string
+
+const (
+	Draft         Status = "draft"
+	PendingReview Status = "pending_review"
+	Published     Status = "published"
+	Rejected      Status = "rejected"
+	Archived      Status = "archived"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid publication status")
+	ErrInvalidTransition = errors.New("publication status can't change this way")
+	ErrReasonRequired    = errors.New("a reason is required to reject a publication")
+	ErrNotPending        = errors.New("publication is not waiting for review")
+	ErrClaimed           = errors.New("publication is claimed by another moderator")
+)
+
+// transitions lists the statuses each status may move to.
+var transitions = map[Status][]Status{
+	Draft:         {PendingReview, Archived},
+	PendingReview: {Published, Rejected, Draft},
+	Published:     {Archived},
+	Rejected:      {PendingReview, Archived},
+	Archived:      {},
+}
+
+// Valid reports whether s is a known status.
+func Valid(s Status) bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// Initial reports whether a publication may be created in status s.
+func Initial(s Status) bool {
+	return s == Draft || s == PendingReview || s == Published
+}
+
+// CanTransition reports whether a publication may move from one status to
+// the other.
+func CanTransition(from, to Status) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Decision reports whether moving to status to is a review decision, the
+// moves a claim on the publication protects.
+func Decision(to Status) bool {
+	return to == Published || to == Rejected
+}
+
+// Edited returns the status a publication moves to when its contributor
+// changes the content. Published and rejected work goes back to review so
+// an edit can't go live unchecked, anything else keeps its status. This is
+// not a transition a caller can ask for, so it isn't in the table above.
+func Edited(from Status) Status {
+	if from == Published || from == Rejected {
+		return PendingReview
+	}
+	return from
+}
+
+// Check validates a transition. Rejections need a reason so the
+// contributor knows what to fix.
+func Check(from, to Status, reason string) error {
+	if !Valid(to) {
+		return ErrInvalidStatus
+	}
+
+	if !CanTransition(from, to) {
+		return ErrInvalidTransition
+	}
+
+	if to == Rejected && len(reason) <= 0 {
+		return ErrReasonRequired
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package moderation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		from   Status
+		to     Status
+		reason string
+		err    error
+	}{
+		{"submit a draft", Draft, PendingReview, "", nil},
+		{"archive a draft", Draft, Archived, "", nil},
+		{"publish a draft directly", Draft, Published, "", ErrInvalidTransition},
+		{"approve", PendingReview, Published, "", nil},
+		{"reject with a reason", PendingReview, Rejected, "blurry scan", nil},
+		{"reject without a reason", PendingReview, Rejected, "", ErrReasonRequired},
+		{"withdraw to draft", PendingReview, Draft, "", nil},
+		{"archive while pending", PendingReview, Archived, "", ErrInvalidTransition},
+		{"archive a published one", Published, Archived, "", nil},
+		{"unpublish to draft", Published, Draft, "", ErrInvalidTransition},
+		{"resubmit a rejected one", Rejected, PendingReview, "", nil},
+		{"publish a rejected one", Rejected, Published, "", ErrInvalidTransition},
+		{"archive a rejected one", Rejected, Archived, "", nil},
+		{"archived is final", Archived, Draft, "", ErrInvalidTransition},
+		{"same status", Published, Published, "", ErrInvalidTransition},
+		{"unknown target", Draft, "deleted", "", ErrInvalidStatus},
+		{"unknown source", "deleted", Draft, "", ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Check(tt.from, tt.to, tt.reason); !errors.Is(err, tt.err) {
+				t.Errorf("Check(%s, %s) = %v, want %v", tt.from, tt.to, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestInitial(t *testing.T) {
+
+	tests := []struct {
+		status Status
+		want   bool
+	}{
+		{Draft, true},
+		{PendingReview, true},
+		{Published, true},
+		{Rejected, false},
+		{Archived, false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := Initial(tt.status); got != tt.want {
+			t.Errorf("Initial(%q) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestDecision(t *testing.T) {
+
+	for status := range transitions {
+		want := status == Published || status == Rejected
+		if got := Decision(status); got != want {
+			t.Errorf("Decision(%s) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestEdited(t *testing.T) {
+
+	tests := []struct {
+		from Status
+		want Status
+	}{
+		{Draft, Draft},
+		{PendingReview, PendingReview},
+		{Published, PendingReview},
+		{Rejected, PendingReview},
+		{Archived, Archived},
+	}
+
+	for _, tt := range tests {
+		if got := Edited(tt.from); got != tt.want {
+			t.Errorf("Edited(%s) = %s, want %s", tt.from, got, tt.want)
+		}
+	}
+}
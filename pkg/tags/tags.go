@@ -0,0 +1,91 @@
+// Package tags normalizes publication tags. A tag is stored lowercased,
+// trimmed and with inner whitespace collapsed to one space, so "Go ",
+// "go" and "GO" are the same tag. Publications still expose their tags as
+// one comma separated string, sorted, built with Join.
+package tags
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxLength is the longest tag accepted, in characters.
+	MaxLength = 50
+	// MaxCount is how many tags a publication may have.
+	MaxCount = 20
+)
+
+// Match tells how a filter on several tags combines them.
+type Match string
+
+const (
+	// MatchAll keeps publications that have every tag.
+	MatchAll Match = "all"
+	// MatchAny keeps publications that have at least one of the tags.
+	MatchAny Match = "any"
+)
+
+var (
+	ErrTooLong      = errors.New("tags are at most 50 characters long")
+	ErrTooMany      = errors.New("a publication has at most 20 tags")
+	ErrEmpty        = errors.New("tag name is empty")
+	ErrInvalidMatch = errors.New("invalid tag match, use all or any")
+	ErrExists       = errors.New("a tag with this name exists, merge the tags instead")
+)
+
+// Normalize returns the canonical form of one tag, empty when nothing is
+// left of it.
+func Normalize(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Split parses a comma separated list of tags into sorted, distinct,
+// normalized tags.
+func Split(raw string) []string {
+
+	var (
+		seen = map[string]bool{}
+		tags []string
+	)
+
+	for _, name := range strings.Split(raw, ",") {
+		name = Normalize(name)
+		if len(name) <= 0 || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
+
+// Join is the inverse of Split.
+func Join(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+// Check validates tags a user sends.
+func Check(tags []string) error {
+	if len(tags) > MaxCount {
+		return ErrTooMany
+	}
+
+	for _, name := range tags {
+		if utf8.RuneCountInString(name) > MaxLength {
+			return ErrTooLong
+		}
+	}
+
+	return nil
+}
+
+// ValidMatch reports whether m is a known match, empty meaning MatchAll.
+func ValidMatch(m Match) bool {
+	return len(m) <= 0 || m == MatchAll || m == MatchAny
+}
@@ -0,0 +1,107 @@
+package tags
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"go", "go"},
+		{"  Go ", "go"},
+		{"GO", "go"},
+		{"Machine   Learning", "machine learning"},
+		{"\tdata\nscience ", "data science"},
+		{"Ünïcode", "ünïcode"},
+		{"   ", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.name); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"", nil},
+		{" , ,", nil},
+		{"go", []string{"go"}},
+		{"sql,Go,algebra", []string{"algebra", "go", "sql"}},
+		{"Go, go ,GO", []string{"go"}},
+		{"linear  algebra,Linear Algebra", []string{"linear algebra"}},
+		{"b,,a,", []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		got := Split(tt.raw)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+		if joined := Join(got); !reflect.DeepEqual(Split(joined), tt.want) {
+			t.Errorf("Split(Join(%q)) = %q, want %q", got, Split(joined), tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+
+	many := make([]string, MaxCount+1)
+	for i := range many {
+		many[i] = strings.Repeat("t", i+1)
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		err  error
+	}{
+		{"none", nil, nil},
+		{"some", []string{"go", "sql"}, nil},
+		{"at the count limit", many[:MaxCount], nil},
+		{"over the count limit", many, ErrTooMany},
+		{"at the length limit", []string{strings.Repeat("a", MaxLength)}, nil},
+		{"over the length limit", []string{strings.Repeat("a", MaxLength+1)}, ErrTooLong},
+		{"length counts characters", []string{strings.Repeat("é", MaxLength)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Check(tt.tags); !errors.Is(err, tt.err) {
+				t.Errorf("Check() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidMatch(t *testing.T) {
+
+	tests := []struct {
+		match Match
+		want  bool
+	}{
+		{"", true},
+		{MatchAll, true},
+		{MatchAny, true},
+		{"ALL", false},
+		{"none", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidMatch(tt.match); got != tt.want {
+			t.Errorf("ValidMatch(%q) = %v, want %v", tt.match, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Package trash names what can be soft deleted. Deleting a semester, course,
+// publication, user or notification only sets its deleted_at; reads skip
+// such rows, an admin can restore them and a job purges them for good once
+// the retention period is over. Deleting a parent trashes its children with
+// the same deleted_at, so restoring the parent brings them back:
+//
+//	semester → courses → publications
+package trash
+
+import "errors"
+
+// Kind is a type of entity that can be in the trash.
+type Kind string
+
+const (
+	Semester     Kind = "semester"
+	Course       Kind = "course"
+	Publication  Kind = "publication"
+	User         Kind = "user"
+	Notification Kind = "notification"
+)
+
+// Kinds lists every kind, in the order the trash is listed.
+var Kinds = []Kind{Semester, Course, Publication, User, Notification}
+
+var (
+	ErrInvalidKind   = errors.New("invalid trash kind")
+	ErrParentDeleted = errors.New("restore the parent first, it is in the trash")
+	ErrTaken         = errors.New("email or username is used by another user")
+)
+
+// Valid reports whether k is a known kind.
+func Valid(k Kind) bool {
+	for _, kind := range Kinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package upload
+
+import (
+	"errors"
+	"mime"
+)
+
+// Kind is what an upload is for. Each kind has its own Policy.
+type Kind string
+
+const (
+	KindProfileImage     Kind = "profile_image"
+	KindPublicationCover Kind = "publication_cover"
+	KindPublicationFile  Kind = "publication_file"
+	KindPublicationVideo Kind = "publication_video"
+)
+
+var (
+	ErrUnknownKind     = errors.New("unknown upload kind")
+	ErrTooLarge        = errors.New("file is too large")
+	ErrUnsupportedType = errors.New("file type is not allowed")
+	ErrQuotaExceeded   = errors.New("storage quota exceeded")
+)
+
+var imageTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+}
+
+// photoTypes are the images that are re-encoded on upload, see Processed.
+// WebP is not among them since the standard library can't decode it.
+var photoTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+}
+
+var documentTypes = []string{
+	"application/pdf",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.ms-powerpoint",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"application/zip",
+	"text/plain",
+}
+
+var videoTypes = []string{
+	"video/mp4",
+	"video/webm",
+	"video/avi",
+	"video/quicktime",
+	"video/x-matroska",
+}
+
+// Policy limits the size and the content types of an upload. Types are
+// compared against the type sniffed from the file content.
+type Policy struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+// Limits holds the maximum size in bytes of every kind.
+type Limits struct {
+	ProfileImage     int64
+	PublicationCover int64
+	PublicationFile  int64
+	PublicationVideo int64
+}
+
+// PolicyFor returns the policy of the kind with the configured limits.
+func PolicyFor(kind Kind, limits Limits) (Policy, error) {
+	switch kind {
+	case KindProfileImage:
+		return Policy{MaxSize: limits.ProfileImage, AllowedTypes: photoTypes}, nil
+	case KindPublicationCover:
+		return Policy{MaxSize: limits.PublicationCover, AllowedTypes: photoTypes}, nil
+	case KindPublicationFile:
+		allowed := append(append([]string{}, documentTypes...), imageTypes...)
+		return Policy{MaxSize: limits.PublicationFile, AllowedTypes: allowed}, nil
+	case KindPublicationVideo:
+		return Policy{MaxSize: limits.PublicationVideo, AllowedTypes: videoTypes}, nil
+	default:
+		return Policy{}, ErrUnknownKind
+	}
+}
+
+// Processed reports whether uploads of the kind are images that are
+// re-encoded without metadata and resized into variants before storing.
+func Processed(kind Kind) bool {
+	return kind == KindProfileImage || kind == KindPublicationCover
+}
+
+// Check validates the size and the sniffed content type of a file.
+func (p Policy) Check(size int64, contentType string) error {
+	if size > p.MaxSize {
+		return ErrTooLarge
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ErrUnsupportedType
+	}
+
+	for _, allowed := range p.AllowedTypes {
+		if mediaType == allowed {
+			return nil
+		}
+	}
+
+	return ErrUnsupportedType
+}
+
+// CacheControl returns the Cache-Control header for files of the kind.
+// The content of a file id never changes, so images may be cached for
+// good; publication files are revalidated on every request so downloads
+// are counted and access is checked. Unknown kinds are not cached.
+func CacheControl(kind Kind) string {
+	switch kind {
+	case KindProfileImage, KindPublicationCover:
+		return "public, max-age=31536000, immutable"
+	case KindPublicationFile, KindPublicationVideo:
+		return "private, no-cache"
+	default:
+		return "no-cache"
+	}
+}
+
+// Access says who may fetch the files of a kind.
+type Access int
+
+const (
+	// AccessPublic files are served to anyone who knows the file id.
+	AccessPublic Access = iota
+	// AccessSignedLink files are only served through a signed download
+	// link, or to their owner and admins.
+	AccessSignedLink
+)
+
+// AccessFor returns the access policy of the kind. Images are public so
+// covers and avatars can be embedded; everything else needs a signed link.
+func AccessFor(kind Kind) Access {
+	switch kind {
+	case KindProfileImage, KindPublicationCover:
+		return AccessPublic
+	default:
+		return AccessSignedLink
+	}
+}
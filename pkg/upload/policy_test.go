@@ -0,0 +1,110 @@
+package upload
+
+import (
+	"errors"
+	"testing"
+
+	"app/pkg/helper"
+)
+
+var testLimits = Limits{
+	ProfileImage:     1 << 20,
+	PublicationCover: 2 << 20,
+	PublicationFile:  10 << 20,
+	PublicationVideo: 100 << 20,
+}
+
+func TestPolicyCheck(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		kind        Kind
+		size        int64
+		contentType string
+		err         error
+	}{
+		{"profile jpeg", KindProfileImage, 1000, "image/jpeg", nil},
+		{"profile at the limit", KindProfileImage, 1 << 20, "image/png", nil},
+		{"profile over the limit", KindProfileImage, 1<<20 + 1, "image/png", ErrTooLarge},
+		{"profile webp is not re-encodable", KindProfileImage, 1000, "image/webp", ErrUnsupportedType},
+		{"cover pdf", KindPublicationCover, 1000, "application/pdf", ErrUnsupportedType},
+		{"file pdf", KindPublicationFile, 1000, "application/pdf", nil},
+		{"file webp", KindPublicationFile, 1000, "image/webp", nil},
+		{"file text with charset", KindPublicationFile, 1000, "text/plain; charset=utf-8", nil},
+		{"file html", KindPublicationFile, 1000, "text/html; charset=utf-8", ErrUnsupportedType},
+		{"file docx", KindPublicationFile, 1000, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", nil},
+		{"video mp4", KindPublicationVideo, 50 << 20, "video/mp4", nil},
+		{"video quicktime", KindPublicationVideo, 1000, "video/quicktime", nil},
+		{"video as document", KindPublicationFile, 1000, "video/mp4", ErrUnsupportedType},
+		{"malformed type", KindPublicationFile, 1000, "not a type;;", ErrUnsupportedType},
+		{"size checked first", KindPublicationVideo, 100<<20 + 1, "text/html", ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := PolicyFor(tt.kind, testLimits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := policy.Check(tt.size, tt.contentType); !errors.Is(err, tt.err) {
+				t.Errorf("Check(%d, %q) = %v, want %v", tt.size, tt.contentType, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPolicyForUnknownKind(t *testing.T) {
+	if _, err := PolicyFor("avatar", testLimits); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("PolicyFor(avatar) = %v, want %v", err, ErrUnknownKind)
+	}
+}
+
+func TestPolicySniffedVideo(t *testing.T) {
+
+	// The policy sees the sniffed type, so each accepted video format has to
+	// be recognized from its first bytes
+	tests := []struct {
+		name string
+		head []byte
+	}{
+		{"mp4", []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom")},
+		{"quicktime", []byte("\x00\x00\x00\x14ftypqt  \x00\x00\x02\x00qt  ")},
+		{"webm", []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x84webm")},
+	}
+
+	policy, err := PolicyFor(KindPublicationVideo, testLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType := helper.DetectContentType(tt.head, "upload")
+			if err := policy.Check(int64(len(tt.head)), contentType); err != nil {
+				t.Errorf("sniffed %q: %v", contentType, err)
+			}
+		})
+	}
+}
+
+func TestAccessFor(t *testing.T) {
+
+	tests := []struct {
+		kind Kind
+		want Access
+	}{
+		{KindProfileImage, AccessPublic},
+		{KindPublicationCover, AccessPublic},
+		{KindPublicationFile, AccessSignedLink},
+		{KindPublicationVideo, AccessSignedLink},
+		// Files with no known kind, such as unregistered legacy blobs
+		{"", AccessSignedLink},
+	}
+
+	for _, tt := range tests {
+		if got := AccessFor(tt.kind); got != tt.want {
+			t.Errorf("AccessFor(%q) = %v, want %v", tt.kind, got, tt.want)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	uuid "github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -96,14 +95,19 @@ func (r *adminRepo) GetByID(ctx context.Context, req *models.AdminPrimaryKey) (*
 	}, nil
 }
 
+// adminSortFields are the sort_by values accepted by GetList.
+var adminSortFields = map[string]string{
+	"email":      "email",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
 func (r *adminRepo) GetList(ctx context.Context, req *models.AdminGetListRequest) (*models.AdminGetListResponse, error) {
 
 	var (
-		resp   = &models.AdminGetListResponse{}
-		query  string
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
+		resp  = &models.AdminGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
 	)
 
 	query = `
@@ -117,24 +121,22 @@ func (r *adminRepo) GetList(ctx context.Context, req *models.AdminGetListRequest
 		FROM admins
 	`
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
+	qb.Search(req.Search, "email").
+		DateRange("created_at", req.DateFrom, req.DateTo).
+		OrderBy(req.SortBy, req.Order, adminSortFields, "created_at").
+		Paginate(req.Offset, req.Limit)
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
+	query, args := qb.Build(query)
 
-	if req.Search != "" {
-		where += ` AND email ILIKE '%' || '` + req.Search + `' || '%'`
+	if err := qb.Err(); err != nil {
+		return nil, err
 	}
 
-	query += where + offset + limit
-
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -167,6 +169,10 @@ func (r *adminRepo) GetList(ctx context.Context, req *models.AdminGetListRequest
 		})
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
@@ -183,7 +189,7 @@ func (r *adminRepo) Update(ctx context.Context, req *models.UpdateAdmin) (int64,
 		SET
 		id = :id,
 		email = :email,
-		password = :password,
+		password = COALESCE(NULLIF(:password, ''), password),
 			updated_at = NOW()
 		WHERE id = :id
 	`
@@ -213,3 +219,13 @@ func (r *adminRepo) Delete(ctx context.Context, req *models.AdminPrimaryKey) err
 
 	return nil
 }
+
+func (r *adminRepo) UpdatePassword(ctx context.Context, id string, password string) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "UPDATE admins SET password = $2, updated_at = NOW() WHERE id = $1", id, password)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
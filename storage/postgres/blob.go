@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+type blobRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewBlobRepo(db *pgxpool.Pool) *blobRepo {
+	return &blobRepo{
+		db: db,
+	}
+}
+
+// Acquire takes a reference on the blob with the hash, creating the row
+// when the content is new. When the content is already known the stored
+// row wins, so the returned key may differ from req.StorageKey.
+func (r *blobRepo) Acquire(ctx context.Context, req *models.CreateBlob) (*models.Blob, error) {
+
+	query := `
+		INSERT INTO blobs(sha256, storage_key, size, mime_type, ref_count)
+		VALUES ($1, $2, $3, $4, 1)
+		ON CONFLICT (sha256) DO UPDATE
+		SET ref_count = blobs.ref_count + 1, updated_at = NOW()
+		RETURNING sha256, storage_key, size, mime_type, ref_count, created_at, updated_at
+	`
+
+	return scanBlob(r.db.QueryRow(ctx, query,
+		req.Sha256,
+		req.StorageKey,
+		req.Size,
+		req.MimeType,
+	))
+}
+
+// Release drops one reference. Blobs left without references are removed
+// from storage by the garbage collector, not here.
+func (r *blobRepo) Release(ctx context.Context, req *models.BlobPrimaryKey) error {
+
+	_, err := r.db.Exec(ctx,
+		"UPDATE blobs SET ref_count = GREATEST(ref_count - 1, 0), updated_at = NOW() WHERE sha256 = $1",
+		req.Sha256,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *blobRepo) GetByID(ctx context.Context, req *models.BlobPrimaryKey) (*models.Blob, error) {
+
+	query := `
+		SELECT sha256, storage_key, size, mime_type, ref_count, created_at, updated_at
+		FROM blobs
+		WHERE sha256 = $1
+	`
+
+	return scanBlob(r.db.QueryRow(ctx, query, req.Sha256))
+}
+
+func scanBlob(row pgx.Row) (*models.Blob, error) {
+
+	var (
+		sha256     sql.NullString
+		storageKey sql.NullString
+		size       sql.NullInt64
+		mimeType   sql.NullString
+		refCount   sql.NullInt64
+		createdAt  sql.NullString
+		updatedAt  sql.NullString
+	)
+
+	err := row.Scan(
+		&sha256,
+		&storageKey,
+		&size,
+		&mimeType,
+		&refCount,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Blob{
+		Sha256:     sha256.String,
+		StorageKey: storageKey.String,
+		Size:       size.Int64,
+		MimeType:   mimeType.String,
+		RefCount:   int(refCount.Int64),
+		CreatedAt:  createdAt.String,
+		UpdatedAt:  updatedAt.String,
+	}, nil
+}
+
+// orphanBlobCondition matches blobs without references whose count last
+// changed more than $1 seconds ago. The files and variants are checked
+// too, so a drifted ref_count never frees content in use.
+const orphanBlobCondition = `
+	b.ref_count = 0
+	AND b.updated_at < NOW() - make_interval(secs => $1)
+	AND NOT EXISTS (SELECT 1 FROM files f WHERE f.sha256 = b.sha256 OR f.storage_key = b.storage_key)
+	AND NOT EXISTS (SELECT 1 FROM file_variants v WHERE v.sha256 = b.sha256)
+`
+
+// GetOrphans lists unreferenced blobs, the longest unused first.
+func (r *blobRepo) GetOrphans(ctx context.Context, req *models.OrphanRequest) ([]*models.Blob, error) {
+
+	query := `
+		SELECT b.sha256, b.storage_key, b.size, b.mime_type, b.ref_count, b.created_at, b.updated_at
+		FROM blobs b
+		WHERE ` + orphanBlobCondition + `
+		ORDER BY b.updated_at
+		OFFSET $2 LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, req.OlderThan, req.Offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var resp []*models.Blob
+	for rows.Next() {
+		blob, err := scanBlob(rows)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, blob)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// DeleteOrphan deletes the blob if it is still unreferenced. remove deletes
+// the stored content and runs while the row is locked: a concurrent
+// Acquire waits for it, then recreates the row and sees the content gone.
+// It returns false when the blob was in use again or already deleted.
+func (r *blobRepo) DeleteOrphan(ctx context.Context, req *models.BlobPrimaryKey, olderThan int64, remove func(*models.Blob) error) (bool, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT b.sha256, b.storage_key, b.size, b.mime_type, b.ref_count, b.created_at, b.updated_at
+		FROM blobs b
+		WHERE b.sha256 = $2 AND ` + orphanBlobCondition + `
+		FOR UPDATE
+	`
+
+	blob, err := scanBlob(tx.QueryRow(ctx, query, olderThan, req.Sha256))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return false, nil
+		}
+		return false, err
+	}
+
+	err = remove(blob)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM blobs WHERE sha256 = $1", blob.Sha256)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetUnknownKeys returns the storage keys nothing in the database refers
+// to: no blob, file, variant, upload chunk or legacy profile image name.
+func (r *blobRepo) GetUnknownKeys(ctx context.Context, keys []string) ([]string, error) {
+
+	query := `
+		SELECT k.key
+		FROM unnest($1::text[]) AS k(key)
+		WHERE NOT EXISTS (SELECT 1 FROM blobs b WHERE b.storage_key = k.key)
+			AND NOT EXISTS (SELECT 1 FROM files f WHERE f.storage_key = k.key)
+			AND NOT EXISTS (SELECT 1 FROM file_variants v WHERE v.storage_key = k.key)
+			AND NOT EXISTS (SELECT 1 FROM upload_chunks c WHERE c.storage_key = k.key)
+			AND NOT EXISTS (
+				SELECT 1 FROM users u
+				WHERE u.profile_image <> '' AND k.key IN (u.profile_image, 'profile_images/' || u.profile_image)
+			)
+	`
+
+	rows, err := r.db.Query(ctx, query, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var resp []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		resp = append(resp, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
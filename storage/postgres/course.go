@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	uuid "github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -66,7 +65,7 @@ func (r *courseRepo) GetByID(ctx context.Context, req *models.CoursePrimaryKey)
 			created_at,
 			updated_at
 		FROM courses
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	err := r.db.QueryRow(ctx, query, req.Id).Scan(
@@ -90,14 +89,19 @@ func (r *courseRepo) GetByID(ctx context.Context, req *models.CoursePrimaryKey)
 	}, nil
 }
 
+// courseSortFields are the sort_by values accepted by GetList.
+var courseSortFields = map[string]string{
+	"course_title": "course_title",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+}
+
 func (r *courseRepo) GetList(ctx context.Context, req *models.CourseGetListRequest) (*models.CourseGetListResponse, error) {
 
 	var (
-		resp   = &models.CourseGetListResponse{}
-		query  string
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
+		resp  = &models.CourseGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
 	)
 
 	query = `
@@ -111,24 +115,24 @@ func (r *courseRepo) GetList(ctx context.Context, req *models.CourseGetListReque
 		FROM courses
 	`
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
+	qb.Where("deleted_at IS NULL").
+		Search(req.Search, "course_title").
+		Equal("semester_id", req.SemesterID).
+		DateRange("created_at", req.DateFrom, req.DateTo).
+		OrderBy(req.SortBy, req.Order, courseSortFields, "created_at").
+		Paginate(req.Offset, req.Limit)
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
+	query, args := qb.Build(query)
 
-	if req.Search != "" {
-		where += ` AND course_title ILIKE '%' || '` + req.Search + `' || '%'`
+	if err := qb.Err(); err != nil {
+		return nil, err
 	}
 
-	query += where + offset + limit
-
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -161,6 +165,10 @@ func (r *courseRepo) GetList(ctx context.Context, req *models.CourseGetListReque
 		})
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
@@ -178,7 +186,7 @@ func (r *courseRepo) Update(ctx context.Context, req *models.UpdateCourse) (int6
 			course_title = :course_title,
 			semester_id = :semester_id,
 			updated_at = NOW()
-		WHERE id = :id
+		WHERE id = :id AND deleted_at IS NULL
 	`
 
 	params = map[string]interface{}{
@@ -197,12 +205,29 @@ func (r *courseRepo) Update(ctx context.Context, req *models.UpdateCourse) (int6
 	return result.RowsAffected(), nil
 }
 
+// Delete moves a course to the trash with its publications, see
+// semesterRepo.Delete.
 func (r *courseRepo) Delete(ctx context.Context, req *models.CoursePrimaryKey) error {
 
-	_, err := r.db.Exec(ctx, "DELETE FROM courses WHERE id = $1", req.Id)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, "UPDATE courses SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", req.Id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() <= 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE publications SET deleted_at = NOW() WHERE course_id = $1 AND deleted_at IS NULL", req.Id)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
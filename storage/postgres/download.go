@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	uuid "github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -22,29 +21,54 @@ func NewDownloadRepo(db *pgxpool.Pool) *downloadRepo {
 	}
 }
 
-func (r *downloadRepo) Create(ctx context.Context, req *models.CreateDownload) (string, error) {
-	var (
-		id    = uuid.New().String()
-		query string
-	)
+// Create records a download event. It reports false and records nothing
+// when the same client downloaded the publication within the last
+// DedupeWindow seconds. Downloads of one client and publication take an
+// advisory lock first, so two concurrent ones can't both miss each other.
+// Every download counts when DedupeWindow is 0.
+func (r *downloadRepo) Create(ctx context.Context, req *models.CreateDownload) (bool, error) {
 
-	query = `
-		INSERT INTO downloads(id, count, publication_id, contributor_id, updated_at)
-		VALUES ($1, $2, $3, $4, NOW())
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1::text))", req.PublicationID+":"+req.ClientHash)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO downloads(id, publication_id, contributor_id, user_agent_hash, client_hash, updated_at)
+		SELECT $1, $2, $3, $4, $5, NOW()
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM downloads
+			WHERE publication_id = $2
+				AND client_hash = $5
+				AND created_at > NOW() - make_interval(secs => $6)
+		)
 	`
 
-	_, err := r.db.Exec(ctx, query,
-		id,
-		req.Count,
+	result, err := tx.Exec(ctx, query,
+		uuid.New().String(),
 		req.PublicationID,
-		req.ContributorID,
+		helper.NewNullString(req.ContributorID),
+		req.UserAgentHash,
+		req.ClientHash,
+		req.DedupeWindow,
 	)
+	if err != nil {
+		return false, err
+	}
 
+	err = tx.Commit(ctx)
 	if err != nil {
-		return "", err
+		return false, err
 	}
 
-	return id, nil
+	return result.RowsAffected() > 0, nil
 }
 
 func (r *downloadRepo) GetByID(ctx context.Context, req *models.DownloadPrimaryKey) (*models.Download, error) {
@@ -53,7 +77,6 @@ func (r *downloadRepo) GetByID(ctx context.Context, req *models.DownloadPrimaryK
 		query string
 
 		id            sql.NullString
-		count         sql.NullFloat64
 		publicationID sql.NullString
 		contributorID sql.NullString
 		createdAt     sql.NullString
@@ -63,7 +86,6 @@ func (r *downloadRepo) GetByID(ctx context.Context, req *models.DownloadPrimaryK
 	query = `
 		SELECT
 			id,
-			count,
 			publication_id,
 			contributor_id,
 			created_at,
@@ -74,7 +96,6 @@ func (r *downloadRepo) GetByID(ctx context.Context, req *models.DownloadPrimaryK
 
 	err := r.db.QueryRow(ctx, query, req.Id).Scan(
 		&id,
-		&count,
 		&publicationID,
 		&contributorID,
 		&createdAt,
@@ -87,7 +108,6 @@ func (r *downloadRepo) GetByID(ctx context.Context, req *models.DownloadPrimaryK
 
 	return &models.Download{
 		Id:            id.String,
-		Count:         count.Float64,
 		PublicationID: publicationID.String,
 		ContributorID: contributorID.String,
 		CreatedAt:     createdAt.String,
@@ -95,21 +115,24 @@ func (r *downloadRepo) GetByID(ctx context.Context, req *models.DownloadPrimaryK
 	}, nil
 }
 
+// downloadSortFields are the sort_by values accepted by GetList.
+var downloadSortFields = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
 func (r *downloadRepo) GetList(ctx context.Context, req *models.DownloadGetListRequest) (*models.DownloadGetListResponse, error) {
 
 	var (
-		resp   = &models.DownloadGetListResponse{}
-		query  string
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
+		resp  = &models.DownloadGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
 	)
 
 	query = `
 		SELECT
 			COUNT(*) OVER(),
 			id,
-			count,
 			publication_id,
 			contributor_id,
 			created_at,
@@ -117,29 +140,28 @@ func (r *downloadRepo) GetList(ctx context.Context, req *models.DownloadGetListR
 		FROM downloads
 	`
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
+	qb.Search(req.Search, "publication_id").
+		Equal("publication_id", req.PublicationID).
+		Equal("contributor_id", req.ContributorID).
+		DateRange("created_at", req.DateFrom, req.DateTo).
+		OrderBy(req.SortBy, req.Order, downloadSortFields, "created_at").
+		Paginate(req.Offset, req.Limit)
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
+	query, args := qb.Build(query)
 
-	if req.Search != "" {
-		where += ` AND publication_id ILIKE '%' || '` + req.Search + `' || '%'`
+	if err := qb.Err(); err != nil {
+		return nil, err
 	}
 
-	query += where + offset + limit
-
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
 			id            sql.NullString
-			count         sql.NullFloat64
 			publicationID sql.NullString
 			contributorID sql.NullString
 			createdAt     sql.NullString
@@ -149,7 +171,6 @@ func (r *downloadRepo) GetList(ctx context.Context, req *models.DownloadGetListR
 		err := rows.Scan(
 			&resp.Count,
 			&id,
-			&count,
 			&publicationID,
 			&contributorID,
 			&createdAt,
@@ -162,7 +183,6 @@ func (r *downloadRepo) GetList(ctx context.Context, req *models.DownloadGetListR
 
 		resp.Downloads = append(resp.Downloads, &models.Download{
 			Id:            id.String,
-			Count:         count.Float64,
 			PublicationID: publicationID.String,
 			ContributorID: contributorID.String,
 			CreatedAt:     createdAt.String,
@@ -170,36 +190,27 @@ func (r *downloadRepo) GetList(ctx context.Context, req *models.DownloadGetListR
 		})
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
-func (r *downloadRepo) Update(ctx context.Context, req *models.UpdateDownload) (int64, error) {
+// Delete removes the download record, when req.OwnerID is set only if it
+// is a download of that user.
+func (r *downloadRepo) Delete(ctx context.Context, req *models.DownloadPrimaryKey) (int64, error) {
 
 	var (
-		query  string
-		params map[string]interface{}
+		query = "DELETE FROM downloads WHERE id = $1"
+		args  = []interface{}{req.Id}
 	)
 
-	query = `
-		UPDATE
-			downloads
-		SET
-			count = :count,
-			publication_id = :publication_id,
-			contributor_id = :contributor_id,
-			updated_at = NOW()
-		WHERE id = :id
-	`
-
-	params = map[string]interface{}{
-		"id":             req.Id,
-		"count":          req.Count,
-		"publication_id": req.PublicationID,
-		"contributor_id": req.ContributorID,
+	if len(req.OwnerID) > 0 {
+		query += " AND contributor_id = $2"
+		args = append(args, req.OwnerID)
 	}
 
-	query, args := helper.ReplaceQueryParams(query, params)
-
 	result, err := r.db.Exec(ctx, query, args...)
 	if err != nil {
 		return 0, err
@@ -207,13 +218,3 @@ func (r *downloadRepo) Update(ctx context.Context, req *models.UpdateDownload) (
 
 	return result.RowsAffected(), nil
 }
-
-func (r *downloadRepo) Delete(ctx context.Context, req *models.DownloadPrimaryKey) error {
-
-	_, err := r.db.Exec(ctx, "DELETE FROM downloads WHERE id = $1", req.Id)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
@@ -0,0 +1,485 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+	"app/pkg/helper"
+)
+
+type fileRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewFileRepo(db *pgxpool.Pool) *fileRepo {
+	return &fileRepo{
+		db: db,
+	}
+}
+
+// Create registers a stored upload. The id is chosen by the caller because
+// it is also part of the storage key.
+func (r *fileRepo) Create(ctx context.Context, req *models.CreateFile) (string, error) {
+
+	query := `
+		INSERT INTO files(id, owner_id, kind, original_name, size, mime_type, sha256, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		req.Id,
+		helper.NewNullString(req.OwnerID),
+		req.Kind,
+		req.OriginalName,
+		req.Size,
+		req.MimeType,
+		helper.NewNullString(req.Sha256),
+		req.StorageKey,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return req.Id, nil
+}
+
+func (r *fileRepo) GetByID(ctx context.Context, req *models.FilePrimaryKey) (*models.File, error) {
+
+	var (
+		query string
+
+		id           sql.NullString
+		ownerID      sql.NullString
+		kind         sql.NullString
+		originalName sql.NullString
+		size         sql.NullInt64
+		mimeType     sql.NullString
+		sha256       sql.NullString
+		storageKey   sql.NullString
+		createdAt    sql.NullString
+	)
+
+	var whereField = "id"
+	if len(req.StorageKey) > 0 {
+		whereField = "storage_key"
+		req.Id = req.StorageKey
+	}
+
+	query = `
+		SELECT
+			id,
+			owner_id,
+			kind,
+			original_name,
+			size,
+			mime_type,
+			sha256,
+			storage_key,
+			created_at
+		FROM files
+		WHERE ` + whereField + ` = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&id,
+		&ownerID,
+		&kind,
+		&originalName,
+		&size,
+		&mimeType,
+		&sha256,
+		&storageKey,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.File{
+		Id:           id.String,
+		OwnerID:      ownerID.String,
+		Kind:         kind.String,
+		OriginalName: originalName.String,
+		Size:         size.Int64,
+		MimeType:     mimeType.String,
+		Sha256:       sha256.String,
+		StorageKey:   storageKey.String,
+		CreatedAt:    createdAt.String,
+	}, nil
+}
+
+// fileSortFields are the sort_by values accepted by GetList.
+var fileSortFields = map[string]string{
+	"created_at": "created_at",
+}
+
+func (r *fileRepo) GetList(ctx context.Context, req *models.FileGetListRequest) (*models.FileGetListResponse, error) {
+
+	var (
+		resp  = &models.FileGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			owner_id,
+			kind,
+			original_name,
+			size,
+			mime_type,
+			sha256,
+			storage_key,
+			created_at
+		FROM files
+	`
+
+	qb.Equal("owner_id", req.OwnerID).
+		Equal("kind", req.Kind).
+		OrderBy("", "", fileSortFields, "created_at").
+		Paginate(req.Offset, req.Limit)
+
+	query, args := qb.Build(query)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id           sql.NullString
+			ownerID      sql.NullString
+			kind         sql.NullString
+			originalName sql.NullString
+			size         sql.NullInt64
+			mimeType     sql.NullString
+			sha256       sql.NullString
+			storageKey   sql.NullString
+			createdAt    sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&id,
+			&ownerID,
+			&kind,
+			&originalName,
+			&size,
+			&mimeType,
+			&sha256,
+			&storageKey,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Files = append(resp.Files, &models.File{
+			Id:           id.String,
+			OwnerID:      ownerID.String,
+			Kind:         kind.String,
+			OriginalName: originalName.String,
+			Size:         size.Int64,
+			MimeType:     mimeType.String,
+			Sha256:       sha256.String,
+			StorageKey:   storageKey.String,
+			CreatedAt:    createdAt.String,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// CreateVariant registers a rendition of a file. It returns 0 when the
+// variant already exists, e.g. when two requests rendered it at once.
+func (r *fileRepo) CreateVariant(ctx context.Context, req *models.CreateFileVariant) (int64, error) {
+
+	query := `
+		INSERT INTO file_variants(file_id, variant, sha256, storage_key, size, mime_type, width, height)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (file_id, variant) DO NOTHING
+	`
+
+	result, err := r.db.Exec(ctx, query,
+		req.FileID,
+		req.Variant,
+		req.Sha256,
+		req.StorageKey,
+		req.Size,
+		req.MimeType,
+		req.Width,
+		req.Height,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *fileRepo) GetVariant(ctx context.Context, req *models.FileVariantPrimaryKey) (*models.FileVariant, error) {
+
+	var (
+		query string
+
+		fileID     sql.NullString
+		variant    sql.NullString
+		sha256     sql.NullString
+		storageKey sql.NullString
+		size       sql.NullInt64
+		mimeType   sql.NullString
+		width      sql.NullInt64
+		height     sql.NullInt64
+		createdAt  sql.NullString
+	)
+
+	query = `
+		SELECT
+			file_id,
+			variant,
+			sha256,
+			storage_key,
+			size,
+			mime_type,
+			width,
+			height,
+			created_at
+		FROM file_variants
+		WHERE file_id = $1 AND variant = $2
+	`
+
+	err := r.db.QueryRow(ctx, query, req.FileID, req.Variant).Scan(
+		&fileID,
+		&variant,
+		&sha256,
+		&storageKey,
+		&size,
+		&mimeType,
+		&width,
+		&height,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.FileVariant{
+		FileID:     fileID.String,
+		Variant:    variant.String,
+		Sha256:     sha256.String,
+		StorageKey: storageKey.String,
+		Size:       size.Int64,
+		MimeType:   mimeType.String,
+		Width:      int(width.Int64),
+		Height:     int(height.Int64),
+		CreatedAt:  createdAt.String,
+	}, nil
+}
+
+// orphanFileCondition matches files older than $1 seconds that nothing
+// refers to: no publication or revision of one, no user profile image
+// (stored as the id, the "<id>.<ext>" name or the storage key) and no live
+// upload session.
+const orphanFileCondition = `
+	f.created_at < NOW() - make_interval(secs => $1)
+	AND NOT EXISTS (SELECT 1 FROM publications p WHERE p.image_id = f.id OR p.file_id = f.id)
+	AND NOT EXISTS (
+		SELECT 1 FROM publication_revisions v
+		WHERE v.image_id = f.id OR v.file_id = f.id OR v.previous_file_id = f.id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM users u
+		WHERE split_part(u.profile_image, '.', 1) = f.id::text OR u.profile_image = f.storage_key
+	)
+	AND NOT EXISTS (SELECT 1 FROM upload_sessions s WHERE s.file_id = f.id AND s.expires_at > NOW())
+`
+
+// GetOrphans lists unreferenced files, oldest first.
+func (r *fileRepo) GetOrphans(ctx context.Context, req *models.OrphanRequest) ([]*models.File, error) {
+
+	query := `
+		SELECT f.id, f.owner_id, f.kind, f.original_name, f.size, f.mime_type, f.sha256, f.storage_key, f.created_at
+		FROM files f
+		WHERE ` + orphanFileCondition + `
+		ORDER BY f.created_at
+		OFFSET $2 LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, req.OlderThan, req.Offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var resp []*models.File
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, file)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// DeleteOrphans deletes up to req.Limit unreferenced files with their
+// variants in one transaction. The references they held on blobs and the
+// quota they used are given back. Files a concurrent request is attaching
+// are locked and skipped.
+func (r *fileRepo) DeleteOrphans(ctx context.Context, req *models.OrphanRequest) ([]*models.File, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT f.id
+		FROM files f
+		WHERE ` + orphanFileCondition + `
+		ORDER BY f.created_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := tx.Query(ctx, query, req.OlderThan, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var resp []*models.File
+	for _, id := range ids {
+
+		// Collect the variant hashes before the cascade drops them
+		var shas []string
+
+		rows, err := tx.Query(ctx, "DELETE FROM file_variants WHERE file_id = $1 RETURNING sha256", id)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var sha string
+			if err := rows.Scan(&sha); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			shas = append(shas, sha)
+		}
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+
+		file, err := scanFile(tx.QueryRow(ctx, `
+			DELETE FROM files f
+			WHERE f.id = $1
+			RETURNING f.id, f.owner_id, f.kind, f.original_name, f.size, f.mime_type, f.sha256, f.storage_key, f.created_at
+		`, id))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(file.Sha256) > 0 {
+			shas = append(shas, file.Sha256)
+		}
+
+		for _, sha := range shas {
+			_, err = tx.Exec(ctx, "UPDATE blobs SET ref_count = GREATEST(ref_count - 1, 0), updated_at = NOW() WHERE sha256 = $1", sha)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if len(file.OwnerID) > 0 {
+			_, err = tx.Exec(ctx, "UPDATE users SET storage_used = GREATEST(storage_used - $2, 0) WHERE id = $1", file.OwnerID, file.Size)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		resp = append(resp, file)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func scanFile(row pgx.Row) (*models.File, error) {
+
+	var (
+		id           sql.NullString
+		ownerID      sql.NullString
+		kind         sql.NullString
+		originalName sql.NullString
+		size         sql.NullInt64
+		mimeType     sql.NullString
+		sha256       sql.NullString
+		storageKey   sql.NullString
+		createdAt    sql.NullString
+	)
+
+	err := row.Scan(
+		&id,
+		&ownerID,
+		&kind,
+		&originalName,
+		&size,
+		&mimeType,
+		&sha256,
+		&storageKey,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.File{
+		Id:           id.String,
+		OwnerID:      ownerID.String,
+		Kind:         kind.String,
+		OriginalName: originalName.String,
+		Size:         size.Int64,
+		MimeType:     mimeType.String,
+		Sha256:       sha256.String,
+		StorageKey:   storageKey.String,
+		CreatedAt:    createdAt.String,
+	}, nil
+}
@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	uuid "github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -22,29 +21,27 @@ func NewLikeRepo(db *pgxpool.Pool) *likeRepo {
 	}
 }
 
-func (r *likeRepo) Create(ctx context.Context, req *models.CreateLike) (string, error) {
-	var (
-		id    = uuid.New().String()
-		query string
-	)
+// Create likes the publication on behalf of the user. It reports false
+// when the like already existed, so repeating the request changes nothing.
+func (r *likeRepo) Create(ctx context.Context, req *models.LikeKey) (bool, error) {
 
-	query = `
-		INSERT INTO likes(id, count, publication_id, contributor_id, updated_at)
-		VALUES ($1, $2, $3, $4, NOW())
+	query := `
+		INSERT INTO likes(id, publication_id, contributor_id, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (publication_id, contributor_id) DO NOTHING
 	`
 
-	_, err := r.db.Exec(ctx, query,
-		id,
-		req.Count,
+	result, err := r.db.Exec(ctx, query,
+		uuid.New().String(),
 		req.PublicationID,
 		req.ContributorID,
 	)
 
 	if err != nil {
-		return "", err
+		return false, err
 	}
 
-	return id, nil
+	return result.RowsAffected() > 0, nil
 }
 
 func (r *likeRepo) GetByID(ctx context.Context, req *models.LikePrimaryKey) (*models.Like, error) {
@@ -53,7 +50,6 @@ func (r *likeRepo) GetByID(ctx context.Context, req *models.LikePrimaryKey) (*mo
 		query string
 
 		id            sql.NullString
-		count         sql.NullFloat64
 		publicationID sql.NullString
 		contributorID sql.NullString
 		createdAt     sql.NullString
@@ -63,7 +59,6 @@ func (r *likeRepo) GetByID(ctx context.Context, req *models.LikePrimaryKey) (*mo
 	query = `
 		SELECT
 			id,
-			count,
 			publication_id,
 			contributor_id,
 			created_at,
@@ -74,7 +69,6 @@ func (r *likeRepo) GetByID(ctx context.Context, req *models.LikePrimaryKey) (*mo
 
 	err := r.db.QueryRow(ctx, query, req.Id).Scan(
 		&id,
-		&count,
 		&publicationID,
 		&contributorID,
 		&createdAt,
@@ -87,7 +81,6 @@ func (r *likeRepo) GetByID(ctx context.Context, req *models.LikePrimaryKey) (*mo
 
 	return &models.Like{
 		Id:            id.String,
-		Count:         count.Float64,
 		PublicationID: publicationID.String,
 		ContributorID: contributorID.String,
 		CreatedAt:     createdAt.String,
@@ -95,21 +88,24 @@ func (r *likeRepo) GetByID(ctx context.Context, req *models.LikePrimaryKey) (*mo
 	}, nil
 }
 
+// likeSortFields are the sort_by values accepted by GetList.
+var likeSortFields = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
 func (r *likeRepo) GetList(ctx context.Context, req *models.LikeGetListRequest) (*models.LikeGetListResponse, error) {
 
 	var (
-		resp   = &models.LikeGetListResponse{}
-		query  string
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
+		resp  = &models.LikeGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
 	)
 
 	query = `
 		SELECT
 			COUNT(*) OVER(),
 			id,
-			count,
 			publication_id,
 			contributor_id,
 			created_at,
@@ -117,29 +113,28 @@ func (r *likeRepo) GetList(ctx context.Context, req *models.LikeGetListRequest)
 		FROM likes
 	`
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
+	qb.Search(req.Search, "publication_id").
+		Equal("publication_id", req.PublicationID).
+		Equal("contributor_id", req.ContributorID).
+		DateRange("created_at", req.DateFrom, req.DateTo).
+		OrderBy(req.SortBy, req.Order, likeSortFields, "created_at").
+		Paginate(req.Offset, req.Limit)
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
+	query, args := qb.Build(query)
 
-	if req.Search != "" {
-		where += ` AND publication_id ILIKE '%' || '` + req.Search + `' || '%'`
+	if err := qb.Err(); err != nil {
+		return nil, err
 	}
 
-	query += where + offset + limit
-
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
 			id            sql.NullString
-			count         sql.NullFloat64
 			publicationID sql.NullString
 			contributorID sql.NullString
 			createdAt     sql.NullString
@@ -149,7 +144,6 @@ func (r *likeRepo) GetList(ctx context.Context, req *models.LikeGetListRequest)
 		err := rows.Scan(
 			&resp.Count,
 			&id,
-			&count,
 			&publicationID,
 			&contributorID,
 			&createdAt,
@@ -162,7 +156,6 @@ func (r *likeRepo) GetList(ctx context.Context, req *models.LikeGetListRequest)
 
 		resp.Likes = append(resp.Likes, &models.Like{
 			Id:            id.String,
-			Count:         count.Float64,
 			PublicationID: publicationID.String,
 			ContributorID: contributorID.String,
 			CreatedAt:     createdAt.String,
@@ -170,37 +163,39 @@ func (r *likeRepo) GetList(ctx context.Context, req *models.LikeGetListRequest)
 		})
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
-func (r *likeRepo) Update(ctx context.Context, req *models.UpdateLike) (int64, error) {
+// Delete removes the like. With req.OwnerID set it only matches a like of
+// that user, so it affects no rows for anyone else's.
+func (r *likeRepo) Delete(ctx context.Context, req *models.LikePrimaryKey) (int64, error) {
 
 	var (
-		query  string
-		params map[string]interface{}
+		query = "DELETE FROM likes WHERE id = $1"
+		args  = []interface{}{req.Id}
 	)
 
-	query = `
-		UPDATE
-			likes
-		SET
-			count = :count,
-			publication_id = :publication_id,
-			contributor_id = :contributor_id,
-			updated_at = NOW()
-		WHERE id = :id
-	`
+	if len(req.OwnerID) > 0 {
+		query += " AND contributor_id = $2"
+		args = append(args, req.OwnerID)
+	}
 
-	params = map[string]interface{}{
-		"id":             req.Id,
-		"count":          req.Count,
-		"publication_id": req.PublicationID,
-		"contributor_id": req.ContributorID,
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
 	}
 
-	query, args := helper.ReplaceQueryParams(query, params)
+	return result.RowsAffected(), nil
+}
+
+// DeleteByKey removes the like of the user on the publication, if any.
+func (r *likeRepo) DeleteByKey(ctx context.Context, req *models.LikeKey) (int64, error) {
 
-	result, err := r.db.Exec(ctx, query, args...)
+	result, err := r.db.Exec(ctx, "DELETE FROM likes WHERE publication_id = $1 AND contributor_id = $2", req.PublicationID, req.ContributorID)
 	if err != nil {
 		return 0, err
 	}
@@ -208,12 +203,30 @@ func (r *likeRepo) Update(ctx context.Context, req *models.UpdateLike) (int64, e
 	return result.RowsAffected(), nil
 }
 
-func (r *likeRepo) Delete(ctx context.Context, req *models.LikePrimaryKey) error {
+func (r *likeRepo) GetStatus(ctx context.Context, req *models.LikeKey) (*models.LikeStatus, error) {
+
+	var (
+		likedByMe sql.NullBool
+		likeCount sql.NullInt64
+	)
+
+	query := `
+		SELECT
+			EXISTS(SELECT 1 FROM likes WHERE publication_id = $1 AND contributor_id = $2),
+			(SELECT COUNT(*) FROM likes WHERE publication_id = $1)
+	`
 
-	_, err := r.db.Exec(ctx, "DELETE FROM likes WHERE id = $1", req.Id)
+	err := r.db.QueryRow(ctx, query, req.PublicationID, req.ContributorID).Scan(
+		&likedByMe,
+		&likeCount,
+	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &models.LikeStatus{
+		PublicationID: req.PublicationID,
+		LikedByMe:     likedByMe.Bool,
+		LikeCount:     int(likeCount.Int64),
+	}, nil
 }
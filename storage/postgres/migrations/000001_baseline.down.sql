@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS downloads;
+DROP TABLE IF EXISTS likes;
+DROP TABLE IF EXISTS publications;
+DROP TABLE IF EXISTS courses;
+DROP TABLE IF EXISTS semesters;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS admins;
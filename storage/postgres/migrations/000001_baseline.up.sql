@@ -1,4 +1,4 @@
-CREATE TABLE admins (
+CREATE TABLE IF NOT EXISTS admins (
     id UUID PRIMARY KEY,
     email VARCHAR(100) UNIQUE NOT NULL,
     password VARCHAR(100) NOT NULL,
@@ -6,7 +6,7 @@ CREATE TABLE admins (
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
-CREATE TABLE users (
+CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY,
     student_id VARCHAR(100) NOT NULL,
     name VARCHAR(100) NOT NULL,
@@ -21,7 +21,7 @@ CREATE TABLE users (
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
-CREATE TABLE semesters (
+CREATE TABLE IF NOT EXISTS semesters (
     id UUID PRIMARY KEY,
     semester_number VARCHAR(100) NOT NULL,
     created_at TIMESTAMP DEFAULT NOW(),
@@ -29,7 +29,7 @@ CREATE TABLE semesters (
 );
 
 
-CREATE TABLE courses (
+CREATE TABLE IF NOT EXISTS courses (
     id UUID PRIMARY KEY,
     course_title VARCHAR(100) NOT NULL,
     semester_id UUID REFERENCES semesters("id"),
@@ -38,7 +38,7 @@ CREATE TABLE courses (
 );
 
 
-CREATE TABLE publications (
+CREATE TABLE IF NOT EXISTS publications (
     id UUID PRIMARY KEY,
     course_id UUID REFERENCES courses("id"),
     title VARCHAR(100) NOT NULL,
@@ -53,7 +53,7 @@ CREATE TABLE publications (
 );
 
 
-CREATE TABLE likes (
+CREATE TABLE IF NOT EXISTS likes (
     id UUID PRIMARY KEY,
     count NUMERIC NOT NULL,
     publication_id UUID REFERENCES publications("id"),
@@ -62,7 +62,7 @@ CREATE TABLE likes (
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
-CREATE TABLE downloads (
+CREATE TABLE IF NOT EXISTS downloads (
     id UUID PRIMARY KEY,
     count NUMERIC NOT NULL,
     publication_id UUID REFERENCES publications("id"),
@@ -73,7 +73,7 @@ CREATE TABLE downloads (
 
 
 
-CREATE TABLE notifications (
+CREATE TABLE IF NOT EXISTS notifications (
     id UUID PRIMARY KEY,
     user_image VARCHAR(100) NOT NULL,
     message VARCHAR(100) NOT NULL,
@@ -83,3 +83,4 @@ CREATE TABLE notifications (
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
 );
+
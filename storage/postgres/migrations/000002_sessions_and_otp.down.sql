@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
+DROP TABLE IF EXISTS otp_codes;
+DROP TABLE IF EXISTS sessions;
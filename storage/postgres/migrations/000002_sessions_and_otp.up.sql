@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    refresh_token_hash VARCHAR(64) NOT NULL,
+    user_agent VARCHAR(255) NULL,
+    ip_address VARCHAR(64) NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS otp_codes (
+    id UUID PRIMARY KEY,
+    email VARCHAR(100) NOT NULL,
+    purpose VARCHAR(30) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    consumed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Accounts created before email verification existed are trusted as verified
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified boolean DEFAULT false;
+UPDATE users SET email_verified = true;
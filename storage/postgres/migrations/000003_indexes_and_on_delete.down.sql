@@ -0,0 +1,33 @@
+DROP INDEX IF EXISTS otp_codes_email_purpose_idx;
+DROP INDEX IF EXISTS sessions_user_id_idx;
+DROP INDEX IF EXISTS downloads_contributor_id_idx;
+DROP INDEX IF EXISTS downloads_publication_id_idx;
+DROP INDEX IF EXISTS likes_contributor_id_idx;
+DROP INDEX IF EXISTS likes_publication_id_idx;
+DROP INDEX IF EXISTS publications_created_at_idx;
+DROP INDEX IF EXISTS publications_status_idx;
+DROP INDEX IF EXISTS publications_contributor_id_idx;
+DROP INDEX IF EXISTS publications_course_id_idx;
+DROP INDEX IF EXISTS courses_semester_id_idx;
+
+ALTER TABLE downloads
+    DROP CONSTRAINT IF EXISTS downloads_contributor_id_fkey,
+    ADD CONSTRAINT downloads_contributor_id_fkey FOREIGN KEY (contributor_id) REFERENCES users(id),
+    DROP CONSTRAINT IF EXISTS downloads_publication_id_fkey,
+    ADD CONSTRAINT downloads_publication_id_fkey FOREIGN KEY (publication_id) REFERENCES publications(id);
+
+ALTER TABLE likes
+    DROP CONSTRAINT IF EXISTS likes_contributor_id_fkey,
+    ADD CONSTRAINT likes_contributor_id_fkey FOREIGN KEY (contributor_id) REFERENCES users(id),
+    DROP CONSTRAINT IF EXISTS likes_publication_id_fkey,
+    ADD CONSTRAINT likes_publication_id_fkey FOREIGN KEY (publication_id) REFERENCES publications(id);
+
+ALTER TABLE publications
+    DROP CONSTRAINT IF EXISTS publications_contributor_id_fkey,
+    ADD CONSTRAINT publications_contributor_id_fkey FOREIGN KEY (contributor_id) REFERENCES users(id),
+    DROP CONSTRAINT IF EXISTS publications_course_id_fkey,
+    ADD CONSTRAINT publications_course_id_fkey FOREIGN KEY (course_id) REFERENCES courses(id);
+
+ALTER TABLE courses
+    DROP CONSTRAINT IF EXISTS courses_semester_id_fkey,
+    ADD CONSTRAINT courses_semester_id_fkey FOREIGN KEY (semester_id) REFERENCES semesters(id);
@@ -0,0 +1,33 @@
+ALTER TABLE courses
+    DROP CONSTRAINT IF EXISTS courses_semester_id_fkey,
+    ADD CONSTRAINT courses_semester_id_fkey FOREIGN KEY (semester_id) REFERENCES semesters(id) ON DELETE RESTRICT;
+
+ALTER TABLE publications
+    DROP CONSTRAINT IF EXISTS publications_course_id_fkey,
+    ADD CONSTRAINT publications_course_id_fkey FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE RESTRICT,
+    DROP CONSTRAINT IF EXISTS publications_contributor_id_fkey,
+    ADD CONSTRAINT publications_contributor_id_fkey FOREIGN KEY (contributor_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE likes
+    DROP CONSTRAINT IF EXISTS likes_publication_id_fkey,
+    ADD CONSTRAINT likes_publication_id_fkey FOREIGN KEY (publication_id) REFERENCES publications(id) ON DELETE CASCADE,
+    DROP CONSTRAINT IF EXISTS likes_contributor_id_fkey,
+    ADD CONSTRAINT likes_contributor_id_fkey FOREIGN KEY (contributor_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE downloads
+    DROP CONSTRAINT IF EXISTS downloads_publication_id_fkey,
+    ADD CONSTRAINT downloads_publication_id_fkey FOREIGN KEY (publication_id) REFERENCES publications(id) ON DELETE CASCADE,
+    DROP CONSTRAINT IF EXISTS downloads_contributor_id_fkey,
+    ADD CONSTRAINT downloads_contributor_id_fkey FOREIGN KEY (contributor_id) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS courses_semester_id_idx ON courses (semester_id);
+CREATE INDEX IF NOT EXISTS publications_course_id_idx ON publications (course_id);
+CREATE INDEX IF NOT EXISTS publications_contributor_id_idx ON publications (contributor_id);
+CREATE INDEX IF NOT EXISTS publications_status_idx ON publications (status);
+CREATE INDEX IF NOT EXISTS publications_created_at_idx ON publications (created_at);
+CREATE INDEX IF NOT EXISTS likes_publication_id_idx ON likes (publication_id);
+CREATE INDEX IF NOT EXISTS likes_contributor_id_idx ON likes (contributor_id);
+CREATE INDEX IF NOT EXISTS downloads_publication_id_idx ON downloads (publication_id);
+CREATE INDEX IF NOT EXISTS downloads_contributor_id_idx ON downloads (contributor_id);
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS otp_codes_email_purpose_idx ON otp_codes (email, purpose);
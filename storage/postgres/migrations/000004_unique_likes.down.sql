@@ -0,0 +1,5 @@
+ALTER TABLE likes
+    DROP CONSTRAINT IF EXISTS likes_publication_id_contributor_id_key,
+    ALTER COLUMN contributor_id DROP NOT NULL,
+    ALTER COLUMN publication_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS count NUMERIC NOT NULL DEFAULT 1;
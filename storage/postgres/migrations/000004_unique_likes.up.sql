@@ -0,0 +1,15 @@
+-- A like is now the relation between one user and one publication, the
+-- free-form count column goes away and totals are counted from rows
+DELETE FROM likes WHERE publication_id IS NULL OR contributor_id IS NULL;
+
+DELETE FROM likes a
+USING likes b
+WHERE a.publication_id = b.publication_id
+    AND a.contributor_id = b.contributor_id
+    AND a.ctid > b.ctid;
+
+ALTER TABLE likes
+    DROP COLUMN IF EXISTS count,
+    ALTER COLUMN publication_id SET NOT NULL,
+    ALTER COLUMN contributor_id SET NOT NULL,
+    ADD CONSTRAINT likes_publication_id_contributor_id_key UNIQUE (publication_id, contributor_id);
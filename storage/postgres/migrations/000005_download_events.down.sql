@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS downloads_publication_id_client_hash_created_at_idx;
+
+ALTER TABLE downloads
+    DROP COLUMN IF EXISTS client_hash,
+    DROP COLUMN IF EXISTS user_agent_hash,
+    ALTER COLUMN publication_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS count NUMERIC NOT NULL DEFAULT 1;
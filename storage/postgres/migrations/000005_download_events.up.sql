@@ -0,0 +1,12 @@
+-- Each row of downloads is now one download served by the API. Existing
+-- self-reported rows are kept as a single event each, the count column
+-- goes away and totals are counted from rows
+DELETE FROM downloads WHERE publication_id IS NULL;
+
+ALTER TABLE downloads
+    DROP COLUMN IF EXISTS count,
+    ALTER COLUMN publication_id SET NOT NULL,
+    ADD COLUMN IF NOT EXISTS user_agent_hash VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS client_hash VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS downloads_publication_id_client_hash_created_at_idx ON downloads (publication_id, client_hash, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS storage_used BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS publications_file_id_idx;
+DROP INDEX IF EXISTS publications_image_id_idx;
+
+ALTER TABLE publications
+    DROP CONSTRAINT IF EXISTS publications_file_id_fkey,
+    DROP CONSTRAINT IF EXISTS publications_image_id_fkey,
+    ALTER COLUMN image_id TYPE VARCHAR(100) USING image_id::text,
+    ALTER COLUMN file_id TYPE VARCHAR(100) USING file_id::text;
+
+UPDATE publications p SET image_id = f.storage_key FROM files f WHERE f.id::text = p.image_id;
+UPDATE publications p SET file_id = f.storage_key FROM files f WHERE f.id::text = p.file_id;
+
+UPDATE publications SET image_id = '' WHERE image_id IS NULL;
+UPDATE publications SET file_id = '' WHERE file_id IS NULL;
+
+ALTER TABLE publications
+    ALTER COLUMN image_id SET NOT NULL,
+    ALTER COLUMN file_id SET NOT NULL;
+
+DROP TABLE IF EXISTS files;
@@ -0,0 +1,64 @@
+-- files records every stored upload. owner_id is the user or admin who
+-- uploaded it, so it has no foreign key
+CREATE TABLE IF NOT EXISTS files (
+    id UUID PRIMARY KEY,
+    owner_id UUID NULL,
+    original_name VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    mime_type VARCHAR(255) NOT NULL DEFAULT 'application/octet-stream',
+    sha256 VARCHAR(64) NULL,
+    storage_key VARCHAR(255) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS files_owner_id_idx ON files (owner_id);
+
+-- Register the stored names publications already point at. Names built as
+-- <uuid>.<ext> keep their uuid as file id, anything else gets a stable id
+-- derived from the name. Size and hash of these files are unknown, the
+-- type is guessed from the extension
+INSERT INTO files (id, owner_id, original_name, mime_type, storage_key)
+SELECT
+    CASE
+        WHEN ref.name ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}(\.[a-z0-9]{1,10})?$'
+        THEN split_part(ref.name, '.', 1)::uuid
+        ELSE md5(ref.name)::uuid
+    END,
+    ref.owner_id,
+    ref.name,
+    CASE lower(substring(ref.name FROM '\.([A-Za-z0-9]+)$'))
+        WHEN 'jpg' THEN 'image/jpeg'
+        WHEN 'jpeg' THEN 'image/jpeg'
+        WHEN 'png' THEN 'image/png'
+        WHEN 'gif' THEN 'image/gif'
+        WHEN 'webp' THEN 'image/webp'
+        WHEN 'pdf' THEN 'application/pdf'
+        ELSE 'application/octet-stream'
+    END,
+    ref.name
+FROM (
+    SELECT image_id AS name, contributor_id AS owner_id FROM publications
+    UNION ALL
+    SELECT file_id, contributor_id FROM publications
+) ref
+WHERE ref.name <> ''
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE publications
+    ALTER COLUMN image_id DROP NOT NULL,
+    ALTER COLUMN file_id DROP NOT NULL;
+
+UPDATE publications SET image_id = NULL WHERE image_id = '';
+UPDATE publications SET file_id = NULL WHERE file_id = '';
+
+UPDATE publications p SET image_id = f.id::text FROM files f WHERE f.storage_key = p.image_id;
+UPDATE publications p SET file_id = f.id::text FROM files f WHERE f.storage_key = p.file_id;
+
+ALTER TABLE publications
+    ALTER COLUMN image_id TYPE UUID USING image_id::uuid,
+    ALTER COLUMN file_id TYPE UUID USING file_id::uuid,
+    ADD CONSTRAINT publications_image_id_fkey FOREIGN KEY (image_id) REFERENCES files(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT publications_file_id_fkey FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS publications_image_id_idx ON publications (image_id);
+CREATE INDEX IF NOT EXISTS publications_file_id_idx ON publications (file_id);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS files_kind_idx;
+DROP INDEX IF EXISTS files_sha256_idx;
+DROP INDEX IF EXISTS files_storage_key_idx;
+
+-- The unique storage_key constraint is not restored: files sharing a blob
+-- would violate it
+ALTER TABLE files
+    DROP CONSTRAINT IF EXISTS files_sha256_fkey,
+    DROP COLUMN IF EXISTS kind;
+
+DROP TABLE IF EXISTS blobs;
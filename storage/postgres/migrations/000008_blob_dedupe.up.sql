@@ -0,0 +1,59 @@
+-- blobs holds each distinct content once, keyed by its SHA-256. ref_count
+-- is the number of files rows pointing at it
+CREATE TABLE IF NOT EXISTS blobs (
+    sha256 VARCHAR(64) PRIMARY KEY,
+    storage_key VARCHAR(255) UNIQUE NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    mime_type VARCHAR(255) NOT NULL DEFAULT 'application/octet-stream',
+    ref_count INTEGER NOT NULL DEFAULT 0 CHECK (ref_count >= 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Several files may now share one stored blob
+ALTER TABLE files
+    DROP CONSTRAINT IF EXISTS files_storage_key_key,
+    ADD COLUMN IF NOT EXISTS kind VARCHAR(32) NOT NULL DEFAULT 'publication_file';
+
+UPDATE files SET kind = 'profile_image' WHERE storage_key LIKE 'profile_images/%';
+
+-- Register the profile images users already point at, so they are listed
+-- and served like new ones
+INSERT INTO files (id, owner_id, kind, original_name, mime_type, storage_key)
+SELECT
+    split_part(profile_image, '.', 1)::uuid,
+    id,
+    'profile_image',
+    profile_image,
+    CASE lower(substring(profile_image FROM '\.([A-Za-z0-9]+)$'))
+        WHEN 'jpg' THEN 'image/jpeg'
+        WHEN 'jpeg' THEN 'image/jpeg'
+        WHEN 'png' THEN 'image/png'
+        WHEN 'gif' THEN 'image/gif'
+        WHEN 'webp' THEN 'image/webp'
+        ELSE 'application/octet-stream'
+    END,
+    'profile_images/' || profile_image
+FROM users
+WHERE profile_image ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}(\.[a-z0-9]{1,10})?$'
+ON CONFLICT DO NOTHING;
+
+UPDATE files f SET kind = 'publication_cover' FROM publications p WHERE p.image_id = f.id;
+
+-- Files hashed on upload before this migration each become a blob, the
+-- oldest copy of a content wins
+INSERT INTO blobs (sha256, storage_key, size, mime_type)
+SELECT DISTINCT ON (sha256) sha256, storage_key, size, mime_type
+FROM files
+WHERE sha256 IS NOT NULL
+ORDER BY sha256, created_at
+ON CONFLICT DO NOTHING;
+
+UPDATE blobs b SET ref_count = (SELECT COUNT(*) FROM files f WHERE f.sha256 = b.sha256);
+
+ALTER TABLE files
+    ADD CONSTRAINT files_sha256_fkey FOREIGN KEY (sha256) REFERENCES blobs(sha256) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS files_storage_key_idx ON files (storage_key);
+CREATE INDEX IF NOT EXISTS files_sha256_idx ON files (sha256);
+CREATE INDEX IF NOT EXISTS files_kind_idx ON files (kind);
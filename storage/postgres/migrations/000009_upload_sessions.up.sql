@@ -0,0 +1,28 @@
+-- upload_sessions tracks resumable uploads. Received bytes are stored as
+-- one blob per chunk in upload_chunks until the upload completes
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    id UUID PRIMARY KEY,
+    owner_id UUID NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    upload_offset BIGINT NOT NULL DEFAULT 0 CHECK (upload_offset >= 0 AND upload_offset <= size),
+    quota_reserved BIGINT NOT NULL DEFAULT 0,
+    file_id UUID NULL REFERENCES files(id) ON DELETE SET NULL,
+    completed_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS upload_chunks (
+    session_id UUID NOT NULL REFERENCES upload_sessions(id) ON DELETE CASCADE,
+    upload_offset BIGINT NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (session_id, upload_offset)
+);
+
+CREATE INDEX IF NOT EXISTS upload_sessions_owner_id_idx ON upload_sessions (owner_id);
+CREATE INDEX IF NOT EXISTS upload_sessions_expires_at_idx ON upload_sessions (expires_at);
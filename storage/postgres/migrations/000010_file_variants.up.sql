@@ -0,0 +1,17 @@
+-- file_variants holds the resized renditions of image files. The file row
+-- itself is the original. Each variant holds a reference (ref_count) on
+-- its blob like a files row does
+CREATE TABLE IF NOT EXISTS file_variants (
+    file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    variant VARCHAR(16) NOT NULL,
+    sha256 VARCHAR(64) NOT NULL REFERENCES blobs(sha256),
+    storage_key VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    mime_type VARCHAR(255) NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (file_id, variant)
+);
+
+CREATE INDEX IF NOT EXISTS file_variants_sha256_idx ON file_variants (sha256);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS publication_transitions;
+
+ALTER TABLE publications
+    DROP CONSTRAINT IF EXISTS publications_status_check,
+    ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE VARCHAR(100);
@@ -0,0 +1,39 @@
+-- Map the free text statuses onto the moderation lifecycle. Everything
+-- that isn't clearly something else stays visible as published
+UPDATE publications
+SET status = CASE lower(trim(status))
+    WHEN 'draft' THEN 'draft'
+    WHEN 'pending' THEN 'pending_review'
+    WHEN 'pending_review' THEN 'pending_review'
+    WHEN 'review' THEN 'pending_review'
+    WHEN 'rejected' THEN 'rejected'
+    WHEN 'archived' THEN 'archived'
+    ELSE 'published'
+END;
+
+ALTER TABLE publications
+    ALTER COLUMN status TYPE VARCHAR(32),
+    ALTER COLUMN status SET DEFAULT 'draft',
+    DROP CONSTRAINT IF EXISTS publications_status_check,
+    ADD CONSTRAINT publications_status_check
+        CHECK (status IN ('draft', 'pending_review', 'published', 'rejected', 'archived'));
+
+-- publication_transitions is the moderation history, one row per status
+-- change. from_status is NULL for the status a publication was created in
+CREATE TABLE IF NOT EXISTS publication_transitions (
+    id UUID PRIMARY KEY,
+    publication_id UUID NOT NULL REFERENCES publications(id) ON DELETE CASCADE,
+    from_status VARCHAR(32) NULL,
+    to_status VARCHAR(32) NOT NULL,
+    actor_id UUID NULL,
+    reason TEXT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS publication_transitions_publication_id_idx
+    ON publication_transitions (publication_id, created_at);
+
+-- Existing publications start their history with their current status
+INSERT INTO publication_transitions (id, publication_id, to_status, actor_id, created_at)
+SELECT md5(id::text || 'created')::uuid, id, status, contributor_id, created_at
+FROM publications;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS publication_transitions_actor_id_idx;
+DROP INDEX IF EXISTS publications_status_created_at_idx;
+DROP TABLE IF EXISTS publication_claims;
@@ -0,0 +1,19 @@
+-- publication_claims marks a publication waiting for review as taken by
+-- one moderator until expires_at, so two admins don't review it at once
+CREATE TABLE IF NOT EXISTS publication_claims (
+    publication_id UUID PRIMARY KEY REFERENCES publications(id) ON DELETE CASCADE,
+    moderator_id UUID NOT NULL,
+    claimed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS publication_claims_moderator_id_idx
+    ON publication_claims (moderator_id);
+
+-- The queue lists pending publications oldest first, the stats count
+-- decisions per moderator
+CREATE INDEX IF NOT EXISTS publications_status_created_at_idx
+    ON publications (status, created_at);
+
+CREATE INDEX IF NOT EXISTS publication_transitions_actor_id_idx
+    ON publication_transitions (actor_id, created_at);
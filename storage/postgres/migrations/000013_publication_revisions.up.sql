@@ -0,0 +1,57 @@
+-- publication_revisions keeps a snapshot of the editable fields of a
+-- publication after every change. Rows are never updated; a rollback adds
+-- a new revision with rollback_of set. changed_fields names the fields
+-- that differ from the previous revision, previous_file_id is the file
+-- the revision replaced
+CREATE TABLE IF NOT EXISTS publication_revisions (
+    id UUID PRIMARY KEY,
+    publication_id UUID NOT NULL REFERENCES publications(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    course_id UUID NULL,
+    title TEXT NULL,
+    description TEXT NULL,
+    tags TEXT NULL,
+    image_id UUID NULL REFERENCES files(id) ON DELETE RESTRICT,
+    file_id UUID NULL REFERENCES files(id) ON DELETE RESTRICT,
+    contributor_id UUID NULL,
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    previous_file_id UUID NULL REFERENCES files(id) ON DELETE RESTRICT,
+    editor_id UUID NULL,
+    rollback_of INT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (publication_id, revision)
+);
+
+CREATE INDEX IF NOT EXISTS publication_revisions_image_id_idx ON publication_revisions (image_id);
+CREATE INDEX IF NOT EXISTS publication_revisions_file_id_idx ON publication_revisions (file_id);
+CREATE INDEX IF NOT EXISTS publication_revisions_previous_file_id_idx ON publication_revisions (previous_file_id);
+
+-- Existing publications start their history with their current content
+INSERT INTO publication_revisions (
+    id, publication_id, revision, course_id, title, description, tags,
+    image_id, file_id, contributor_id, changed_fields, editor_id, created_at
+)
+SELECT
+    md5(id::text || 'revision-1')::uuid,
+    id,
+    1,
+    course_id,
+    title,
+    description,
+    tags,
+    image_id,
+    file_id,
+    contributor_id,
+    array_remove(ARRAY[
+        CASE WHEN course_id IS NOT NULL THEN 'course_id' END,
+        CASE WHEN COALESCE(title, '') <> '' THEN 'title' END,
+        CASE WHEN COALESCE(description, '') <> '' THEN 'description' END,
+        CASE WHEN COALESCE(tags, '') <> '' THEN 'tags' END,
+        CASE WHEN image_id IS NOT NULL THEN 'image_id' END,
+        CASE WHEN file_id IS NOT NULL THEN 'file_id' END,
+        CASE WHEN contributor_id IS NOT NULL THEN 'contributor_id' END
+    ], NULL),
+    contributor_id,
+    COALESCE(updated_at, created_at, NOW())
+FROM publications
+ON CONFLICT DO NOTHING;
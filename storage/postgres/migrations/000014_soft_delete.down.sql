@@ -0,0 +1,19 @@
+-- Rows still in the trash are gone for good when going back
+DELETE FROM notifications WHERE deleted_at IS NOT NULL;
+DELETE FROM publications WHERE deleted_at IS NOT NULL;
+DELETE FROM courses WHERE deleted_at IS NOT NULL;
+DELETE FROM semesters WHERE deleted_at IS NOT NULL;
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS users_username_live_key;
+DROP INDEX IF EXISTS users_email_live_key;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_email_key UNIQUE (email),
+    ADD CONSTRAINT users_username_key UNIQUE (username);
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE publications DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE courses DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE semesters DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,22 @@
+-- deleted_at marks rows moved to the trash. Reads skip them, an admin can
+-- restore them, and a job purges them after the retention period
+ALTER TABLE semesters ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE publications ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS semesters_deleted_at_idx ON semesters (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS courses_deleted_at_idx ON courses (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS publications_deleted_at_idx ON publications (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS notifications_deleted_at_idx ON notifications (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- A deleted user must not keep their email and username from someone
+-- registering again, so uniqueness only holds among live users
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_email_key,
+    DROP CONSTRAINT IF EXISTS users_username_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_live_key ON users (email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_live_key ON users (username) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS publication_tags;
+DROP TABLE IF EXISTS tags;
+
+-- Longer tag lists don't fit the old column and are cut
+ALTER TABLE publications ALTER COLUMN tags TYPE VARCHAR(100) USING left(tags, 100);
@@ -0,0 +1,50 @@
+-- tags is the catalog of normalized tags: lowercased, trimmed, inner
+-- whitespace collapsed. publication_tags links them to publications.
+-- publications.tags stays as the sorted, comma joined names so reads don't
+-- need the join; it is rewritten whenever the links change
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS tags_name_prefix_idx ON tags (name text_pattern_ops);
+
+CREATE TABLE IF NOT EXISTS publication_tags (
+    publication_id UUID NOT NULL REFERENCES publications(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (publication_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS publication_tags_tag_id_idx ON publication_tags (tag_id);
+
+ALTER TABLE publications ALTER COLUMN tags TYPE TEXT;
+
+-- Split the existing strings the way the API normalizes new tags
+CREATE TEMPORARY TABLE legacy_tags ON COMMIT DROP AS
+SELECT DISTINCT p.id AS publication_id, lower(btrim(regexp_replace(t.name, '\s+', ' ', 'g'))) AS name
+FROM publications p, unnest(string_to_array(p.tags, ',')) AS t(name);
+
+DELETE FROM legacy_tags WHERE name = '';
+
+-- The ids are shaped as version 4 UUIDs, like the ones the API creates
+INSERT INTO tags (id, name)
+SELECT overlay(overlay(md5('tag-' || name) PLACING '4' FROM 13) PLACING '8' FROM 17)::uuid, name
+FROM (SELECT DISTINCT name FROM legacy_tags) AS names
+ON CONFLICT DO NOTHING;
+
+INSERT INTO publication_tags (publication_id, tag_id)
+SELECT l.publication_id, t.id
+FROM legacy_tags l
+JOIN tags t ON t.name = l.name
+ON CONFLICT DO NOTHING;
+
+UPDATE publications p
+SET tags = COALESCE((
+    SELECT string_agg(t.name, ',' ORDER BY t.name COLLATE "C")
+    FROM publication_tags pt
+    JOIN tags t ON t.id = pt.tag_id
+    WHERE pt.publication_id = p.id
+), '')
+WHERE p.tags IS NOT NULL;
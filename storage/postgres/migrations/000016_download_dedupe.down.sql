@@ -0,0 +1 @@
+DROP INDEX IF EXISTS downloads_publication_id_client_hash_created_at_idx;
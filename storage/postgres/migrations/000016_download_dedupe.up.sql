@@ -0,0 +1,4 @@
+-- A download is counted once per client and publication within the dedupe
+-- window. This index serves the lookup of the latest download of a client
+CREATE INDEX IF NOT EXISTS downloads_publication_id_client_hash_created_at_idx
+    ON downloads (publication_id, client_hash, created_at);
@@ -0,0 +1,418 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/moderation"
+)
+
+type moderationRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewModerationRepo(db *pgxpool.Pool) *moderationRepo {
+	return &moderationRepo{
+		db: db,
+	}
+}
+
+// submittedAtJoin adds s.submitted_at, the last time the publication p
+// entered pending_review.
+const submittedAtJoin = `
+	LEFT JOIN LATERAL (
+		SELECT MAX(t.created_at) AS submitted_at
+		FROM publication_transitions t
+		WHERE t.publication_id = p.id AND t.to_status = 'pending_review'
+	) s ON TRUE
+`
+
+// GetQueue lists the publications waiting for review, oldest submission
+// first, with their contributor, file and live claim.
+func (r *moderationRepo) GetQueue(ctx context.Context, req *models.ModerationQueueRequest) (*models.ModerationQueueResponse, error) {
+
+	var (
+		resp  = &models.ModerationQueueResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			p.id,
+			p.course_id,
+			p.title,
+			p.description,
+			p.tags,
+			p.image_id,
+			p.file_id,
+			p.contributor_id,
+			p.status,
+			p.created_at,
+			p.updated_at,
+			COALESCE(s.submitted_at, p.created_at),
+			u.name,
+			u.surname,
+			u.username,
+			u.email,
+			(SELECT COUNT(*) FROM publications o WHERE o.contributor_id = p.contributor_id AND o.status = 'published' AND o.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM publications o WHERE o.contributor_id = p.contributor_id AND o.status = 'rejected' AND o.deleted_at IS NULL),
+			f.original_name,
+			f.mime_type,
+			f.size,
+			c.moderator_id,
+			c.claimed_at,
+			c.expires_at
+		FROM publications p
+		LEFT JOIN users u ON u.id = p.contributor_id
+		LEFT JOIN files f ON f.id = p.file_id
+		LEFT JOIN publication_claims c ON c.publication_id = p.id AND c.expires_at > NOW()
+	` + submittedAtJoin
+
+	qb.Where("p.status = "+qb.Arg(string(moderation.PendingReview))).
+		Where("p.deleted_at IS NULL").
+		Equal("p.course_id", req.CourseID)
+
+	if req.Available {
+		qb.Where("(c.moderator_id IS NULL OR c.moderator_id = " + qb.Arg(req.ModeratorID) + ")")
+	}
+
+	qb.OrderBy("submitted_at", "asc", map[string]string{
+		"submitted_at": "COALESCE(s.submitted_at, p.created_at)",
+	}, "submitted_at").
+		Paginate(req.Offset, req.Limit)
+
+	query, args := qb.Build(query)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id           sql.NullString
+			courseId     sql.NullString
+			title        sql.NullString
+			description  sql.NullString
+			tags         sql.NullString
+			imageID      sql.NullString
+			fileID       sql.NullString
+			contributor  sql.NullString
+			status       sql.NullString
+			createdAt    sql.NullString
+			updatedAt    sql.NullString
+			submittedAt  sql.NullString
+			name         sql.NullString
+			surname      sql.NullString
+			username     sql.NullString
+			email        sql.NullString
+			published    sql.NullInt64
+			rejected     sql.NullInt64
+			originalName sql.NullString
+			mimeType     sql.NullString
+			size         sql.NullInt64
+			moderatorID  sql.NullString
+			claimedAt    sql.NullString
+			expiresAt    sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&id,
+			&courseId,
+			&title,
+			&description,
+			&tags,
+			&imageID,
+			&fileID,
+			&contributor,
+			&status,
+			&createdAt,
+			&updatedAt,
+			&submittedAt,
+			&name,
+			&surname,
+			&username,
+			&email,
+			&published,
+			&rejected,
+			&originalName,
+			&mimeType,
+			&size,
+			&moderatorID,
+			&claimedAt,
+			&expiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		item := &models.ModerationQueueItem{
+			Publication: &models.Publication{
+				Id:            id.String,
+				CourseId:      courseId.String,
+				Title:         title.String,
+				Description:   description.String,
+				Tags:          tags.String,
+				ImageID:       imageID.String,
+				FileID:        fileID.String,
+				ContributorID: contributor.String,
+				Status:        status.String,
+				CreatedAt:     createdAt.String,
+				UpdatedAt:     updatedAt.String,
+			},
+			SubmittedAt: submittedAt.String,
+			Contributor: &models.ModerationContributor{
+				Id:        contributor.String,
+				Name:      name.String,
+				Surname:   surname.String,
+				Username:  username.String,
+				Email:     email.String,
+				Published: int(published.Int64),
+				Rejected:  int(rejected.Int64),
+			},
+		}
+
+		if fileID.Valid {
+			item.File = &models.ModerationFile{
+				Id:           fileID.String,
+				OriginalName: originalName.String,
+				MimeType:     mimeType.String,
+				Size:         size.Int64,
+			}
+		}
+
+		if moderatorID.Valid {
+			item.Claim = &models.ModerationClaim{
+				PublicationID: id.String,
+				ModeratorID:   moderatorID.String,
+				ClaimedAt:     claimedAt.String,
+				ExpiresAt:     expiresAt.String,
+			}
+		}
+
+		resp.Items = append(resp.Items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Claim gives req.ModeratorID the publication for req.TTL seconds. Claiming
+// again extends the claim; an expired claim of someone else is taken over.
+// A live claim of another moderator is returned with moderation.ErrClaimed.
+func (r *moderationRepo) Claim(ctx context.Context, req *models.ModerationClaimRequest) (*models.ModerationClaim, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the publication serializes claims with each other and with
+	// status changes
+	var status string
+	err = tx.QueryRow(ctx, "SELECT status FROM publications WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", req.PublicationID).Scan(&status)
+	if err != nil {
+		return nil, err
+	}
+
+	if moderation.Status(status) != moderation.PendingReview {
+		return nil, moderation.ErrNotPending
+	}
+
+	var (
+		moderatorID sql.NullString
+		claimedAt   sql.NullString
+		expiresAt   sql.NullString
+	)
+
+	err = tx.QueryRow(ctx, `
+		SELECT moderator_id, claimed_at, expires_at
+		FROM publication_claims
+		WHERE publication_id = $1 AND expires_at > NOW()
+	`, req.PublicationID).Scan(&moderatorID, &claimedAt, &expiresAt)
+	if err != nil && err.Error() != "no rows in result set" {
+		return nil, err
+	}
+
+	if err == nil && moderatorID.String != req.ModeratorID {
+		return &models.ModerationClaim{
+			PublicationID: req.PublicationID,
+			ModeratorID:   moderatorID.String,
+			ClaimedAt:     claimedAt.String,
+			ExpiresAt:     expiresAt.String,
+		}, moderation.ErrClaimed
+	}
+
+	query := `
+		INSERT INTO publication_claims(publication_id, moderator_id, claimed_at, expires_at)
+		VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3))
+		ON CONFLICT (publication_id) DO UPDATE
+		SET
+			claimed_at = CASE
+				WHEN publication_claims.moderator_id = EXCLUDED.moderator_id
+					AND publication_claims.expires_at > NOW()
+				THEN publication_claims.claimed_at
+				ELSE EXCLUDED.claimed_at
+			END,
+			moderator_id = EXCLUDED.moderator_id,
+			expires_at = EXCLUDED.expires_at
+		RETURNING claimed_at, expires_at
+	`
+
+	err = tx.QueryRow(ctx, query, req.PublicationID, req.ModeratorID, req.TTL).Scan(&claimedAt, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ModerationClaim{
+		PublicationID: req.PublicationID,
+		ModeratorID:   req.ModeratorID,
+		ClaimedAt:     claimedAt.String,
+		ExpiresAt:     expiresAt.String,
+	}, nil
+}
+
+// Release drops a claim and returns how many were dropped.
+func (r *moderationRepo) Release(ctx context.Context, req *models.ModerationReleaseRequest) (int64, error) {
+
+	query := `
+		DELETE FROM publication_claims
+		WHERE publication_id = $1 AND (moderator_id = $2 OR $3)
+	`
+
+	result, err := r.db.Exec(ctx, query, req.PublicationID, req.ModeratorID, req.Force)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetStats counts the review decisions of every moderator, approvals being
+// the moves from pending_review to published and rejections those to
+// rejected.
+func (r *moderationRepo) GetStats(ctx context.Context, req *models.ModerationStatsRequest) (*models.ModerationStats, error) {
+
+	var (
+		resp          = &models.ModerationStats{}
+		pending       sql.NullInt64
+		oldestPending sql.NullString
+		qb            = helper.NewQueryBuilder()
+	)
+
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*), MIN(COALESCE(s.submitted_at, p.created_at))
+		FROM publications p
+	`+submittedAtJoin+`
+		WHERE p.status = $1 AND p.deleted_at IS NULL
+	`, string(moderation.PendingReview)).Scan(&pending, &oldestPending)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Pending = int(pending.Int64)
+	resp.OldestPendingAt = oldestPending.String
+
+	qb.Where("t.from_status = "+qb.Arg(string(moderation.PendingReview))).
+		Where("t.to_status IN ("+qb.Arg(string(moderation.Published))+", "+qb.Arg(string(moderation.Rejected))+")").
+		Where("t.actor_id IS NOT NULL").
+		DateRange("t.created_at", req.DateFrom, req.DateTo)
+
+	query := `
+		WITH decisions AS (
+			SELECT
+				t.actor_id,
+				t.to_status,
+				t.created_at,
+				EXTRACT(EPOCH FROM t.created_at - (
+					SELECT MAX(s.created_at)
+					FROM publication_transitions s
+					WHERE s.publication_id = t.publication_id
+						AND s.to_status = t.from_status
+						AND s.created_at <= t.created_at
+				)) AS review_seconds
+			FROM publication_transitions t
+			` + qb.WhereClause() + `
+		)
+		SELECT
+			d.actor_id,
+			a.email,
+			COUNT(*) FILTER (WHERE d.to_status = 'published'),
+			COUNT(*) FILTER (WHERE d.to_status = 'rejected'),
+			COUNT(*),
+			COALESCE(AVG(d.review_seconds), 0)::float8,
+			MAX(d.created_at),
+			(SELECT COUNT(*) FROM publication_claims c WHERE c.moderator_id = d.actor_id AND c.expires_at > NOW())
+		FROM decisions d
+		LEFT JOIN admins a ON a.id = d.actor_id
+		GROUP BY d.actor_id, a.email
+		ORDER BY COUNT(*) DESC, d.actor_id
+	`
+
+	rows, err := r.db.Query(ctx, query, qb.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			moderatorID    sql.NullString
+			email          sql.NullString
+			approved       sql.NullInt64
+			rejected       sql.NullInt64
+			total          sql.NullInt64
+			avgReview      sql.NullFloat64
+			lastDecisionAt sql.NullString
+			activeClaims   sql.NullInt64
+		)
+
+		err := rows.Scan(
+			&moderatorID,
+			&email,
+			&approved,
+			&rejected,
+			&total,
+			&avgReview,
+			&lastDecisionAt,
+			&activeClaims,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Moderators = append(resp.Moderators, &models.ModeratorThroughput{
+			ModeratorID:      moderatorID.String,
+			Email:            email.String,
+			Approved:         int(approved.Int64),
+			Rejected:         int(rejected.Int64),
+			Total:            int(total.Int64),
+			AvgReviewSeconds: avgReview.Float64,
+			LastDecisionAt:   lastDecisionAt.String,
+			ActiveClaims:     int(activeClaims.Int64),
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
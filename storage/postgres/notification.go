@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	uuid "github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -75,7 +74,7 @@ func (r *notificationRepo) GetByID(ctx context.Context, req *models.Notification
 			created_at,
 			updated_at
 		FROM notifications
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	err := r.db.QueryRow(ctx, query, req.Id).Scan(
@@ -105,14 +104,19 @@ func (r *notificationRepo) GetByID(ctx context.Context, req *models.Notification
 	}, nil
 }
 
+// notificationSortFields are the sort_by values accepted by GetList.
+var notificationSortFields = map[string]string{
+	"message_type": "message_type",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+}
+
 func (r *notificationRepo) GetList(ctx context.Context, req *models.NotificationGetListRequest) (*models.NotificationGetListResponse, error) {
 
 	var (
-		resp   = &models.NotificationGetListResponse{}
-		query  string
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
+		resp  = &models.NotificationGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
 	)
 
 	query = `
@@ -129,24 +133,24 @@ func (r *notificationRepo) GetList(ctx context.Context, req *models.Notification
 		FROM notifications
 	`
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
+	qb.Where("deleted_at IS NULL").
+		Search(req.Search, "message").
+		Equal("message_type", req.MessageType).
+		DateRange("created_at", req.DateFrom, req.DateTo).
+		OrderBy(req.SortBy, req.Order, notificationSortFields, "created_at").
+		Paginate(req.Offset, req.Limit)
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
+	query, args := qb.Build(query)
 
-	if req.Search != "" {
-		where += ` AND message ILIKE '%' || '` + req.Search + `' || '%'`
+	if err := qb.Err(); err != nil {
+		return nil, err
 	}
 
-	query += where + offset + limit
-
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -188,6 +192,10 @@ func (r *notificationRepo) GetList(ctx context.Context, req *models.Notification
 		})
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
@@ -208,7 +216,7 @@ func (r *notificationRepo) Update(ctx context.Context, req *models.UpdateNotific
 			username = :username,
 			message_type = :message_type,
 			updated_at = NOW()
-		WHERE id = :id
+		WHERE id = :id AND deleted_at IS NULL
 	`
 
 	params = map[string]interface{}{
@@ -232,7 +240,7 @@ func (r *notificationRepo) Update(ctx context.Context, req *models.UpdateNotific
 
 func (r *notificationRepo) Delete(ctx context.Context, req *models.NotificationPrimaryKey) error {
 
-	_, err := r.db.Exec(ctx, "DELETE FROM notifications WHERE id = $1", req.Id)
+	_, err := r.db.Exec(ctx, "UPDATE notifications SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", req.Id)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+type otpRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOtpRepo(db *pgxpool.Pool) *otpRepo {
+	return &otpRepo{
+		db: db,
+	}
+}
+
+// Create stores a new code and invalidates every earlier unused code for the
+// same email and purpose, so only the latest code sent can be redeemed.
+// No code is created, and the id is empty, while the last code is younger
+// than req.Cooldown seconds or once req.MaxPerHour codes, when set, were
+// issued in the last hour.
+func (r *otpRepo) Create(ctx context.Context, req *models.CreateOtp) (string, error) {
+	var (
+		id    = uuid.New().String()
+		query string
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serializes the requests for the same email and purpose so concurrent
+	// ones can't all pass the limits
+	_, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1 || ':' || $2))", req.Email, req.Purpose)
+	if err != nil {
+		return "", err
+	}
+
+	query = `
+		SELECT
+			COUNT(*) FILTER (WHERE created_at > NOW() - make_interval(secs => $3)),
+			COUNT(*)
+		FROM otp_codes
+		WHERE email = $1 AND purpose = $2 AND created_at > NOW() - INTERVAL '1 hour'
+	`
+
+	var recent, lastHour int
+	err = tx.QueryRow(ctx, query, req.Email, req.Purpose, req.Cooldown).Scan(&recent, &lastHour)
+	if err != nil {
+		return "", err
+	}
+
+	if recent > 0 || (req.MaxPerHour > 0 && lastHour >= req.MaxPerHour) {
+		return "", nil
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE otp_codes SET consumed_at = NOW() WHERE email = $1 AND purpose = $2 AND consumed_at IS NULL",
+		req.Email,
+		req.Purpose,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	query = `
+		INSERT INTO otp_codes(id, email, purpose, code_hash, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5))
+	`
+
+	_, err = tx.Exec(ctx, query,
+		id,
+		req.Email,
+		req.Purpose,
+		req.CodeHash,
+		req.ExpiresIn,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// GetActive returns the latest unused and unexpired code for the email and purpose.
+func (r *otpRepo) GetActive(ctx context.Context, req *models.OtpPrimaryKey) (*models.Otp, error) {
+
+	var (
+		query string
+
+		id        sql.NullString
+		email     sql.NullString
+		purpose   sql.NullString
+		codeHash  sql.NullString
+		attempts  sql.NullInt64
+		expiresAt sql.NullString
+		createdAt sql.NullString
+	)
+
+	query = `
+		SELECT
+			id,
+			email,
+			purpose,
+			code_hash,
+			attempts,
+			expires_at,
+			created_at
+		FROM otp_codes
+		WHERE email = $1 AND purpose = $2 AND consumed_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Email, req.Purpose).Scan(
+		&id,
+		&email,
+		&purpose,
+		&codeHash,
+		&attempts,
+		&expiresAt,
+		&createdAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Otp{
+		Id:        id.String,
+		Email:     email.String,
+		Purpose:   purpose.String,
+		CodeHash:  codeHash.String,
+		Attempts:  int(attempts.Int64),
+		ExpiresAt: expiresAt.String,
+		CreatedAt: createdAt.String,
+	}, nil
+}
+
+// RegisterAttempt counts a verification attempt. It affects no rows once
+// maxAttempts is reached, which locks the code.
+func (r *otpRepo) RegisterAttempt(ctx context.Context, req *models.OtpPrimaryKey, maxAttempts int) (int64, error) {
+
+	result, err := r.db.Exec(ctx,
+		"UPDATE otp_codes SET attempts = attempts + 1 WHERE id = $1 AND attempts < $2 AND consumed_at IS NULL",
+		req.Id,
+		maxAttempts,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *otpRepo) Consume(ctx context.Context, req *models.OtpPrimaryKey) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "UPDATE otp_codes SET consumed_at = NOW() WHERE id = $1 AND consumed_at IS NULL", req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
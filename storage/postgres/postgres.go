@@ -11,27 +11,28 @@ import (
 )
 
 type store struct {
-	db           *pgxpool.Pool
-	admin        *adminRepo
-	user         *userRepo
-	course       *courseRepo
-	semester     *semesterRepo
-	like         *likeRepo
-	download     *downloadRepo
-	publication  *publicationRepo
-	notification *notificationRepo
+	db            *pgxpool.Pool
+	admin         *adminRepo
+	user          *userRepo
+	course        *courseRepo
+	semester      *semesterRepo
+	like          *likeRepo
+	download      *downloadRepo
+	publication   *publicationRepo
+	notification  *notificationRepo
+	session       *sessionRepo
+	otp           *otpRepo
+	file          *fileRepo
+	blob          *blobRepo
+	uploadSession *uploadSessionRepo
+	moderation    *moderationRepo
+	trash         *trashRepo
+	tag           *tagRepo
 }
 
 func NewConnectionPostgres(cfg *config.Config) (storage.StorageI, error) {
 
-	connect, err := pgxpool.ParseConfig(fmt.Sprintf(
-		"host=%s user=%s dbname=%s password=%s port=%d sslmode=disable",
-		cfg.PostgresHost,
-		cfg.PostgresUser,
-		cfg.PostgresDatabase,
-		cfg.PostgresPassword,
-		cfg.PostgresPort,
-	))
+	connect, err := pgxpool.ParseConfig(connString(cfg))
 
 	if err != nil {
 		return nil, err
@@ -48,6 +49,17 @@ func NewConnectionPostgres(cfg *config.Config) (storage.StorageI, error) {
 	}, nil
 }
 
+func connString(cfg *config.Config) string {
+	return fmt.Sprintf(
+		"host=%s user=%s dbname=%s password=%s port=%d sslmode=disable",
+		cfg.PostgresHost,
+		cfg.PostgresUser,
+		cfg.PostgresDatabase,
+		cfg.PostgresPassword,
+		cfg.PostgresPort,
+	)
+}
+
 func (s *store) Close() {
 	s.db.Close()
 }
@@ -119,3 +131,75 @@ func (s *store) Notification() storage.NotificationRepoI {
 
 	return s.notification
 }
+
+func (s *store) Session() storage.SessionRepoI {
+
+	if s.session == nil {
+		s.session = NewSessionRepo(s.db)
+	}
+
+	return s.session
+}
+
+func (s *store) Otp() storage.OtpRepoI {
+
+	if s.otp == nil {
+		s.otp = NewOtpRepo(s.db)
+	}
+
+	return s.otp
+}
+
+func (s *store) File() storage.FileRepoI {
+
+	if s.file == nil {
+		s.file = NewFileRepo(s.db)
+	}
+
+	return s.file
+}
+
+func (s *store) Blob() storage.BlobRepoI {
+
+	if s.blob == nil {
+		s.blob = NewBlobRepo(s.db)
+	}
+
+	return s.blob
+}
+
+func (s *store) UploadSession() storage.UploadSessionRepoI {
+
+	if s.uploadSession == nil {
+		s.uploadSession = NewUploadSessionRepo(s.db)
+	}
+
+	return s.uploadSession
+}
+
+func (s *store) Moderation() storage.ModerationRepoI {
+
+	if s.moderation == nil {
+		s.moderation = NewModerationRepo(s.db)
+	}
+
+	return s.moderation
+}
+
+func (s *store) Trash() storage.TrashRepoI {
+
+	if s.trash == nil {
+		s.trash = NewTrashRepo(s.db)
+	}
+
+	return s.trash
+}
+
+func (s *store) Tag() storage.TagRepoI {
+
+	if s.tag == nil {
+		s.tag = NewTagRepo(s.db)
+	}
+
+	return s.tag
+}
@@ -6,10 +6,13 @@ import (
 	"fmt"
 
 	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"app/api/models"
 	"app/pkg/helper"
+	"app/pkg/moderation"
+	"app/pkg/tags"
 )
 
 type publicationRepo struct {
@@ -22,25 +25,38 @@ func NewPublicationRepo(db *pgxpool.Pool) *publicationRepo {
 	}
 }
 
+// Create inserts a publication in an initial moderation status and starts
+// its transition and revision history.
 func (r *publicationRepo) Create(ctx context.Context, req *models.CreatePublication) (string, error) {
 	var (
 		id    = uuid.New().String()
+		names = tags.Split(req.Tags)
 		query string
 	)
 
+	if !moderation.Initial(moderation.Status(req.Status)) {
+		return "", moderation.ErrInvalidStatus
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
 	query = `
 		INSERT INTO publications(id, course_id, title, description,tags, image_id, file_id, contributor_id, status, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8,$9, NOW())
 	`
 
-	_, err := r.db.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		id,
 		req.CourseId,
 		req.Title,
 		req.Description,
-		req.Tags,
-		req.ImageID,
-		req.FileID,
+		tags.Join(names),
+		helper.NewNullString(req.ImageID),
+		helper.NewNullString(req.FileID),
 		req.ContributorID,
 		req.Status,
 	)
@@ -49,6 +65,40 @@ func (r *publicationRepo) Create(ctx context.Context, req *models.CreatePublicat
 		return "", err
 	}
 
+	joined, err := setPublicationTags(ctx, tx, id, names)
+	if err != nil {
+		return "", err
+	}
+
+	err = insertTransition(ctx, tx, &models.PublicationTransition{
+		PublicationID: id,
+		ToStatus:      req.Status,
+		ActorID:       req.ActorID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	_, err = insertRevision(ctx, tx, &models.PublicationRevision{}, &models.PublicationRevision{
+		PublicationID: id,
+		CourseId:      req.CourseId,
+		Title:         req.Title,
+		Description:   req.Description,
+		Tags:          joined,
+		ImageID:       req.ImageID,
+		FileID:        req.FileID,
+		ContributorID: req.ContributorID,
+		EditorID:      req.ActorID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	return id, nil
 }
 
@@ -66,10 +116,25 @@ func (r *publicationRepo) GetByID(ctx context.Context, req *models.PublicationPr
 		fileID      sql.NullString
 		contributor sql.NullString
 		status      sql.NullString
+		likeCount   sql.NullInt64
+		likedByMe   sql.NullBool
 		createdAt   sql.NullString
 		updatedAt   sql.NullString
 	)
 
+	var whereField = "id"
+	if len(req.FileID) > 0 {
+		whereField = "file_id"
+		req.Id = req.FileID
+	}
+
+	likedByMeColumn := "FALSE"
+	args := []interface{}{req.Id}
+	if len(req.ViewerID) > 0 {
+		args = append(args, req.ViewerID)
+		likedByMeColumn = "EXISTS(SELECT 1 FROM likes l WHERE l.publication_id = publications.id AND l.contributor_id = $2)"
+	}
+
 	query = `
 		SELECT
 			id,
@@ -81,13 +146,17 @@ func (r *publicationRepo) GetByID(ctx context.Context, req *models.PublicationPr
 			file_id,
 			contributor_id,
 			status,
+			(SELECT COUNT(*) FROM likes l WHERE l.publication_id = publications.id),
+			` + likedByMeColumn + `,
 			created_at,
 			updated_at
 		FROM publications
-		WHERE id = $1
+		WHERE ` + whereField + ` = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
 	`
 
-	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+	err := r.db.QueryRow(ctx, query, args...).Scan(
 		&id,
 		&courseId,
 		&title,
@@ -97,6 +166,8 @@ func (r *publicationRepo) GetByID(ctx context.Context, req *models.PublicationPr
 		&fileID,
 		&contributor,
 		&status,
+		&likeCount,
+		&likedByMe,
 		&createdAt,
 		&updatedAt,
 	)
@@ -115,21 +186,35 @@ func (r *publicationRepo) GetByID(ctx context.Context, req *models.PublicationPr
 		FileID:        fileID.String,
 		ContributorID: contributor.String,
 		Status:        status.String,
+		LikeCount:     int(likeCount.Int64),
+		LikedByMe:     likedByMe.Bool,
 		CreatedAt:     createdAt.String,
 		UpdatedAt:     updatedAt.String,
 	}, nil
 }
 
+// publicationSortFields are the sort_by values accepted by GetList.
+var publicationSortFields = map[string]string{
+	"title":      "title",
+	"status":     "status",
+	"like_count": "like_count",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
 func (r *publicationRepo) GetList(ctx context.Context, req *models.PublicationGetListRequest) (*models.PublicationGetListResponse, error) {
 
 	var (
-		resp   = &models.PublicationGetListResponse{}
-		query  string
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
+		resp  = &models.PublicationGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
 	)
 
+	likedByMeColumn := "FALSE"
+	if len(req.ViewerID) > 0 {
+		likedByMeColumn = "EXISTS(SELECT 1 FROM likes l WHERE l.publication_id = publications.id AND l.contributor_id = " + qb.Arg(req.ViewerID) + ")"
+	}
+
 	query = `
 		SELECT
 			COUNT(*) OVER(),
@@ -142,29 +227,42 @@ func (r *publicationRepo) GetList(ctx context.Context, req *models.PublicationGe
 			file_id,
 			contributor_id,
 			status,
+			(SELECT COUNT(*) FROM likes l WHERE l.publication_id = publications.id) AS like_count,
+			` + likedByMeColumn + `,
 			created_at,
 			updated_at
 		FROM publications
 	`
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	if len(req.SemesterID) > 0 {
+		qb.Where("course_id IN (SELECT id FROM courses WHERE semester_id = " + qb.Arg(req.SemesterID) + ")")
 	}
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
+	qb.Where("deleted_at IS NULL").
+		Search(req.Search, "title", "tags", "status").
+		Equal("course_id", req.CourseID).
+		Equal("contributor_id", req.ContributorID).
+		Equal("status", req.Status).
+		DateRange("created_at", req.DateFrom, req.DateTo)
 
-	if req.Search != "" {
-		where += ` AND (title ILIKE '%' || '` + req.Search + `' || '%' OR tags ILIKE '%' || '` + req.Search + `' || '%' OR status ILIKE '%' || '` + req.Search + `' || '%')`
+	if len(req.Tags) > 0 {
+		qb.Where(tagCondition(qb, "id", req.Tags, tags.Match(req.TagMatch)))
 	}
 
-	query += where + offset + limit
+	qb.OrderBy(req.SortBy, req.Order, publicationSortFields, "created_at").
+		Paginate(req.Offset, req.Limit)
 
-	rows, err := r.db.Query(ctx, query)
+	query, args := qb.Build(query)
+
+	if err := qb.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -177,6 +275,8 @@ func (r *publicationRepo) GetList(ctx context.Context, req *models.PublicationGe
 			fileID      sql.NullString
 			contributor sql.NullString
 			status      sql.NullString
+			likeCount   sql.NullInt64
+			likedByMe   sql.NullBool
 			createdAt   sql.NullString
 			updatedAt   sql.NullString
 		)
@@ -192,6 +292,8 @@ func (r *publicationRepo) GetList(ctx context.Context, req *models.PublicationGe
 			&fileID,
 			&contributor,
 			&status,
+			&likeCount,
+			&likedByMe,
 			&createdAt,
 			&updatedAt,
 		)
@@ -210,21 +312,54 @@ func (r *publicationRepo) GetList(ctx context.Context, req *models.PublicationGe
 			FileID:        fileID.String,
 			ContributorID: contributor.String,
 			Status:        status.String,
+			LikeCount:     int(likeCount.Int64),
+			LikedByMe:     likedByMe.Bool,
 			CreatedAt:     createdAt.String,
 			UpdatedAt:     updatedAt.String,
 		})
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
+// Update overwrites the editable fields of a publication and records the
+// result as a new revision when anything changed. With req.Review set, a
+// content change sends a published or rejected publication back to review.
+// Nothing is updated when req.OwnerID is set and isn't the contributor.
 func (r *publicationRepo) Update(ctx context.Context, req *models.UpdatePublication) (int64, error) {
 
 	var (
 		query  string
 		params map[string]interface{}
+		names  = tags.Split(req.Tags)
 	)
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockContent(ctx, tx, req.Id)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	// The row is locked now, so a review can't change the status between
+	// this read and the end of the edit
+	var from string
+	err = tx.QueryRow(ctx, "SELECT status FROM publications WHERE id = $1", req.Id).Scan(&from)
+	if err != nil {
+		return 0, err
+	}
+
 	query = `
 		UPDATE
 			publications
@@ -236,7 +371,6 @@ func (r *publicationRepo) Update(ctx context.Context, req *models.UpdatePublicat
 			image_id = :image_id,
 			file_id = :file_id,
 			contributor_id = :contributor_id,
-			status = :status,
 			updated_at = NOW()
 		WHERE id = :id
 	`
@@ -246,16 +380,72 @@ func (r *publicationRepo) Update(ctx context.Context, req *models.UpdatePublicat
 		"course_id":      req.CourseId,
 		"title":          req.Title,
 		"description":    req.Description,
-		"tags":           req.Tags,
-		"image_id":       req.ImageID,
-		"file_id":        req.FileID,
+		"tags":           tags.Join(names),
+		"image_id":       helper.NewNullString(req.ImageID),
+		"file_id":        helper.NewNullString(req.FileID),
 		"contributor_id": req.ContributorID,
-		"status":         req.Status,
+	}
+
+	if len(req.OwnerID) > 0 {
+		query += " AND contributor_id = :owner_id"
+		params["owner_id"] = req.OwnerID
 	}
 
 	query, args := helper.ReplaceQueryParams(query, params)
 
-	result, err := r.db.Exec(ctx, query, args...)
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, nil
+	}
+
+	joined, err := setPublicationTags(ctx, tx, req.Id, names)
+	if err != nil {
+		return 0, err
+	}
+
+	next := &models.PublicationRevision{
+		PublicationID: req.Id,
+		CourseId:      req.CourseId,
+		Title:         req.Title,
+		Description:   req.Description,
+		Tags:          joined,
+		ImageID:       req.ImageID,
+		FileID:        req.FileID,
+		ContributorID: req.ContributorID,
+		EditorID:      req.EditorID,
+	}
+
+	changed := len(revisionChanges(current, next)) > 0
+	if changed {
+		_, err = insertRevision(ctx, tx, current, next)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	to := moderation.Edited(moderation.Status(from))
+	if req.Review && changed && to != moderation.Status(from) {
+		_, err = tx.Exec(ctx, "UPDATE publications SET status = $2 WHERE id = $1", req.Id, string(to))
+		if err != nil {
+			return 0, err
+		}
+
+		err = insertTransition(ctx, tx, &models.PublicationTransition{
+			PublicationID: req.Id,
+			FromStatus:    from,
+			ToStatus:      string(to),
+			ActorID:       req.EditorID,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -263,25 +453,272 @@ func (r *publicationRepo) Update(ctx context.Context, req *models.UpdatePublicat
 	return result.RowsAffected(), nil
 }
 
-func (r *publicationRepo) Delete(ctx context.Context, req *models.PublicationPrimaryKey) error {
+// Transition moves a publication to another moderation status and records
+// the change. The current status is locked, so concurrent reviews can't
+// both succeed: the second one sees the new status and fails the check.
+// Decisions are refused while another moderator holds a claim. With
+// req.OwnerID set, a publication of someone else is not found.
+func (r *publicationRepo) Transition(ctx context.Context, req *models.PublicationTransitionRequest) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		from  string
+		query = "SELECT status FROM publications WHERE id = $1 AND deleted_at IS NULL"
+		args  = []interface{}{req.Id}
+	)
 
-	_, err := r.db.Exec(ctx, "DELETE FROM publications WHERE id = $1", req.Id)
+	if len(req.OwnerID) > 0 {
+		query += " AND contributor_id = $2"
+		args = append(args, req.OwnerID)
+	}
+
+	err = tx.QueryRow(ctx, query+" FOR UPDATE", args...).Scan(&from)
+	if err != nil {
+		return err
+	}
+
+	err = moderation.Check(moderation.Status(from), moderation.Status(req.To), req.Reason)
+	if err != nil {
+		return err
+	}
+
+	if moderation.Decision(moderation.Status(req.To)) {
+		var holder string
+		err = tx.QueryRow(ctx,
+			"SELECT moderator_id FROM publication_claims WHERE publication_id = $1 AND expires_at > NOW()",
+			req.Id,
+		).Scan(&holder)
+		if err != nil && err.Error() != "no rows in result set" {
+			return err
+		}
+		if err == nil && holder != req.ActorID {
+			return moderation.ErrClaimed
+		}
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE publications SET status = $2, updated_at = NOW() WHERE id = $1", req.Id, req.To)
+	if err != nil {
+		return err
+	}
+
+	// A claim only makes sense while the publication waits for review
+	if moderation.Status(from) == moderation.PendingReview {
+		_, err = tx.Exec(ctx, "DELETE FROM publication_claims WHERE publication_id = $1", req.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = insertTransition(ctx, tx, &models.PublicationTransition{
+		PublicationID: req.Id,
+		FromStatus:    from,
+		ToStatus:      req.To,
+		ActorID:       req.ActorID,
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func insertTransition(ctx context.Context, tx pgx.Tx, req *models.PublicationTransition) error {
+
+	query := `
+		INSERT INTO publication_transitions(id, publication_id, from_status, to_status, actor_id, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := tx.Exec(ctx, query,
+		uuid.New().String(),
+		req.PublicationID,
+		helper.NewNullString(req.FromStatus),
+		req.ToStatus,
+		helper.NewNullString(req.ActorID),
+		helper.NewNullString(req.Reason),
+	)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// GetTransitions returns the moderation history of a publication, oldest
+// first.
+func (r *publicationRepo) GetTransitions(ctx context.Context, req *models.PublicationPrimaryKey) ([]*models.PublicationTransition, error) {
+
+	var resp []*models.PublicationTransition
+
+	query := `
+		SELECT
+			id,
+			publication_id,
+			from_status,
+			to_status,
+			actor_id,
+			reason,
+			created_at
+		FROM publication_transitions
+		WHERE publication_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id            sql.NullString
+			publicationID sql.NullString
+			fromStatus    sql.NullString
+			toStatus      sql.NullString
+			actorID       sql.NullString
+			reason        sql.NullString
+			createdAt     sql.NullString
+		)
+
+		err := rows.Scan(
+			&id,
+			&publicationID,
+			&fromStatus,
+			&toStatus,
+			&actorID,
+			&reason,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp = append(resp, &models.PublicationTransition{
+			Id:            id.String,
+			PublicationID: publicationID.String,
+			FromStatus:    fromStatus.String,
+			ToStatus:      toStatus.String,
+			ActorID:       actorID.String,
+			Reason:        reason.String,
+			CreatedAt:     createdAt.String,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Delete moves a publication to the trash. With req.OwnerID set, only a
+// publication contributed by that user is moved.
+func (r *publicationRepo) Delete(ctx context.Context, req *models.PublicationPrimaryKey) (int64, error) {
+
+	var (
+		query = "UPDATE publications SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL"
+		args  = []interface{}{req.Id}
+	)
+
+	if len(req.OwnerID) > 0 {
+		query += " AND contributor_id = $2"
+		args = append(args, req.OwnerID)
+	}
+
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// FindDuplicates lists publications whose file has the same content as
+// req.FileID. Files without a known hash never match.
+func (r *publicationRepo) FindDuplicates(ctx context.Context, req *models.PublicationDuplicateRequest) ([]*models.PublicationDuplicate, error) {
+
+	var (
+		resp  []*models.PublicationDuplicate
+		query string
+		qb    = helper.NewQueryBuilder()
+	)
+
+	query = `
+		SELECT
+			p.id,
+			p.course_id,
+			p.title,
+			p.file_id
+		FROM publications p
+		JOIN files f ON f.id = p.file_id
+	`
+
+	qb.Where("p.deleted_at IS NULL").
+		Where("f.sha256 = (SELECT sha256 FROM files WHERE id = "+qb.Arg(req.FileID)+")").
+		Equal("p.course_id", req.CourseID)
+
+	if len(req.ExcludeID) > 0 {
+		qb.Where("p.id <> " + qb.Arg(req.ExcludeID))
+	}
+
+	query, args := qb.Build(query)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id       sql.NullString
+			courseId sql.NullString
+			title    sql.NullString
+			fileID   sql.NullString
+		)
+
+		err := rows.Scan(
+			&id,
+			&courseId,
+			&title,
+			&fileID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp = append(resp, &models.PublicationDuplicate{
+			Id:       id.String,
+			CourseId: courseId.String,
+			Title:    title.String,
+			FileID:   fileID.String,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (r *publicationRepo) GetPublicationStats(ctx context.Context, publicationID string) (*models.PublicationStats, error) {
 	var (
-		likeCount     sql.NullFloat64
-		downloadCount sql.NullFloat64
+		likeCount     sql.NullInt64
+		downloadCount sql.NullInt64
 	)
 
 	query := `
         SELECT
-            (SELECT count FROM likes WHERE publication_id = $1) AS like_count,
-            (SELECT count FROM downloads WHERE publication_id = $1) AS download_count;
+            (SELECT COUNT(*) FROM likes WHERE publication_id = $1) AS like_count,
+            (SELECT COUNT(*) FROM downloads WHERE publication_id = $1) AS download_count;
     `
 
 	err := r.db.QueryRow(ctx, query, publicationID).Scan(
@@ -293,44 +730,96 @@ func (r *publicationRepo) GetPublicationStats(ctx context.Context, publicationID
 	}
 
 	return &models.PublicationStats{
-		LikeCount:     (likeCount.Float64),
-		DownloadCount: (downloadCount.Float64),
+		LikeCount:     int(likeCount.Int64),
+		DownloadCount: int(downloadCount.Int64),
 	}, nil
 }
-func (r *publicationRepo) GetPublicationsByTag(ctx context.Context, tag string) ([]*models.Publication, error) {
-	var publications []*models.Publication
+
+// GetPublicationsByTag returns the published publications carrying the
+// tags, newest first. Tags match exactly, after normalization.
+func (r *publicationRepo) GetPublicationsByTag(ctx context.Context, req *models.PublicationTagRequest) ([]*models.Publication, error) {
+	var (
+		publications []*models.Publication
+		qb           = helper.NewQueryBuilder()
+	)
+
+	qb.Where("status = 'published'").
+		Where("deleted_at IS NULL").
+		Where(tagCondition(qb, "id", req.Tags, tags.Match(req.Match)))
 
 	query := `
-		SELECT id, course_id, title, description, tags, image_id, file_id, contributor_id, status
+		SELECT
+			id,
+			course_id,
+			title,
+			description,
+			tags,
+			image_id,
+			file_id,
+			contributor_id,
+			status,
+			created_at,
+			updated_at
 		FROM publications
-		WHERE tags ILIKE '%' || $1 || '%';
+	` + qb.WhereClause() + `
+		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, tag)
+	rows, err := r.db.Query(ctx, query, qb.Args()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get publications by tag: %v", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var publication models.Publication
+		var (
+			id          sql.NullString
+			courseId    sql.NullString
+			title       sql.NullString
+			description sql.NullString
+			tags        sql.NullString
+			imageID     sql.NullString
+			fileID      sql.NullString
+			contributor sql.NullString
+			status      sql.NullString
+			createdAt   sql.NullString
+			updatedAt   sql.NullString
+		)
+
 		err := rows.Scan(
-			&publication.Id,
-			&publication.CourseId,
-			&publication.Title,
-			&publication.Description,
-			&publication.Tags,
-			&publication.ImageID,
-			&publication.FileID,
-			&publication.ContributorID,
-			&publication.Status,
-			// &publication.CreatedAt,
-			// &publication.UpdatedAt,
+			&id,
+			&courseId,
+			&title,
+			&description,
+			&tags,
+			&imageID,
+			&fileID,
+			&contributor,
+			&status,
+			&createdAt,
+			&updatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
-		publications = append(publications, &publication)
+
+		publications = append(publications, &models.Publication{
+			Id:            id.String,
+			CourseId:      courseId.String,
+			Title:         title.String,
+			Description:   description.String,
+			Tags:          tags.String,
+			ImageID:       imageID.String,
+			FileID:        fileID.String,
+			ContributorID: contributor.String,
+			Status:        status.String,
+			CreatedAt:     createdAt.String,
+			UpdatedAt:     updatedAt.String,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return publications, nil
@@ -0,0 +1,388 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/tags"
+)
+
+// revisionFields are the publication fields a revision keeps, in the order
+// changes are reported.
+var revisionFields = []struct {
+	name  string
+	value func(*models.PublicationRevision) string
+}{
+	{"course_id", func(r *models.PublicationRevision) string { return r.CourseId }},
+	{"title", func(r *models.PublicationRevision) string { return r.Title }},
+	{"description", func(r *models.PublicationRevision) string { return r.Description }},
+	{"tags", func(r *models.PublicationRevision) string { return r.Tags }},
+	{"image_id", func(r *models.PublicationRevision) string { return r.ImageID }},
+	{"file_id", func(r *models.PublicationRevision) string { return r.FileID }},
+	{"contributor_id", func(r *models.PublicationRevision) string { return r.ContributorID }},
+}
+
+// revisionChanges lists the fields that differ between two snapshots.
+func revisionChanges(from, to *models.PublicationRevision) []*models.FieldChange {
+
+	var changes []*models.FieldChange
+
+	for _, field := range revisionFields {
+		if before, after := field.value(from), field.value(to); before != after {
+			changes = append(changes, &models.FieldChange{
+				Field: field.name,
+				From:  before,
+				To:    after,
+			})
+		}
+	}
+
+	return changes
+}
+
+const revisionColumns = `
+	id,
+	publication_id,
+	revision,
+	course_id,
+	title,
+	description,
+	tags,
+	image_id,
+	file_id,
+	contributor_id,
+	changed_fields,
+	previous_file_id,
+	editor_id,
+	rollback_of,
+	created_at
+`
+
+func scanRevision(row pgx.Row) (*models.PublicationRevision, error) {
+
+	var (
+		id             sql.NullString
+		publicationID  sql.NullString
+		revision       sql.NullInt64
+		courseId       sql.NullString
+		title          sql.NullString
+		description    sql.NullString
+		tags           sql.NullString
+		imageID        sql.NullString
+		fileID         sql.NullString
+		contributor    sql.NullString
+		changedFields  []string
+		previousFileID sql.NullString
+		editorID       sql.NullString
+		rollbackOf     sql.NullInt64
+		createdAt      sql.NullString
+	)
+
+	err := row.Scan(
+		&id,
+		&publicationID,
+		&revision,
+		&courseId,
+		&title,
+		&description,
+		&tags,
+		&imageID,
+		&fileID,
+		&contributor,
+		&changedFields,
+		&previousFileID,
+		&editorID,
+		&rollbackOf,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PublicationRevision{
+		Id:             id.String,
+		PublicationID:  publicationID.String,
+		Revision:       int(revision.Int64),
+		CourseId:       courseId.String,
+		Title:          title.String,
+		Description:    description.String,
+		Tags:           tags.String,
+		ImageID:        imageID.String,
+		FileID:         fileID.String,
+		ContributorID:  contributor.String,
+		ChangedFields:  changedFields,
+		PreviousFileID: previousFileID.String,
+		EditorID:       editorID.String,
+		RollbackOf:     int(rollbackOf.Int64),
+		CreatedAt:      createdAt.String,
+	}, nil
+}
+
+// lockContent locks a publication and returns its editable fields as a
+// snapshot.
+func lockContent(ctx context.Context, tx pgx.Tx, id string) (*models.PublicationRevision, error) {
+
+	var (
+		courseId    sql.NullString
+		title       sql.NullString
+		description sql.NullString
+		tags        sql.NullString
+		imageID     sql.NullString
+		fileID      sql.NullString
+		contributor sql.NullString
+	)
+
+	query := `
+		SELECT course_id, title, description, tags, image_id, file_id, contributor_id
+		FROM publications
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err := tx.QueryRow(ctx, query, id).Scan(
+		&courseId,
+		&title,
+		&description,
+		&tags,
+		&imageID,
+		&fileID,
+		&contributor,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PublicationRevision{
+		PublicationID: id,
+		CourseId:      courseId.String,
+		Title:         title.String,
+		Description:   description.String,
+		Tags:          tags.String,
+		ImageID:       imageID.String,
+		FileID:        fileID.String,
+		ContributorID: contributor.String,
+	}, nil
+}
+
+// insertRevision records next as the newest revision of its publication,
+// listing what changed since previous. The publication must be locked (or
+// just created) by tx so revision numbers don't collide.
+func insertRevision(ctx context.Context, tx pgx.Tx, previous, next *models.PublicationRevision) (int, error) {
+
+	var changed = []string{}
+	for _, change := range revisionChanges(previous, next) {
+		changed = append(changed, change.Field)
+	}
+
+	var previousFileID string
+	if previous.FileID != next.FileID {
+		previousFileID = previous.FileID
+	}
+
+	query := `
+		INSERT INTO publication_revisions(
+			id, publication_id, revision, course_id, title, description, tags,
+			image_id, file_id, contributor_id, changed_fields, previous_file_id,
+			editor_id, rollback_of
+		)
+		SELECT
+			$1, $2,
+			COALESCE((SELECT MAX(revision) FROM publication_revisions WHERE publication_id = $2), 0) + 1,
+			$3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		RETURNING revision
+	`
+
+	var revision int
+	err := tx.QueryRow(ctx, query,
+		uuid.New().String(),
+		next.PublicationID,
+		helper.NewNullString(next.CourseId),
+		next.Title,
+		next.Description,
+		next.Tags,
+		helper.NewNullString(next.ImageID),
+		helper.NewNullString(next.FileID),
+		helper.NewNullString(next.ContributorID),
+		changed,
+		helper.NewNullString(previousFileID),
+		helper.NewNullString(next.EditorID),
+		sql.NullInt64{Int64: int64(next.RollbackOf), Valid: next.RollbackOf > 0},
+	).Scan(&revision)
+	if err != nil {
+		return 0, err
+	}
+
+	return revision, nil
+}
+
+// GetRevisions returns the revisions of a publication, newest first.
+func (r *publicationRepo) GetRevisions(ctx context.Context, req *models.PublicationPrimaryKey) ([]*models.PublicationRevision, error) {
+
+	var resp []*models.PublicationRevision
+
+	query := `
+		SELECT ` + revisionColumns + `
+		FROM publication_revisions
+		WHERE publication_id = $1
+		ORDER BY revision DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetRevision returns one revision, the latest when req.Revision is 0.
+func (r *publicationRepo) GetRevision(ctx context.Context, req *models.PublicationRevisionPrimaryKey) (*models.PublicationRevision, error) {
+
+	query := `
+		SELECT ` + revisionColumns + `
+		FROM publication_revisions
+		WHERE publication_id = $1 AND ($2 = 0 OR revision = $2)
+		ORDER BY revision DESC
+		LIMIT 1
+	`
+
+	return scanRevision(r.db.QueryRow(ctx, query, req.PublicationID, req.Revision))
+}
+
+// GetRevisionDiff compares two revisions of a publication field by field.
+func (r *publicationRepo) GetRevisionDiff(ctx context.Context, req *models.PublicationRevisionDiffRequest) (*models.PublicationRevisionDiff, error) {
+
+	to, err := r.GetRevision(ctx, &models.PublicationRevisionPrimaryKey{
+		PublicationID: req.PublicationID,
+		Revision:      req.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		fromRevision = req.From
+		from         = &models.PublicationRevision{}
+	)
+
+	if fromRevision <= 0 {
+		fromRevision = to.Revision - 1
+	}
+
+	if fromRevision > 0 {
+		from, err = r.GetRevision(ctx, &models.PublicationRevisionPrimaryKey{
+			PublicationID: req.PublicationID,
+			Revision:      fromRevision,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.PublicationRevisionDiff{
+		PublicationID: req.PublicationID,
+		From:          fromRevision,
+		To:            to.Revision,
+		Changes:       revisionChanges(from, to),
+	}, nil
+}
+
+// Rollback restores the content of an earlier revision. The restored
+// content becomes a new revision, history is never rewritten. Returns the
+// new revision number, 0 when the publication already has that content.
+func (r *publicationRepo) Rollback(ctx context.Context, req *models.PublicationRollback) (int, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockContent(ctx, tx, req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	target, err := scanRevision(tx.QueryRow(ctx, `
+		SELECT `+revisionColumns+`
+		FROM publication_revisions
+		WHERE publication_id = $1 AND revision = $2
+	`, req.Id, req.Revision))
+	if err != nil {
+		return 0, err
+	}
+
+	// Revisions older than the tag catalog hold the tags as typed
+	names := tags.Split(target.Tags)
+	target.Tags = tags.Join(names)
+
+	if len(revisionChanges(current, target)) <= 0 {
+		return 0, nil
+	}
+
+	query := `
+		UPDATE publications
+		SET
+			course_id = $2,
+			title = $3,
+			description = $4,
+			tags = $5,
+			image_id = $6,
+			file_id = $7,
+			contributor_id = $8,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err = tx.Exec(ctx, query,
+		req.Id,
+		helper.NewNullString(target.CourseId),
+		target.Title,
+		target.Description,
+		target.Tags,
+		helper.NewNullString(target.ImageID),
+		helper.NewNullString(target.FileID),
+		helper.NewNullString(target.ContributorID),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = setPublicationTags(ctx, tx, req.Id, names)
+	if err != nil {
+		return 0, err
+	}
+
+	target.EditorID = req.EditorID
+	target.RollbackOf = target.Revision
+
+	revision, err := insertRevision(ctx, tx, current, target)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return revision, nil
+}
@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	uuid "github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -63,7 +62,7 @@ func (r *semesterRepo) GetByID(ctx context.Context, req *models.SemesterPrimaryK
 			created_at,
 			updated_at
 		FROM semesters
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	err := r.db.QueryRow(ctx, query, req.Id).Scan(
@@ -85,14 +84,19 @@ func (r *semesterRepo) GetByID(ctx context.Context, req *models.SemesterPrimaryK
 	}, nil
 }
 
+// semesterSortFields are the sort_by values accepted by GetList.
+var semesterSortFields = map[string]string{
+	"semester_number": "semester_number",
+	"created_at":      "created_at",
+	"updated_at":      "updated_at",
+}
+
 func (r *semesterRepo) GetList(ctx context.Context, req *models.SemesterGetListRequest) (*models.SemesterGetListResponse, error) {
 
 	var (
-		resp   = &models.SemesterGetListResponse{}
-		query  string
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
+		resp  = &models.SemesterGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
 	)
 
 	query = `
@@ -105,24 +109,23 @@ func (r *semesterRepo) GetList(ctx context.Context, req *models.SemesterGetListR
 		FROM semesters
 	`
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
+	qb.Where("deleted_at IS NULL").
+		Search(req.Search, "semester_number").
+		DateRange("created_at", req.DateFrom, req.DateTo).
+		OrderBy(req.SortBy, req.Order, semesterSortFields, "created_at").
+		Paginate(req.Offset, req.Limit)
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
+	query, args := qb.Build(query)
 
-	if req.Search != "" {
-		where += ` AND semester_number ILIKE '%' || '` + req.Search + `' || '%'`
+	if err := qb.Err(); err != nil {
+		return nil, err
 	}
 
-	query += where + offset + limit
-
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -152,6 +155,10 @@ func (r *semesterRepo) GetList(ctx context.Context, req *models.SemesterGetListR
 		})
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
@@ -168,7 +175,7 @@ func (r *semesterRepo) Update(ctx context.Context, req *models.UpdateSemester) (
 		SET
 			semester_number = :semester_number,
 			updated_at = NOW()
-		WHERE id = :id
+		WHERE id = :id AND deleted_at IS NULL
 	`
 
 	params = map[string]interface{}{
@@ -186,12 +193,41 @@ func (r *semesterRepo) Update(ctx context.Context, req *models.UpdateSemester) (
 	return result.RowsAffected(), nil
 }
 
+// Delete moves a semester to the trash with its courses and their
+// publications. They all get the same deleted_at, the transaction time,
+// which is how restoring the semester finds them again.
 func (r *semesterRepo) Delete(ctx context.Context, req *models.SemesterPrimaryKey) error {
 
-	_, err := r.db.Exec(ctx, "DELETE FROM semesters WHERE id = $1", req.Id)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, "UPDATE semesters SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", req.Id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() <= 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE publications SET deleted_at = NOW()
+		WHERE deleted_at IS NULL
+			AND course_id IN (SELECT id FROM courses WHERE semester_id = $1 AND deleted_at IS NULL)
+	`
+
+	_, err = tx.Exec(ctx, query, req.Id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE courses SET deleted_at = NOW() WHERE semester_id = $1 AND deleted_at IS NULL", req.Id)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+type sessionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepo(db *pgxpool.Pool) *sessionRepo {
+	return &sessionRepo{
+		db: db,
+	}
+}
+
+func (r *sessionRepo) Create(ctx context.Context, req *models.CreateSession) (string, error) {
+	var (
+		id    = uuid.New().String()
+		query string
+	)
+
+	query = `
+		INSERT INTO sessions(id, user_id, role, refresh_token_hash, user_agent, ip_address, expires_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW() + make_interval(secs => $7), NOW())
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		id,
+		req.UserID,
+		req.Role,
+		req.RefreshTokenHash,
+		req.UserAgent,
+		req.IPAddress,
+		req.ExpiresIn,
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (r *sessionRepo) GetByID(ctx context.Context, req *models.SessionPrimaryKey) (*models.Session, error) {
+
+	var (
+		query string
+
+		id               sql.NullString
+		userID           sql.NullString
+		role             sql.NullString
+		refreshTokenHash sql.NullString
+		userAgent        sql.NullString
+		ipAddress        sql.NullString
+		expiresAt        sql.NullString
+		revoked          sql.NullBool
+		expired          sql.NullBool
+		createdAt        sql.NullString
+		updatedAt        sql.NullString
+	)
+
+	query = `
+		SELECT
+			id,
+			user_id,
+			role,
+			refresh_token_hash,
+			user_agent,
+			ip_address,
+			expires_at,
+			revoked_at IS NOT NULL,
+			expires_at <= NOW(),
+			created_at,
+			updated_at
+		FROM sessions
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&id,
+		&userID,
+		&role,
+		&refreshTokenHash,
+		&userAgent,
+		&ipAddress,
+		&expiresAt,
+		&revoked,
+		&expired,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Session{
+		Id:               id.String,
+		UserID:           userID.String,
+		Role:             role.String,
+		RefreshTokenHash: refreshTokenHash.String,
+		UserAgent:        userAgent.String,
+		IPAddress:        ipAddress.String,
+		ExpiresAt:        expiresAt.String,
+		Revoked:          revoked.Bool,
+		Expired:          expired.Bool,
+		CreatedAt:        createdAt.String,
+		UpdatedAt:        updatedAt.String,
+	}, nil
+}
+
+// Rotate swaps the refresh token hash of an active session. It affects no
+// rows when the presented hash is stale, so a replayed token can't rotate.
+func (r *sessionRepo) Rotate(ctx context.Context, req *models.RotateSession) (int64, error) {
+
+	query := `
+		UPDATE
+			sessions
+		SET
+			refresh_token_hash = $3,
+			expires_at = NOW() + make_interval(secs => $4),
+			updated_at = NOW()
+		WHERE id = $1 AND refresh_token_hash = $2 AND revoked_at IS NULL AND expires_at > NOW()
+	`
+
+	result, err := r.db.Exec(ctx, query, req.Id, req.OldRefreshTokenHash, req.NewRefreshTokenHash, req.ExpiresIn)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *sessionRepo) Revoke(ctx context.Context, req *models.SessionPrimaryKey) error {
+
+	_, err := r.db.Exec(ctx, "UPDATE sessions SET revoked_at = NOW(), updated_at = NOW() WHERE id = $1 AND revoked_at IS NULL", req.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *sessionRepo) RevokeAllByUser(ctx context.Context, userID string) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "UPDATE sessions SET revoked_at = NOW(), updated_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL", userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
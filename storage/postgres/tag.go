@@ -0,0 +1,412 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/tags"
+)
+
+type tagRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTagRepo(db *pgxpool.Pool) *tagRepo {
+	return &tagRepo{
+		db: db,
+	}
+}
+
+// tagCondition keeps the publications whose id is in column and that
+// carry all of names, or any of them with tags.MatchAny. names must be
+// normalized and distinct.
+func tagCondition(qb *helper.QueryBuilder, column string, names []string, match tags.Match) string {
+
+	condition := column + ` IN (
+		SELECT pt.publication_id
+		FROM publication_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE t.name = ANY(` + qb.Arg(names) + `)`
+
+	if match != tags.MatchAny {
+		condition += " GROUP BY pt.publication_id HAVING COUNT(*) = " + qb.Arg(len(names))
+	}
+
+	return condition + ")"
+}
+
+// setPublicationTags makes names the tags of a publication, adding the
+// missing ones to the catalog, and returns them joined for the tags column.
+func setPublicationTags(ctx context.Context, tx pgx.Tx, publicationID string, names []string) (string, error) {
+
+	if names == nil {
+		names = []string{}
+	}
+
+	for _, name := range names {
+		_, err := tx.Exec(ctx, "INSERT INTO tags(id, name) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING", uuid.New().String(), name)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	query := `
+		DELETE FROM publication_tags
+		WHERE publication_id = $1
+			AND tag_id NOT IN (SELECT id FROM tags WHERE name = ANY($2))
+	`
+
+	_, err := tx.Exec(ctx, query, publicationID, names)
+	if err != nil {
+		return "", err
+	}
+
+	query = `
+		INSERT INTO publication_tags(publication_id, tag_id)
+		SELECT $1::uuid, id FROM tags WHERE name = ANY($2)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err = tx.Exec(ctx, query, publicationID, names)
+	if err != nil {
+		return "", err
+	}
+
+	return tags.Join(names), nil
+}
+
+// retagPublications rewrites the tags column of the publications from their
+// links, after a rename or merge, and records a revision for those that
+// are not in the trash. Returns how many publications were rewritten.
+func retagPublications(ctx context.Context, tx pgx.Tx, ids []string, editorID string) (int, error) {
+
+	var snapshots = map[string]*models.PublicationRevision{}
+
+	for _, id := range ids {
+		current, err := lockContent(ctx, tx, id)
+		if err != nil {
+			if err.Error() == "no rows in result set" {
+				continue
+			}
+			return 0, err
+		}
+		snapshots[id] = current
+	}
+
+	query := `
+		UPDATE publications p
+		SET
+			tags = COALESCE((
+				SELECT string_agg(t.name, ',' ORDER BY t.name COLLATE "C")
+				FROM publication_tags pt
+				JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.publication_id = p.id
+			), ''),
+			updated_at = NOW()
+		WHERE p.id = ANY($1)
+		RETURNING p.id::text, p.tags
+	`
+
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	var retagged = map[string]string{}
+	for rows.Next() {
+		var id, joined string
+		if err := rows.Scan(&id, &joined); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		retagged[id] = joined
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		current, ok := snapshots[id]
+		if !ok {
+			continue
+		}
+
+		next := *current
+		next.Tags = retagged[id]
+		next.EditorID = editorID
+
+		_, err = insertRevision(ctx, tx, current, &next)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(retagged), nil
+}
+
+// tagSortFields are the sort_by values accepted by GetList.
+var tagSortFields = map[string]string{
+	"count":      "count",
+	"name":       "name",
+	"created_at": "created_at",
+}
+
+// GetList lists the tags carried by at least one published publication,
+// most used first by default.
+func (r *tagRepo) GetList(ctx context.Context, req *models.TagGetListRequest) (*models.TagGetListResponse, error) {
+
+	var (
+		resp  = &models.TagGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			name,
+			count,
+			created_at
+		FROM (
+			SELECT t.id, t.name, t.created_at, COUNT(p.id) AS count
+			FROM tags t
+			LEFT JOIN publication_tags pt ON pt.tag_id = t.id
+			LEFT JOIN publications p ON p.id = pt.publication_id
+				AND p.status = 'published'
+				AND p.deleted_at IS NULL
+			GROUP BY t.id, t.name, t.created_at
+		) AS tags
+	`
+
+	qb.Where("count > 0")
+
+	if prefix := tags.Normalize(req.Prefix); len(prefix) > 0 {
+		qb.Where("name LIKE " + qb.Arg(helper.EscapeLike(prefix)+"%"))
+	}
+
+	qb.OrderBy(req.SortBy, req.Order, tagSortFields, "count").
+		Paginate(req.Offset, req.Limit)
+
+	query, args := qb.Build(query)
+
+	if err := qb.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id        sql.NullString
+			name      sql.NullString
+			count     sql.NullInt64
+			createdAt sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&id,
+			&name,
+			&count,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Tags = append(resp.Tags, &models.Tag{
+			Id:        id.String,
+			Name:      name.String,
+			Count:     int(count.Int64),
+			CreatedAt: createdAt.String,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// taggedPublications returns the publications carrying any of the tags.
+func taggedPublications(ctx context.Context, tx pgx.Tx, tagIDs []string) ([]string, error) {
+
+	rows, err := tx.Query(ctx, "SELECT DISTINCT publication_id::text FROM publication_tags WHERE tag_id = ANY($1)", tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Rename changes the name of a tag on every publication carrying it.
+// Renaming to the name of another tag fails with tags.ErrExists, those
+// tags are merged instead.
+func (r *tagRepo) Rename(ctx context.Context, req *models.RenameTag) (*models.TagChange, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		name      string
+		createdAt sql.NullString
+	)
+
+	err = tx.QueryRow(ctx, "SELECT name, created_at FROM tags WHERE id = $1 FOR UPDATE", req.Id).Scan(&name, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.TagChange{
+		Tag: &models.Tag{Id: req.Id, Name: req.Name, CreatedAt: createdAt.String},
+	}
+
+	if name == req.Name {
+		return resp, nil
+	}
+
+	var taken bool
+	err = tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM tags WHERE name = $1)", req.Name).Scan(&taken)
+	if err != nil {
+		return nil, err
+	}
+
+	if taken {
+		return nil, tags.ErrExists
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE tags SET name = $2, updated_at = NOW() WHERE id = $1", req.Id, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := taggedPublications(ctx, tx, []string{req.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Publications, err = retagPublications(ctx, tx, ids, req.EditorID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Merge moves the publications of the source tags to the target tag and
+// removes the sources. Fails with pgx.ErrNoRows when no source tag exists.
+func (r *tagRepo) Merge(ctx context.Context, req *models.MergeTags) (*models.TagChange, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, "SELECT id::text FROM tags WHERE name = ANY($1) AND name <> $2 FOR UPDATE", req.Sources, req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sourceIDs = append(sourceIDs, id)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(sourceIDs) <= 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	var (
+		target = &models.Tag{Name: req.Target}
+		query  = `
+			INSERT INTO tags(id, name) VALUES ($1, $2)
+			ON CONFLICT (name) DO UPDATE SET updated_at = NOW()
+			RETURNING id, created_at
+		`
+		createdAt sql.NullString
+	)
+
+	err = tx.QueryRow(ctx, query, uuid.New().String(), req.Target).Scan(&target.Id, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	target.CreatedAt = createdAt.String
+
+	ids, err := taggedPublications(ctx, tx, sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO publication_tags(publication_id, tag_id)
+		SELECT publication_id, $2::uuid FROM publication_tags WHERE tag_id = ANY($1)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err = tx.Exec(ctx, query, sourceIDs, target.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM tags WHERE id = ANY($1)", sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.TagChange{Tag: target}
+
+	resp.Publications, err = retagPublications(ctx, tx, ids, req.EditorID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
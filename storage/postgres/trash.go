@@ -0,0 +1,315 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+	"app/pkg/helper"
+	"app/pkg/trash"
+)
+
+type trashRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTrashRepo(db *pgxpool.Pool) *trashRepo {
+	return &trashRepo{
+		db: db,
+	}
+}
+
+// trashItems lists every deleted row with its kind and label.
+const trashItems = `
+	SELECT 'semester' AS kind, id::text AS id, semester_number AS label, deleted_at FROM semesters WHERE deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'course', id::text, course_title, deleted_at FROM courses WHERE deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'publication', id::text, title, deleted_at FROM publications WHERE deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'user', id::text, username, deleted_at FROM users WHERE deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'notification', id::text, message, deleted_at FROM notifications WHERE deleted_at IS NOT NULL
+`
+
+// GetList lists the trash, most recently deleted first.
+func (r *trashRepo) GetList(ctx context.Context, req *models.TrashGetListRequest) (*models.TrashGetListResponse, error) {
+
+	var (
+		resp  = &models.TrashGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
+	)
+
+	query = `
+		SELECT
+			COUNT(*) OVER(),
+			kind,
+			id,
+			label,
+			deleted_at,
+			deleted_at + make_interval(secs => ` + qb.Arg(req.Retention) + `)
+		FROM (` + trashItems + `) AS items
+	`
+
+	qb.Equal("kind", req.Kind).
+		OrderBy("deleted_at", "desc", map[string]string{"deleted_at": "deleted_at"}, "deleted_at").
+		Paginate(req.Offset, req.Limit)
+
+	query, args := qb.Build(query)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			kind      sql.NullString
+			id        sql.NullString
+			label     sql.NullString
+			deletedAt sql.NullString
+			purgeAt   sql.NullString
+		)
+
+		err := rows.Scan(
+			&resp.Count,
+			&kind,
+			&id,
+			&label,
+			&deletedAt,
+			&purgeAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Items = append(resp.Items, &models.TrashItem{
+			Kind:      kind.String,
+			Id:        id.String,
+			Label:     label.String,
+			DeletedAt: deletedAt.String,
+			PurgeAt:   purgeAt.String,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Restore takes a row out of the trash, with the children that were
+// trashed along with it. A row whose parent is still in the trash can't be
+// restored, nor can a user whose email or username was taken since. Returns
+// how many rows were restored, 0 when the row is not in the trash.
+func (r *trashRepo) Restore(ctx context.Context, req *models.TrashPrimaryKey) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var restored int64
+
+	switch trash.Kind(req.Kind) {
+	case trash.Semester:
+		restored, err = restoreSemester(ctx, tx, req.Id)
+	case trash.Course:
+		restored, err = restoreCourse(ctx, tx, req.Id)
+	case trash.Publication:
+		restored, err = restorePublication(ctx, tx, req.Id)
+	case trash.User:
+		restored, err = restoreUser(ctx, tx, req.Id)
+	case trash.Notification:
+		restored, err = restoreRows(ctx, tx, "UPDATE notifications SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", req.Id)
+	default:
+		return 0, trash.ErrInvalidKind
+	}
+	if err != nil || restored <= 0 {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return restored, nil
+}
+
+func restoreRows(ctx context.Context, tx pgx.Tx, query string, args ...interface{}) (int64, error) {
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// lockDeleted runs query, which locks a trashed row and selects when it
+// was deleted and whether something blocks its restore, such as a parent
+// in the trash. deleted is false when the row is not in the trash.
+func lockDeleted(ctx context.Context, tx pgx.Tx, query string, id string) (deletedAt time.Time, parentDeleted bool, deleted bool, err error) {
+
+	err = tx.QueryRow(ctx, query, id).Scan(&deletedAt, &parentDeleted)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return deletedAt, false, false, nil
+		}
+		return deletedAt, false, false, err
+	}
+
+	return deletedAt, parentDeleted, true, nil
+}
+
+func restoreSemester(ctx context.Context, tx pgx.Tx, id string) (int64, error) {
+
+	deletedAt, _, deleted, err := lockDeleted(ctx, tx, `
+		SELECT deleted_at, FALSE
+		FROM semesters
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		FOR UPDATE
+	`, id)
+	if err != nil || !deleted {
+		return 0, err
+	}
+
+	query := `
+		UPDATE publications SET deleted_at = NULL
+		WHERE deleted_at = $2
+			AND course_id IN (SELECT id FROM courses WHERE semester_id = $1 AND deleted_at = $2)
+	`
+
+	publications, err := restoreRows(ctx, tx, query, id, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	courses, err := restoreRows(ctx, tx, "UPDATE courses SET deleted_at = NULL WHERE semester_id = $1 AND deleted_at = $2", id, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	semesters, err := restoreRows(ctx, tx, "UPDATE semesters SET deleted_at = NULL WHERE id = $1", id)
+	if err != nil {
+		return 0, err
+	}
+
+	return publications + courses + semesters, nil
+}
+
+func restoreCourse(ctx context.Context, tx pgx.Tx, id string) (int64, error) {
+
+	deletedAt, parentDeleted, deleted, err := lockDeleted(ctx, tx, `
+		SELECT c.deleted_at, COALESCE(s.deleted_at IS NOT NULL, FALSE)
+		FROM courses c
+		LEFT JOIN semesters s ON s.id = c.semester_id
+		WHERE c.id = $1 AND c.deleted_at IS NOT NULL
+		FOR UPDATE OF c
+	`, id)
+	if err != nil || !deleted {
+		return 0, err
+	}
+
+	if parentDeleted {
+		return 0, trash.ErrParentDeleted
+	}
+
+	publications, err := restoreRows(ctx, tx, "UPDATE publications SET deleted_at = NULL WHERE course_id = $1 AND deleted_at = $2", id, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	courses, err := restoreRows(ctx, tx, "UPDATE courses SET deleted_at = NULL WHERE id = $1", id)
+	if err != nil {
+		return 0, err
+	}
+
+	return publications + courses, nil
+}
+
+func restorePublication(ctx context.Context, tx pgx.Tx, id string) (int64, error) {
+
+	_, parentDeleted, deleted, err := lockDeleted(ctx, tx, `
+		SELECT p.deleted_at, COALESCE(c.deleted_at IS NOT NULL, FALSE)
+		FROM publications p
+		LEFT JOIN courses c ON c.id = p.course_id
+		WHERE p.id = $1 AND p.deleted_at IS NOT NULL
+		FOR UPDATE OF p
+	`, id)
+	if err != nil || !deleted {
+		return 0, err
+	}
+
+	if parentDeleted {
+		return 0, trash.ErrParentDeleted
+	}
+
+	return restoreRows(ctx, tx, "UPDATE publications SET deleted_at = NULL WHERE id = $1", id)
+}
+
+func restoreUser(ctx context.Context, tx pgx.Tx, id string) (int64, error) {
+
+	// The "parent" of a user is a live user holding the same email or
+	// username, registered while this one was in the trash
+	_, taken, deleted, err := lockDeleted(ctx, tx, `
+		SELECT u.deleted_at, EXISTS (
+			SELECT 1 FROM users o
+			WHERE o.deleted_at IS NULL AND (o.email = u.email OR o.username = u.username)
+		)
+		FROM users u
+		WHERE u.id = $1 AND u.deleted_at IS NOT NULL
+		FOR UPDATE
+	`, id)
+	if err != nil || !deleted {
+		return 0, err
+	}
+
+	if taken {
+		return 0, trash.ErrTaken
+	}
+
+	return restoreRows(ctx, tx, "UPDATE users SET deleted_at = NULL WHERE id = $1", id)
+}
+
+// Purge deletes for good the rows that have been in the trash for more
+// than olderThan seconds, children first. A course or semester still
+// referenced by a row, which can only happen when that row was deleted
+// later, waits for the next purge.
+func (r *trashRepo) Purge(ctx context.Context, olderThan int64) (*models.PurgeReport, error) {
+
+	var (
+		report  = &models.PurgeReport{}
+		expired = "deleted_at < NOW() - make_interval(secs => $1)"
+	)
+
+	steps := []struct {
+		query string
+		count *int64
+	}{
+		{"DELETE FROM notifications WHERE " + expired, &report.Notifications},
+		{"DELETE FROM publications WHERE " + expired, &report.Publications},
+		{"DELETE FROM courses c WHERE " + expired + " AND NOT EXISTS (SELECT 1 FROM publications p WHERE p.course_id = c.id)", &report.Courses},
+		{"DELETE FROM semesters s WHERE " + expired + " AND NOT EXISTS (SELECT 1 FROM courses c WHERE c.semester_id = s.id)", &report.Semesters},
+		{"DELETE FROM users WHERE " + expired, &report.Users},
+	}
+
+	for _, step := range steps {
+		result, err := r.db.Exec(ctx, step.query, olderThan)
+		if err != nil {
+			return report, err
+		}
+		*step.count = result.RowsAffected()
+	}
+
+	return report, nil
+}
@@ -0,0 +1,281 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	uuid "github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"app/api/models"
+)
+
+type uploadSessionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewUploadSessionRepo(db *pgxpool.Pool) *uploadSessionRepo {
+	return &uploadSessionRepo{
+		db: db,
+	}
+}
+
+func (r *uploadSessionRepo) Create(ctx context.Context, req *models.CreateUploadSession) (string, error) {
+	var (
+		id    = uuid.New().String()
+		query string
+	)
+
+	query = `
+		INSERT INTO upload_sessions(id, owner_id, kind, filename, size, quota_reserved, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW() + make_interval(secs => $7))
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		id,
+		req.OwnerID,
+		req.Kind,
+		req.Filename,
+		req.Size,
+		req.QuotaReserved,
+		req.ExpiresIn,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// uploadSessionColumns are the columns read by scanUploadSession.
+const uploadSessionColumns = `
+	id,
+	owner_id,
+	kind,
+	filename,
+	size,
+	upload_offset,
+	quota_reserved,
+	file_id,
+	completed_at,
+	expires_at,
+	GREATEST(EXTRACT(EPOCH FROM expires_at - NOW()), 0)::BIGINT,
+	created_at,
+	updated_at
+`
+
+func (r *uploadSessionRepo) GetByID(ctx context.Context, req *models.UploadSessionPrimaryKey) (*models.UploadSession, error) {
+
+	query := `SELECT ` + uploadSessionColumns + ` FROM upload_sessions WHERE id = $1`
+
+	return scanUploadSession(r.db.QueryRow(ctx, query, req.Id))
+}
+
+// AppendChunk records a written chunk and moves the offset past it, as long
+// as nobody else moved it first and the session is still open. It returns
+// the number of sessions advanced, 0 or 1.
+func (r *uploadSessionRepo) AppendChunk(ctx context.Context, req *models.AppendUploadChunk) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE upload_sessions
+		SET
+			upload_offset = upload_offset + $3,
+			expires_at = NOW() + make_interval(secs => $4),
+			updated_at = NOW()
+		WHERE id = $1 AND upload_offset = $2 AND upload_offset + $3 <= size
+			AND completed_at IS NULL AND expires_at > NOW()
+	`
+
+	result, err := tx.Exec(ctx, query, req.SessionID, req.Offset, req.Size, req.ExpiresIn)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.RowsAffected() <= 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO upload_chunks(session_id, upload_offset, size, storage_key) VALUES ($1, $2, $3, $4)",
+		req.SessionID,
+		req.Offset,
+		req.Size,
+		req.StorageKey,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return 1, nil
+}
+
+// GetChunks returns the chunks of the session in offset order.
+func (r *uploadSessionRepo) GetChunks(ctx context.Context, req *models.UploadSessionPrimaryKey) ([]*models.UploadChunk, error) {
+
+	var resp []*models.UploadChunk
+
+	rows, err := r.db.Query(ctx,
+		"SELECT session_id, upload_offset, size, storage_key FROM upload_chunks WHERE session_id = $1 ORDER BY upload_offset",
+		req.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var chunk models.UploadChunk
+
+		err := rows.Scan(
+			&chunk.SessionID,
+			&chunk.Offset,
+			&chunk.Size,
+			&chunk.StorageKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp = append(resp, &chunk)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Complete marks a fully received session as registered. Only the first
+// caller succeeds, later ones see 0 rows affected.
+func (r *uploadSessionRepo) Complete(ctx context.Context, req *models.CompleteUploadSession) (int64, error) {
+
+	query := `
+		UPDATE upload_sessions
+		SET file_id = $2, completed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND completed_at IS NULL AND upload_offset = size
+	`
+
+	result, err := r.db.Exec(ctx, query, req.Id, req.FileID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// DeleteChunks forgets the chunk rows of the session once their blobs are gone.
+func (r *uploadSessionRepo) DeleteChunks(ctx context.Context, req *models.UploadSessionPrimaryKey) error {
+
+	_, err := r.db.Exec(ctx, "DELETE FROM upload_chunks WHERE session_id = $1", req.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *uploadSessionRepo) Delete(ctx context.Context, req *models.UploadSessionPrimaryKey) error {
+
+	_, err := r.db.Exec(ctx, "DELETE FROM upload_sessions WHERE id = $1", req.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetExpired returns up to limit sessions past their expiry, oldest first.
+func (r *uploadSessionRepo) GetExpired(ctx context.Context, limit int) ([]*models.UploadSession, error) {
+
+	var resp []*models.UploadSession
+
+	query := `SELECT ` + uploadSessionColumns + ` FROM upload_sessions WHERE expires_at <= NOW() ORDER BY expires_at LIMIT $1`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		session, err := scanUploadSession(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		resp = append(resp, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func scanUploadSession(row pgx.Row) (*models.UploadSession, error) {
+
+	var (
+		id            sql.NullString
+		ownerID       sql.NullString
+		kind          sql.NullString
+		filename      sql.NullString
+		size          sql.NullInt64
+		offset        sql.NullInt64
+		quotaReserved sql.NullInt64
+		fileID        sql.NullString
+		completedAt   sql.NullString
+		expiresAt     sql.NullString
+		expiresIn     sql.NullInt64
+		createdAt     sql.NullString
+		updatedAt     sql.NullString
+	)
+
+	err := row.Scan(
+		&id,
+		&ownerID,
+		&kind,
+		&filename,
+		&size,
+		&offset,
+		&quotaReserved,
+		&fileID,
+		&completedAt,
+		&expiresAt,
+		&expiresIn,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UploadSession{
+		Id:            id.String,
+		OwnerID:       ownerID.String,
+		Kind:          kind.String,
+		Filename:      filename.String,
+		Size:          size.Int64,
+		Offset:        offset.Int64,
+		QuotaReserved: quotaReserved.Int64,
+		FileID:        fileID.String,
+		CompletedAt:   completedAt.String,
+		ExpiresAt:     expiresAt.String,
+		ExpiresIn:     expiresIn.Int64,
+		CreatedAt:     createdAt.String,
+		UpdatedAt:     updatedAt.String,
+	}, nil
+}
@@ -76,6 +76,7 @@ func (r *userRepo) GetByID(ctx context.Context, req *models.UserPrimaryKey) (*mo
 		password      sql.NullString
 		profile_image sql.NullString
 		status        sql.NullBool
+		emailVerified sql.NullBool
 		createdAt     sql.NullString
 		updatedAt     sql.NullString
 	)
@@ -92,10 +93,11 @@ func (r *userRepo) GetByID(ctx context.Context, req *models.UserPrimaryKey) (*mo
 			password,
 			profile_image,
 			status,
+			email_verified,
 			created_at,
 			updated_at
 		FROM users
-	WHERE ` + whereField + ` = $1
+	WHERE ` + whereField + ` = $1 AND deleted_at IS NULL
 
 	`
 
@@ -110,6 +112,7 @@ func (r *userRepo) GetByID(ctx context.Context, req *models.UserPrimaryKey) (*mo
 		&password,
 		&profile_image,
 		&status,
+		&emailVerified,
 		&createdAt,
 		&updatedAt,
 	)
@@ -119,28 +122,37 @@ func (r *userRepo) GetByID(ctx context.Context, req *models.UserPrimaryKey) (*mo
 	}
 
 	return &models.User{
-		Id:           id.String,
-		StudentId:    studentId.String,
-		Name:         name.String,
-		Surname:      surname.String,
-		Email:        email.String,
-		Grade:        grade.String,
-		Username:     username.String,
-		Password:     password.String,
-		ProfileImage: profile_image.String,
-		Status:       status.Bool,
-		CreatedAt:    createdAt.String,
-		UpdatedAt:    updatedAt.String,
+		Id:            id.String,
+		StudentId:     studentId.String,
+		Name:          name.String,
+		Surname:       surname.String,
+		Email:         email.String,
+		Grade:         grade.String,
+		Username:      username.String,
+		Password:      password.String,
+		ProfileImage:  profile_image.String,
+		Status:        status.Bool,
+		EmailVerified: emailVerified.Bool,
+		CreatedAt:     createdAt.String,
+		UpdatedAt:     updatedAt.String,
 	}, nil
 }
 
+// userSortFields are the sort_by values accepted by GetList.
+var userSortFields = map[string]string{
+	"name":       "name",
+	"surname":    "surname",
+	"email":      "email",
+	"grade":      "grade",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
 func (r *userRepo) GetList(ctx context.Context, req *models.UserGetListRequest) (*models.UserGetListResponse, error) {
 	var (
-		resp   = &models.UserGetListResponse{}
-		query  string
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
+		resp  = &models.UserGetListResponse{}
+		query string
+		qb    = helper.NewQueryBuilder()
 	)
 
 	query = `
@@ -156,29 +168,30 @@ func (r *userRepo) GetList(ctx context.Context, req *models.UserGetListRequest)
 			password,
 		    profile_image,
 			status,
+			email_verified,
 			created_at,
 			updated_at
 		FROM users
 	`
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
+	qb.Where("deleted_at IS NULL").
+		Search(req.Search, "name", "surname", "email").
+		Equal("status", req.Status).
+		DateRange("created_at", req.DateFrom, req.DateTo).
+		OrderBy(req.SortBy, req.Order, userSortFields, "created_at").
+		Paginate(req.Offset, req.Limit)
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
+	query, args := qb.Build(query)
 
-	if req.Search != "" {
-		where += ` AND (name ILIKE '%' || '` + req.Search + `' || '%' OR surname ILIKE '%' || '` + req.Search + `' || '%' OR email ILIKE '%' || '` + req.Search + `' || '%')`
+	if err := qb.Err(); err != nil {
+		return nil, err
 	}
 
-	query += where + offset + limit
-
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
@@ -192,6 +205,7 @@ func (r *userRepo) GetList(ctx context.Context, req *models.UserGetListRequest)
 			password      sql.NullString
 			profile_image sql.NullString
 			status        sql.NullBool
+			emailVerified sql.NullBool
 			createdAt     sql.NullString
 			updatedAt     sql.NullString
 		)
@@ -208,6 +222,7 @@ func (r *userRepo) GetList(ctx context.Context, req *models.UserGetListRequest)
 			&password,
 			&profile_image,
 			&status,
+			&emailVerified,
 			&createdAt,
 			&updatedAt,
 		)
@@ -217,24 +232,30 @@ func (r *userRepo) GetList(ctx context.Context, req *models.UserGetListRequest)
 		}
 
 		resp.Users = append(resp.Users, &models.User{
-			Id:           id.String,
-			StudentId:    studentId.String,
-			Name:         name.String,
-			Surname:      surname.String,
-			Email:        email.String,
-			Grade:        grade.String,
-			Username:     username.String,
-			Password:     password.String,
-			ProfileImage: profile_image.String,
-			Status:       status.Bool,
-			CreatedAt:    createdAt.String,
-			UpdatedAt:    updatedAt.String,
+			Id:            id.String,
+			StudentId:     studentId.String,
+			Name:          name.String,
+			Surname:       surname.String,
+			Email:         email.String,
+			Grade:         grade.String,
+			Username:      username.String,
+			Password:      password.String,
+			ProfileImage:  profile_image.String,
+			Status:        status.Bool,
+			EmailVerified: emailVerified.Bool,
+			CreatedAt:     createdAt.String,
+			UpdatedAt:     updatedAt.String,
 		})
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
+// Update changes a user. A new email has to be verified again.
 func (r *userRepo) Update(ctx context.Context, req *models.UpdateUser) (int64, error) {
 	var (
 		query  string
@@ -249,13 +270,14 @@ func (r *userRepo) Update(ctx context.Context, req *models.UpdateUser) (int64, e
 			name = :name,
 			surname = :surname,
 			email = :email,
+			email_verified = email_verified AND email = :email,
 			grade = :grade,
 			username = :username,
-			password = :password,
+			password = COALESCE(NULLIF(:password, ''), password),
 			profile_image = :profile_image,
 			status = :status,
 			updated_at = NOW()
-		WHERE id = :id
+		WHERE id = :id AND deleted_at IS NULL
 	`
 
 	params = map[string]interface{}{
@@ -281,8 +303,59 @@ func (r *userRepo) Update(ctx context.Context, req *models.UpdateUser) (int64, e
 	return result.RowsAffected(), nil
 }
 
+// Delete moves a user to the trash. The user's publications stay, they are
+// only hidden from the statistics while the user is there.
 func (r *userRepo) Delete(ctx context.Context, req *models.UserPrimaryKey) error {
-	_, err := r.db.Exec(ctx, "DELETE FROM users WHERE id = $1", req.Id)
+	_, err := r.db.Exec(ctx, "UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", req.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepo) UpdatePassword(ctx context.Context, id string, password string) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "UPDATE users SET password = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL", id, password)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *userRepo) VerifyEmail(ctx context.Context, email string) (int64, error) {
+
+	result, err := r.db.Exec(ctx, "UPDATE users SET email_verified = true, updated_at = NOW() WHERE email = $1 AND deleted_at IS NULL", email)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// ReserveStorage adds size bytes to the storage used by the user, only if
+// the total stays within quota. It reports whether the bytes were reserved.
+func (r *userRepo) ReserveStorage(ctx context.Context, userID string, size, quota int64) (bool, error) {
+
+	query := `
+		UPDATE users
+		SET storage_used = storage_used + $2
+		WHERE id = $1 AND storage_used + $2 <= $3
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, size, quota)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// ReleaseStorage gives back size bytes of the storage used by the user.
+func (r *userRepo) ReleaseStorage(ctx context.Context, userID string, size int64) error {
+
+	_, err := r.db.Exec(ctx, "UPDATE users SET storage_used = GREATEST(storage_used - $2, 0) WHERE id = $1", userID, size)
 	if err != nil {
 		return err
 	}
@@ -293,15 +366,15 @@ func (r *userRepo) Delete(ctx context.Context, req *models.UserPrimaryKey) error
 func (r *userRepo) GetUserActivityCounts(ctx context.Context, userID string) (*models.UserActivityCounts, error) {
 	var (
 		publicationCount sql.NullInt64
-		downloadCount    sql.NullFloat64
-		likeCount        sql.NullFloat64
+		downloadCount    sql.NullInt64
+		likeCount        sql.NullInt64
 	)
 
 	query := `
         SELECT
-            (SELECT COUNT(*) FROM publications WHERE contributor_id = $1) AS publication_count,
-    (SELECT count FROM downloads WHERE contributor_id = $1) AS download_count,
-    (SELECT count FROM likes WHERE contributor_id = $1) AS like_count;
+            (SELECT COUNT(*) FROM publications WHERE contributor_id = $1 AND deleted_at IS NULL) AS publication_count,
+            (SELECT COUNT(*) FROM downloads WHERE contributor_id = $1) AS download_count,
+            (SELECT COUNT(*) FROM likes WHERE contributor_id = $1) AS like_count;
     `
 
 	err := r.db.QueryRow(ctx, query, userID).Scan(
@@ -315,8 +388,8 @@ func (r *userRepo) GetUserActivityCounts(ctx context.Context, userID string) (*m
 
 	return &models.UserActivityCounts{
 		PublicationCount: int(publicationCount.Int64),
-		DownloadCount:    (downloadCount.Float64),
-		LikeCount:        (likeCount.Float64),
+		DownloadCount:    int(downloadCount.Int64),
+		LikeCount:        int(likeCount.Int64),
 	}, nil
 }
 
@@ -329,6 +402,7 @@ func (r *userRepo) GetTopContributors(ctx context.Context) ([]*models.Userpublic
 				COUNT(p.id) AS publication_count
 			FROM publications p
 			JOIN users u ON p.contributor_id = u.id
+			WHERE p.deleted_at IS NULL AND u.deleted_at IS NULL
 			GROUP BY u.id
 			ORDER BY publication_count DESC
 		`
@@ -361,24 +435,48 @@ func (r *userRepo) GetTopContributors(ctx context.Context) ([]*models.Userpublic
 	return contributors, nil
 }
 
+// userScoresQuery scores every user by the likes and downloads their
+// publications received, averaged per publication. Each total comes from
+// its own subquery, so joining them doesn't multiply the rows.
+const userScoresQuery = `
+	SELECT
+		u.id AS user_id,
+		COALESCE(pc.publications, 0) AS publications_count,
+		COALESCE(lc.likes, 0) AS likes_count,
+		COALESCE(dc.downloads, 0) AS downloads_count,
+		ROUND((COALESCE(lc.likes, 0) + COALESCE(dc.downloads, 0)) / GREATEST(COALESCE(pc.publications, 0), 1)::numeric)::int AS score
+	FROM users u
+	LEFT JOIN (
+		SELECT contributor_id, COUNT(*) AS publications
+		FROM publications
+		WHERE deleted_at IS NULL
+		GROUP BY contributor_id
+	) pc ON pc.contributor_id = u.id
+	LEFT JOIN (
+		SELECT p.contributor_id, COUNT(*) AS likes
+		FROM likes l
+		JOIN publications p ON p.id = l.publication_id
+		WHERE p.deleted_at IS NULL
+		GROUP BY p.contributor_id
+	) lc ON lc.contributor_id = u.id
+	LEFT JOIN (
+		SELECT p.contributor_id, COUNT(*) AS downloads
+		FROM downloads d
+		JOIN publications p ON p.id = d.publication_id
+		WHERE p.deleted_at IS NULL
+		GROUP BY p.contributor_id
+	) dc ON dc.contributor_id = u.id
+	WHERE u.deleted_at IS NULL
+`
+
 func (r *userRepo) GetUserScores(ctx context.Context) ([]*models.UserScore, error) {
 	var scores []*models.UserScore
 
 	query := `
-     SELECT 
-    u.id AS user_id,
-    ROUND((COALESCE(SUM(l.count), 0) + COALESCE(SUM(d.count), 0)) / 
-          CASE 
-              WHEN COUNT(p.id) = 0 THEN 1 
-              ELSE COUNT(p.id) 
-          END) AS score  -- Round to nearest integer
-FROM users u
-LEFT JOIN publications p ON p.contributor_id = u.id
-LEFT JOIN likes l ON l.publication_id = p.id
-LEFT JOIN downloads d ON d.publication_id = p.id
-GROUP BY u.id
-ORDER BY score DESC
-    `
+		SELECT user_id, score
+		FROM (` + userScoresQuery + `) AS scores
+		ORDER BY score DESC
+	`
 
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
@@ -405,39 +503,14 @@ func (r *userRepo) GetUserRank(ctx context.Context, userID string) (*models.User
 	var rankInfo models.UserRank
 
 	query := `
-			SELECT 
-    (SELECT COUNT(*) FROM users) AS user_count,  -- Total user count (calculated separately)
-    (
-        SELECT COUNT(*) + 1  -- Calculate rank
-        FROM (
-            SELECT 
-                u.id AS user_id,
-                (COALESCE(SUM(l.count), 0) + COALESCE(SUM(d.count), 0)) / 
-                CASE 
-                    WHEN COUNT(p.id) = 0 THEN 1 
-                    ELSE COUNT(p.id) 
-                END AS score
-            FROM users u
-            LEFT JOIN publications p ON p.contributor_id = u.id
-            LEFT JOIN likes l ON l.publication_id = p.id
-            LEFT JOIN downloads d ON d.publication_id = p.id
-            GROUP BY u.id
-            ORDER BY score DESC
-        ) AS ranked_users
-        WHERE score > (
-            SELECT 
-                (COALESCE(SUM(l.count), 0) + COALESCE(SUM(d.count), 0)) / 
-                CASE 
-                    WHEN COUNT(p.id) = 0 THEN 1 
-                    ELSE COUNT(p.id) 
-                END AS score
-            FROM users u
-            LEFT JOIN publications p ON p.contributor_id = u.id
-            LEFT JOIN likes l ON l.publication_id = p.id
-            LEFT JOIN downloads d ON d.publication_id = p.id
-            WHERE u.id = $1  -- Score of the specific user
-        )
-    ) AS user_rank;
+		WITH scores AS (` + userScoresQuery + `)
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE deleted_at IS NULL) AS user_count,
+			(
+				SELECT COUNT(*) + 1
+				FROM scores
+				WHERE score > (SELECT score FROM scores WHERE user_id = $1)
+			) AS user_rank
 	`
 
 	err := r.db.QueryRow(ctx, query, userID).Scan(&rankInfo.UserCount, &rankInfo.UserRank)
@@ -453,18 +526,10 @@ func (r *userRepo) GetUserRank(ctx context.Context, userID string) (*models.User
 func (r *userRepo) GetUserStatistics(ctx context.Context, userID string) (*models.UserStatistics, error) {
 	// Query to get count of publications, likes, and downloads for a specific user
 	query := `
-        SELECT 
-            u.id AS user_id,
-            COUNT(p.id) AS publications_count,
-            COALESCE(SUM(l.count), 0) AS likes_count,
-            COALESCE(SUM(d.count), 0) AS downloads_count
-        FROM users u
-        LEFT JOIN publications p ON p.contributor_id = u.id
-        LEFT JOIN likes l ON l.publication_id = p.id
-        LEFT JOIN downloads d ON d.publication_id = p.id
-        WHERE u.id = $1
-        GROUP BY u.id;
-    `
+		SELECT user_id, publications_count, likes_count, downloads_count
+		FROM (` + userScoresQuery + `) AS scores
+		WHERE user_id = $1
+	`
 
 	// Execute the query
 	var stats models.UserStatistics
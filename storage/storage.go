@@ -15,6 +15,14 @@ type StorageI interface {
 	Download() DownloadRepoI
 	Publication() PublicationRepoI
 	Notification() NotificationRepoI
+	Session() SessionRepoI
+	Otp() OtpRepoI
+	File() FileRepoI
+	Blob() BlobRepoI
+	UploadSession() UploadSessionRepoI
+	Moderation() ModerationRepoI
+	Trash() TrashRepoI
+	Tag() TagRepoI
 }
 
 type AdminRepoI interface {
@@ -23,6 +31,7 @@ type AdminRepoI interface {
 	GetList(context.Context, *models.AdminGetListRequest) (*models.AdminGetListResponse, error)
 	Update(context.Context, *models.UpdateAdmin) (int64, error)
 	Delete(context.Context, *models.AdminPrimaryKey) error
+	UpdatePassword(ctx context.Context, id string, password string) (int64, error)
 }
 
 type UserRepoI interface {
@@ -31,6 +40,10 @@ type UserRepoI interface {
 	GetList(context.Context, *models.UserGetListRequest) (*models.UserGetListResponse, error)
 	Update(context.Context, *models.UpdateUser) (int64, error)
 	Delete(context.Context, *models.UserPrimaryKey) error
+	UpdatePassword(ctx context.Context, id string, password string) (int64, error)
+	VerifyEmail(ctx context.Context, email string) (int64, error)
+	ReserveStorage(ctx context.Context, userID string, size, quota int64) (bool, error)
+	ReleaseStorage(ctx context.Context, userID string, size int64) error
 	GetUserActivityCounts(ctx context.Context, userID string) (*models.UserActivityCounts, error)
 	GetTopContributors(ctx context.Context) ([]*models.UserpublicationCounts, error)
 	GetUserScores(ctx context.Context) ([]*models.UserScore, error)
@@ -55,28 +68,54 @@ type SemesterRepoI interface {
 }
 
 type LikeRepoI interface {
-	Create(context.Context, *models.CreateLike) (string, error)
+	Create(context.Context, *models.LikeKey) (bool, error)
 	GetByID(context.Context, *models.LikePrimaryKey) (*models.Like, error)
 	GetList(context.Context, *models.LikeGetListRequest) (*models.LikeGetListResponse, error)
-	Update(context.Context, *models.UpdateLike) (int64, error)
-	Delete(context.Context, *models.LikePrimaryKey) error
+	Delete(context.Context, *models.LikePrimaryKey) (int64, error)
+	DeleteByKey(context.Context, *models.LikeKey) (int64, error)
+	GetStatus(context.Context, *models.LikeKey) (*models.LikeStatus, error)
 }
 type DownloadRepoI interface {
-	Create(context.Context, *models.CreateDownload) (string, error)
+	Create(context.Context, *models.CreateDownload) (bool, error)
 	GetByID(context.Context, *models.DownloadPrimaryKey) (*models.Download, error)
 	GetList(context.Context, *models.DownloadGetListRequest) (*models.DownloadGetListResponse, error)
-	Update(context.Context, *models.UpdateDownload) (int64, error)
-	Delete(context.Context, *models.DownloadPrimaryKey) error
+	Delete(context.Context, *models.DownloadPrimaryKey) (int64, error)
 }
 type PublicationRepoI interface {
 	Create(context.Context, *models.CreatePublication) (string, error)
 	GetByID(context.Context, *models.PublicationPrimaryKey) (*models.Publication, error)
 	GetList(context.Context, *models.PublicationGetListRequest) (*models.PublicationGetListResponse, error)
 	Update(context.Context, *models.UpdatePublication) (int64, error)
-	Delete(context.Context, *models.PublicationPrimaryKey) error
+	Delete(context.Context, *models.PublicationPrimaryKey) (int64, error)
 	GetPublicationStats(ctx context.Context, publicationID string) (*models.PublicationStats, error)
-	GetPublicationsByTag(ctx context.Context, tag string) ([]*models.Publication, error)
+	GetPublicationsByTag(context.Context, *models.PublicationTagRequest) ([]*models.Publication, error)
+	FindDuplicates(context.Context, *models.PublicationDuplicateRequest) ([]*models.PublicationDuplicate, error)
+	Transition(context.Context, *models.PublicationTransitionRequest) error
+	GetTransitions(context.Context, *models.PublicationPrimaryKey) ([]*models.PublicationTransition, error)
+	GetRevisions(context.Context, *models.PublicationPrimaryKey) ([]*models.PublicationRevision, error)
+	GetRevision(context.Context, *models.PublicationRevisionPrimaryKey) (*models.PublicationRevision, error)
+	GetRevisionDiff(context.Context, *models.PublicationRevisionDiffRequest) (*models.PublicationRevisionDiff, error)
+	Rollback(context.Context, *models.PublicationRollback) (int, error)
 }
+type ModerationRepoI interface {
+	GetQueue(context.Context, *models.ModerationQueueRequest) (*models.ModerationQueueResponse, error)
+	Claim(context.Context, *models.ModerationClaimRequest) (*models.ModerationClaim, error)
+	Release(context.Context, *models.ModerationReleaseRequest) (int64, error)
+	GetStats(context.Context, *models.ModerationStatsRequest) (*models.ModerationStats, error)
+}
+
+type TagRepoI interface {
+	GetList(context.Context, *models.TagGetListRequest) (*models.TagGetListResponse, error)
+	Rename(context.Context, *models.RenameTag) (*models.TagChange, error)
+	Merge(context.Context, *models.MergeTags) (*models.TagChange, error)
+}
+
+type TrashRepoI interface {
+	GetList(context.Context, *models.TrashGetListRequest) (*models.TrashGetListResponse, error)
+	Restore(context.Context, *models.TrashPrimaryKey) (int64, error)
+	Purge(ctx context.Context, olderThan int64) (*models.PurgeReport, error)
+}
+
 type NotificationRepoI interface {
 	Create(context.Context, *models.CreateNotification) (string, error)
 	GetByID(context.Context, *models.NotificationPrimaryKey) (*models.Notification, error)
@@ -84,3 +123,48 @@ type NotificationRepoI interface {
 	Update(context.Context, *models.UpdateNotification) (int64, error)
 	Delete(context.Context, *models.NotificationPrimaryKey) error
 }
+
+type SessionRepoI interface {
+	Create(context.Context, *models.CreateSession) (string, error)
+	GetByID(context.Context, *models.SessionPrimaryKey) (*models.Session, error)
+	Rotate(context.Context, *models.RotateSession) (int64, error)
+	Revoke(context.Context, *models.SessionPrimaryKey) error
+	RevokeAllByUser(ctx context.Context, userID string) (int64, error)
+}
+
+type OtpRepoI interface {
+	Create(context.Context, *models.CreateOtp) (string, error)
+	GetActive(context.Context, *models.OtpPrimaryKey) (*models.Otp, error)
+	RegisterAttempt(ctx context.Context, req *models.OtpPrimaryKey, maxAttempts int) (int64, error)
+	Consume(context.Context, *models.OtpPrimaryKey) (int64, error)
+}
+
+type FileRepoI interface {
+	Create(context.Context, *models.CreateFile) (string, error)
+	GetByID(context.Context, *models.FilePrimaryKey) (*models.File, error)
+	GetList(context.Context, *models.FileGetListRequest) (*models.FileGetListResponse, error)
+	CreateVariant(context.Context, *models.CreateFileVariant) (int64, error)
+	GetVariant(context.Context, *models.FileVariantPrimaryKey) (*models.FileVariant, error)
+	GetOrphans(context.Context, *models.OrphanRequest) ([]*models.File, error)
+	DeleteOrphans(context.Context, *models.OrphanRequest) ([]*models.File, error)
+}
+
+type BlobRepoI interface {
+	Acquire(context.Context, *models.CreateBlob) (*models.Blob, error)
+	Release(context.Context, *models.BlobPrimaryKey) error
+	GetByID(context.Context, *models.BlobPrimaryKey) (*models.Blob, error)
+	GetOrphans(context.Context, *models.OrphanRequest) ([]*models.Blob, error)
+	DeleteOrphan(ctx context.Context, req *models.BlobPrimaryKey, olderThan int64, remove func(*models.Blob) error) (bool, error)
+	GetUnknownKeys(ctx context.Context, keys []string) ([]string, error)
+}
+
+type UploadSessionRepoI interface {
+	Create(context.Context, *models.CreateUploadSession) (string, error)
+	GetByID(context.Context, *models.UploadSessionPrimaryKey) (*models.UploadSession, error)
+	AppendChunk(context.Context, *models.AppendUploadChunk) (int64, error)
+	GetChunks(context.Context, *models.UploadSessionPrimaryKey) ([]*models.UploadChunk, error)
+	Complete(context.Context, *models.CompleteUploadSession) (int64, error)
+	DeleteChunks(context.Context, *models.UploadSessionPrimaryKey) error
+	Delete(context.Context, *models.UploadSessionPrimaryKey) error
+	GetExpired(ctx context.Context, limit int) ([]*models.UploadSession, error)
+}